@@ -1,18 +1,62 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/output"
 	"github.com/alvmarrod/pi-monitor-api/internal/adapters/repository"
+	"github.com/alvmarrod/pi-monitor-api/internal/config"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
 	"github.com/alvmarrod/pi-monitor-api/internal/core/services"
 
 	"github.com/gorilla/mux"
 )
 
-// RegisterV1Routes sets up the routes for version 1 of the API
-func RegisterV1Routes(r *mux.Router) {
+// Collectors holds the services shared by the routes and the background
+// outputs, so that every run is accounted for in the same registry
+type Collectors struct {
+	Registry    *services.CollectorRegistry
+	Diagnostics *services.DiagnosticsService
+	Samplers    []ports.SamplePort
+
+	CPU           *services.CPUService
+	RAM           *services.RAMService
+	Storage       *services.StorageService
+	StorageHealth *services.StorageHealthService
+	Forecast      *services.StorageForecastService
+	Mounts        *services.MountService
+	Directories   *services.DirectoryService
+	Network       *services.NetworkService
+	Protocols     *services.NetworkProtocolService
+	Sockets       *services.SocketService
+	Routes        *services.RouteService
+	Neighbors     *services.NeighborService
+	Probes        *services.ProbeService
+	System        *services.SystemService
+	Board         *services.BoardService
+	Processes     *services.ProcessService
+	Watch         *services.WatchService
+	Systemd       *services.SystemdService
+	Cgroups       *services.CgroupService
+	Power         *services.PowerService
+	Sensors       *services.SensorService
+	// Only when enabled in the configuration
+	Containers *services.ContainerService
+}
+
+// NewCollectors instantiates every repository and service
+func NewCollectors(cfg config.Config) *Collectors {
 
 	//Instantiate the real components that can be mocked during testing
 	fileReader := &repository.RealFileReader{}
@@ -23,18 +67,215 @@ func RegisterV1Routes(r *mux.Router) {
 	cpuRepo := repository.NewCPURepository(fileReader)
 	ramRepo := repository.NewRAMRepository(fileReader)
 	storageRepo := repository.NewStorageRepository(fileReader, execFinder, cmd)
+	storageHealthRepo := repository.NewStorageHealthRepository(fileReader, execFinder, cmd)
+	mountRepo := repository.NewMountRepository(fileReader, &repository.RealFilesystemStat{}, time.Duration(cfg.Mounts.Timeout))
 	networkRepo := repository.NewNetworkRepository(fileReader, execFinder, cmd)
+	protocolRepo := repository.NewNetworkProtocolRepository(fileReader)
+	socketRepo := repository.NewSocketRepository(fileReader, &repository.RealLinkReader{})
+	routeRepo := repository.NewRouteRepository(fileReader)
+	vendors, err := repository.LoadOUITable(fileReader, cfg.Neighbors.OUIFile)
+	if err != nil {
+		log.Fatalf("Invalid neighbors configuration: %v", err)
+	}
+	neighborRepo := repository.NewNeighborRepository(fileReader, execFinder, cmd, vendors)
+	systemRepo := repository.NewSystemRepository(fileReader, execFinder, cmd)
+	boardRepo := repository.NewBoardRepository(fileReader)
+	processRepo := repository.NewProcessRepository(fileReader)
+	systemdRepo := repository.NewSystemdRepository(execFinder, cmd)
+	cgroupRepo := repository.NewCgroupRepository(fileReader)
+	powerRepo := repository.NewPowerRepository(fileReader, execFinder, cmd)
+	sensorRepo := repository.NewSensorRepository(fileReader)
+
+	// Expose every collector by name for the features working on all of them,
+	// keeping the stats of their runs
+	registry := services.NewCollectorRegistry()
 
-	cpuService := services.NewCPUService(cpuRepo)
-	ramService := services.NewRAMService(ramRepo)
-	storageService := services.NewStorageService(storageRepo)
-	networkService := services.NewNetworkService(networkRepo)
+	// Optional features, like sensors or systemd, don't make the API unready
+	coreCollectors := []string{"cpu", "ram", "storage", "network"}
+
+	c := &Collectors{
+		Registry:      registry,
+		Diagnostics:   services.NewDiagnosticsService(registry).WithCore(coreCollectors),
+		CPU:           services.NewCPUService(cpuRepo).WithRecorder(registry),
+		RAM:           services.NewRAMService(ramRepo).WithRecorder(registry),
+		StorageHealth: services.NewStorageHealthService(storageHealthRepo).WithRecorder(registry),
+		Mounts: services.NewMountService(mountRepo, domain.MountFilter{
+			IncludeTypes: cfg.Mounts.IncludeTypes,
+			ExcludeTypes: cfg.Mounts.ExcludeTypes,
+			IncludePaths: cfg.Mounts.IncludePaths,
+			ExcludePaths: cfg.Mounts.ExcludePaths,
+		}).WithRecorder(registry),
+		Network:   services.NewNetworkService(networkRepo).WithRecorder(registry),
+		Protocols: services.NewNetworkProtocolService(protocolRepo).WithRecorder(registry),
+		Sockets:   services.NewSocketService(socketRepo).WithRecorder(registry),
+		Routes:    services.NewRouteService(routeRepo).WithRecorder(registry),
+		Neighbors: services.NewNeighborService(neighborRepo).WithRecorder(registry),
+		System:    services.NewSystemService(systemRepo).WithRecorder(registry),
+		Board:     services.NewBoardService(boardRepo).WithRecorder(registry),
+		Processes: services.NewProcessService(processRepo).WithRecorder(registry),
+		Systemd:   services.NewSystemdService(systemdRepo, cfg.Services.Units).WithRecorder(registry),
+		Cgroups:   services.NewCgroupService(cgroupRepo, cfg.Cgroups.Paths).WithRecorder(registry),
+		Power:     services.NewPowerService(powerRepo).WithRecorder(registry),
+		Sensors:   services.NewSensorService(sensorRepo).WithRecorder(registry),
+	}
+	// Devices carry the health of their disk, as at /v1/storage/health, read
+	// again every few minutes at most so that disks can spin down
+	c.Storage = services.NewStorageService(storageRepo, cfg.Storage.ExpectedRW).WithRecorder(registry).WithHealth(c.StorageHealth, 10*time.Minute)
+
+	rules := make([]domain.WatchRule, 0, len(cfg.Watch))
+	for _, watch := range cfg.Watch {
+		rules = append(rules, domain.WatchRule{
+			Name:    watch.Name,
+			Process: watch.Process,
+			Cmdline: watch.Cmdline,
+			Pidfile: watch.Pidfile,
+		})
+	}
+	// The watcher has a CPU snapshot of its own, not to skew the one of
+	// /v1/processes
+	watchRepo := repository.NewProcessRepository(fileReader)
+	watchService, err := services.NewWatchService(watchRepo, watchRepo, rules)
+	if err != nil {
+		log.Fatalf("Invalid watch configuration: %v", err)
+	}
+	c.Watch = watchService.WithRecorder(registry)
+
+	// Walked in the background by StartDirectoryWalker
+	directoryRules := make([]domain.DirectoryRule, 0, len(cfg.Directories.Paths))
+	directoryPaths := make([]string, 0, len(cfg.Directories.Paths))
+	for _, directory := range cfg.Directories.Paths {
+		directoryRules = append(directoryRules, domain.DirectoryRule{Path: directory.Path, SoftLimit: directory.SoftLimitBytes})
+		directoryPaths = append(directoryPaths, directory.Path)
+	}
+	directoryRepo := repository.NewDirectoryRepository(fileReader, directoryPaths, cfg.Directories.Rate, cfg.Directories.MaxEntries)
+	c.Directories = services.NewDirectoryService(directoryRepo, directoryRules, cfg.Directories.Top).WithRecorder(registry)
+	// Sampled in the background by StartStorageForecast
+	c.Forecast = services.NewStorageForecastService(storageRepo, time.Duration(cfg.Storage.Forecast.Window)).WithRecorder(registry)
+	// Run in the background by StartProbes
+	probeTargets := make([]domain.ProbeTarget, 0, len(cfg.Probes.Targets))
+	for _, probe := range cfg.Probes.Targets {
+		probeTargets = append(probeTargets, domain.ProbeTarget{
+			Name:           probe.Name,
+			Type:           probe.Type,
+			Target:         probe.Target,
+			Server:         probe.Server,
+			ExpectedStatus: probe.ExpectedStatus,
+			Interval:       time.Duration(probe.Interval),
+			Timeout:        time.Duration(probe.Timeout),
+		})
+	}
+	probeRepo := repository.NewProbeRepository()
+	c.Probes = services.NewProbeService(probeRepo, probeTargets, cfg.Probes.Window).WithRecorder(registry)
+
+	registry.Register("cpu", func() (any, error) { return c.CPU.GetCPULoad() })
+	registry.Register("ram", func() (any, error) { return c.RAM.GetRAMStats() })
+	registry.Register("storage", func() (any, error) { return c.Storage.GetDevices() })
+	registry.Register("storage_health", func() (any, error) { return c.StorageHealth.GetDevicesHealth() })
+	registry.Register("storage_forecast", func() (any, error) { return c.Forecast.GetForecasts() })
+	registry.Register("mounts", func() (any, error) { return c.Mounts.GetMounts() })
+	registry.Register("directories", func() (any, error) { return c.Directories.GetDirectories() })
+	registry.Register("network", func() (any, error) { return c.Network.GetNetworkInterfaces() })
+	registry.Register("network_protocols", func() (any, error) { return c.Protocols.GetProtocols() })
+	registry.Register("sockets", func() (any, error) { return c.Sockets.GetSockets(domain.SocketFilter{}) })
+	registry.Register("routes", func() (any, error) { return c.Routes.GetRouting() })
+	registry.Register("neighbors", func() (any, error) { return c.Neighbors.GetNeighbors() })
+	registry.Register("probes", func() (any, error) { return c.Probes.GetProbes() })
+	registry.Register("system", func() (any, error) { return c.System.GetSystemInfo() })
+	registry.Register("board", func() (any, error) { return c.Board.GetBoardInfo() })
+	registry.Register("processes", func() (any, error) { return c.Processes.GetTopProcesses("cpu", 10) })
+	registry.Register("watch", func() (any, error) { return c.Watch.GetWatchedProcesses() })
+	registry.Register("cgroups", func() (any, error) { return c.Cgroups.GetCgroups() })
+	registry.Register("power", func() (any, error) { return c.Power.GetPower() })
+	registry.Register("sensors", func() (any, error) { return c.Sensors.GetSensors() })
+
+	c.Diagnostics.AddSources("cpu", cpuRepo)
+	c.Diagnostics.AddSources("ram", ramRepo)
+	c.Diagnostics.AddSources("storage", storageRepo)
+	c.Diagnostics.AddSources("storage_health", storageHealthRepo)
+	c.Diagnostics.AddSources("storage_forecast", storageRepo)
+	c.Diagnostics.AddSources("mounts", mountRepo)
+	c.Diagnostics.AddSources("directories", directoryRepo)
+	c.Diagnostics.AddSources("network", networkRepo)
+	c.Diagnostics.AddSources("network_protocols", protocolRepo)
+	c.Diagnostics.AddSources("sockets", socketRepo)
+	c.Diagnostics.AddSources("routes", routeRepo)
+	c.Diagnostics.AddSources("neighbors", neighborRepo)
+	c.Diagnostics.AddSources("probes", probeRepo)
+	c.Diagnostics.AddSources("system", systemRepo)
+	c.Diagnostics.AddSources("board", boardRepo)
+	c.Diagnostics.AddSources("processes", processRepo)
+	c.Diagnostics.AddSources("watch", watchRepo)
+	c.Diagnostics.AddSources("cgroups", cgroupRepo)
+	c.Diagnostics.AddSources("power", powerRepo)
+	c.Diagnostics.AddSources("sensors", sensorRepo)
+
+	// Hosts without systemd would never be ready otherwise
+	if len(cfg.Services.Units) > 0 {
+		registry.Register("services", func() (any, error) { return c.Systemd.GetServices() })
+		c.Diagnostics.AddSources("services", systemdRepo)
+	}
+	if cfg.Docker.Enabled {
+		dockerRepo := repository.NewDockerRepository(cfg.Docker.Socket, time.Duration(cfg.Docker.Timeout))
+		c.Containers = services.NewContainerService(dockerRepo).WithRecorder(registry)
+		registry.Register("containers", func() (any, error) { return c.Containers.GetContainers() })
+		c.Diagnostics.AddSources("containers", dockerRepo)
+	}
+
+	c.Samplers = []ports.SamplePort{c.CPU, c.RAM, c.Storage, c.StorageHealth, c.Forecast, c.Mounts, c.Directories, c.Network, c.Protocols, c.Routes, c.Neighbors, c.Probes, c.System, c.Watch, c.Power, c.Sensors}
+	// Hosts without systemd would fail on every push otherwise
+	if len(cfg.Services.Units) > 0 {
+		c.Samplers = append(c.Samplers, c.Systemd)
+	}
+	// Nor can cgroup v1 hosts be sampled
+	if cgroupRepo.Unified() {
+		c.Samplers = append(c.Samplers, c.Cgroups)
+	}
+	if c.Containers != nil {
+		c.Samplers = append(c.Samplers, c.Containers)
+	}
+
+	return c
+}
+
+// RegisterV1Routes sets up the routes for version 1 of the API, along with
+// the unversioned health probes
+func RegisterV1Routes(r *mux.Router, cfg config.Config, c *Collectors) {
 
 	// Initialize handlers
-	cpuHandler := handler.NewCPUHandler(cpuService)
-	ramHandler := handler.NewRAMHandler(ramService)
-	storageHandler := handler.NewStorageHandler(storageService)
-	networkHandler := handler.NewNetworkHandler(networkService)
+	cpuHandler := handler.NewCPUHandler(c.CPU)
+	ramHandler := handler.NewRAMHandler(c.RAM)
+	storageHandler := handler.NewStorageHandler(c.Storage)
+	storageHealthHandler := handler.NewStorageHealthHandler(c.StorageHealth)
+	storageForecastHandler := handler.NewStorageForecastHandler(c.Forecast)
+	mountHandler := handler.NewMountHandler(c.Mounts)
+	directoryHandler := handler.NewDirectoryHandler(c.Directories)
+	networkHandler := handler.NewNetworkHandler(c.Network)
+	protocolHandler := handler.NewNetworkProtocolHandler(c.Protocols)
+	socketHandler := handler.NewSocketHandler(c.Sockets)
+	routeHandler := handler.NewRouteHandler(c.Routes)
+	neighborHandler := handler.NewNeighborHandler(c.Neighbors)
+	probeHandler := handler.NewProbeHandler(c.Probes)
+	systemHandler := handler.NewSystemHandler(c.System)
+	boardHandler := handler.NewBoardHandler(c.Board)
+	processHandler := handler.NewProcessHandler(c.Processes)
+	watchHandler := handler.NewWatchHandler(c.Watch)
+	systemdHandler := handler.NewSystemdHandler(c.Systemd)
+	cgroupHandler := handler.NewCgroupHandler(c.Cgroups)
+	powerHandler := handler.NewPowerHandler(c.Power)
+	sensorHandler := handler.NewSensorHandler(c.Sensors)
+	// The port must be nil, not a nil *ContainerService, when disabled
+	containerHandler := handler.NewContainerHandler(nil)
+	if c.Containers != nil {
+		containerHandler = handler.NewContainerHandler(c.Containers)
+	}
+	streamHandler := handler.NewStreamHandler(c.Registry,
+		time.Duration(cfg.Stream.MinInterval), time.Duration(cfg.Stream.DefaultInterval))
+	healthHandler := handler.NewHealthHandler(c.Diagnostics)
+	openAPIHandler := handler.NewOpenAPIHandler(append(handler.HealthOperations, handler.V1Operations...))
+
+	// Probes stay outside of the API versioning
+	r.HandleFunc("/healthz", healthHandler.GetHealthz).Methods("GET")
+	r.HandleFunc("/readyz", healthHandler.GetReadyz).Methods("GET")
 
 	// Create a subrouter for version 1 of the API
 	v1 := r.PathPrefix("/v1").Subrouter()
@@ -43,20 +284,157 @@ func RegisterV1Routes(r *mux.Router) {
 	v1.HandleFunc("/cpu", cpuHandler.GetCPULoad).Methods("GET")
 	v1.HandleFunc("/ram", ramHandler.GetRAMInfo).Methods("GET")
 	v1.HandleFunc("/storage", storageHandler.GetStorageInfo).Methods("GET")
+	v1.HandleFunc("/storage/health", storageHealthHandler.GetStorageHealth).Methods("GET")
+	v1.HandleFunc("/storage/forecast", storageForecastHandler.GetStorageForecast).Methods("GET")
+	v1.HandleFunc("/mounts", mountHandler.GetMounts).Methods("GET")
+	v1.HandleFunc("/directories", directoryHandler.GetDirectories).Methods("GET")
 	v1.HandleFunc("/network", networkHandler.GetNetworkInfo).Methods("GET")
+	v1.HandleFunc("/network/protocols", protocolHandler.GetProtocols).Methods("GET")
+	v1.HandleFunc("/network/sockets", socketHandler.GetSockets).Methods("GET")
+	v1.HandleFunc("/network/routes", routeHandler.GetRoutes).Methods("GET")
+	v1.HandleFunc("/network/neighbors", neighborHandler.GetNeighbors).Methods("GET")
+	v1.HandleFunc("/probes", probeHandler.GetProbes).Methods("GET")
+	v1.HandleFunc("/system", systemHandler.GetSystemInfo).Methods("GET")
+	v1.HandleFunc("/board", boardHandler.GetBoardInfo).Methods("GET")
+	v1.HandleFunc("/processes", processHandler.GetProcesses).Methods("GET")
+	v1.HandleFunc("/watch", watchHandler.GetWatchedProcesses).Methods("GET")
+	v1.HandleFunc("/services", systemdHandler.GetServices).Methods("GET")
+	v1.HandleFunc("/containers", containerHandler.GetContainers).Methods("GET")
+	v1.HandleFunc("/cgroups", cgroupHandler.GetCgroups).Methods("GET")
+	v1.HandleFunc("/power", powerHandler.GetPowerInfo).Methods("GET")
+	v1.HandleFunc("/sensors", sensorHandler.GetSensors).Methods("GET")
+	v1.HandleFunc("/stream", streamHandler.GetStream).Methods("GET")
+	v1.HandleFunc("/stream/ws", streamHandler.GetStreamWS).Methods("GET")
+	v1.HandleFunc("/diagnostics", healthHandler.GetDiagnostics).Methods("GET")
+	v1.HandleFunc("/openapi.json", openAPIHandler.GetOpenAPI).Methods("GET")
+}
+
+// StartMetricsExport pushes the samples of every collector to InfluxDB in
+// the background, if enabled, until the context is cancelled. The returned
+// channel is closed once the pending samples have been flushed.
+func StartMetricsExport(ctx context.Context, cfg *config.InfluxConfig, c *Collectors) <-chan struct{} {
+	done := make(chan struct{})
+	if cfg == nil || !cfg.Enabled {
+		close(done)
+		return done
+	}
+
+	sink, err := output.NewInfluxSink(*cfg)
+	if err != nil {
+		log.Fatalf("Invalid InfluxDB output: %v", err)
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		log.Printf("Unable to resolve hostname for metrics: %v", err)
+	}
+
+	metricsService := services.NewMetricsService(sink, host, c.Samplers...)
+
+	log.Printf("Pushing metrics to %s every %s", cfg.URL, time.Duration(cfg.Interval))
+	go func() {
+		defer close(done)
+		metricsService.Run(ctx, time.Duration(cfg.Interval))
+	}()
+	return done
+}
+
+// StartStorageForecast samples the usage of every partition in the background
+// until the context is cancelled. The returned channel is closed once it stops.
+func StartStorageForecast(ctx context.Context, cfg config.ForecastConfig, c *Collectors) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Forecast.Run(ctx, time.Duration(cfg.Interval))
+	}()
+	return done
+}
+
+// StartWatcher checks the watched processes in the background until the
+// context is cancelled. The returned channel is closed once it stops.
+func StartWatcher(ctx context.Context, cfg config.Config, c *Collectors) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Watch.Run(ctx, time.Duration(cfg.WatchInterval))
+	}()
+	return done
+}
+
+// StartDirectoryWalker walks the watched directories in the background until
+// the context is cancelled. The returned channel is closed once it stops.
+func StartDirectoryWalker(ctx context.Context, cfg config.DirectoriesConfig, c *Collectors) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Directories.Run(ctx, time.Duration(cfg.Interval))
+	}()
+	return done
+}
+
+// StartProbes runs the connectivity probes in the background, each on its
+// own schedule, until the context is cancelled. The returned channel is
+// closed once they stop.
+func StartProbes(ctx context.Context, c *Collectors) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Probes.Run(ctx)
+	}()
+	return done
 }
 
 func main() {
+	configPath := flag.String("config", os.Getenv("PI_MONITOR_CONFIG"), "path to the JSON configuration file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Unable to load configuration: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Set up the main router
 	r := mux.NewRouter()
 
 	// Register all v1 routes
-	RegisterV1Routes(r)
+	collectors := NewCollectors(cfg)
+	RegisterV1Routes(r, cfg, collectors)
+
+	// Everything else is the embedded dashboard
+	r.PathPrefix("/").Handler(handler.NewDashboardHandler()).Methods("GET")
+
+	// Start the optional background outputs
+	exportDone := StartMetricsExport(ctx, cfg.Influx, collectors)
+	forecastDone := StartStorageForecast(ctx, cfg.Storage.Forecast, collectors)
+	directoriesDone := StartDirectoryWalker(ctx, cfg.Directories, collectors)
+	probesDone := StartProbes(ctx, collectors)
+	watchDone := StartWatcher(ctx, cfg, collectors)
 
 	// Start the HTTP server
-	log.Println("Starting API server on :8080")
-	err := http.ListenAndServe(":8080", r)
-	if err != nil {
+	server := &http.Server{
+		Addr:    cfg.Listen,
+		Handler: r,
+		// Long lived streams end with the server
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Starting API server on %s", cfg.Listen)
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Server failed to start: %v", err)
 	}
+	<-exportDone
+	<-forecastDone
+	<-directoriesDone
+	<-probesDone
+	<-watchDone
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/config"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// registeredRoutes lists the "METHOD /path" of every route of the router
+func registeredRoutes(t *testing.T, r *mux.Router) []string {
+	var routes []string
+	err := r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Subrouters have no methods of their own
+			return nil
+		}
+		for _, method := range methods {
+			routes = append(routes, method+" "+path)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	sort.Strings(routes)
+	return routes
+}
+
+func unversioned(routes []string, v1Routes []string) []string {
+	var others []string
+	for _, route := range routes {
+		if !slices.Contains(v1Routes, route) {
+			others = append(others, route)
+		}
+	}
+	return others
+}
+
+func TestEveryV1RouteIsDocumented(t *testing.T) {
+	r := mux.NewRouter()
+	RegisterV1Routes(r, config.Default(), NewCollectors(config.Default()))
+
+	routes := registeredRoutes(t, r)
+	assert.NotEmpty(t, routes)
+
+	// Only the probes live outside of /v1
+	var v1Routes []string
+	for _, route := range routes {
+		if strings.Contains(route, " /v1/") {
+			v1Routes = append(v1Routes, route)
+		}
+	}
+	assert.Equal(t, handler.DocumentedRoutes(handler.HealthOperations), unversioned(routes, v1Routes))
+
+	// Both ways: undocumented routes and documentation of removed routes
+	assert.Equal(t, v1Routes, handler.DocumentedRoutes(handler.V1Operations),
+		"Every route registered in RegisterV1Routes must be described in handler.V1Operations")
+}
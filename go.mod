@@ -4,6 +4,7 @@ go 1.22.6
 
 require (
 	github.com/gorilla/mux v1.8.1
+	github.com/gorilla/websocket v1.5.3
 	github.com/stretchr/testify v1.9.0
 )
 
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type BoardHandler struct {
+	BoardService ports.BoardPort
+}
+
+func NewBoardHandler(service ports.BoardPort) *BoardHandler {
+	return &BoardHandler{BoardService: service}
+}
+
+func (h *BoardHandler) GetBoardInfo(w http.ResponseWriter, r *http.Request) {
+	boardInfo, err := h.BoardService.GetBoardInfo()
+	if err != nil {
+		log.Printf("Error retrieving board info: %v", err)
+		http.Error(w, "Failed to retrieve board info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Board info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(boardInfo)
+}
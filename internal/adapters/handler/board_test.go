@@ -0,0 +1,76 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBoardPort struct {
+	mock.Mock
+}
+
+func (m *MockBoardPort) GetBoardInfo() (domain.Board, error) {
+	args := m.Called()
+	return args.Get(0).(domain.Board), args.Error(1)
+}
+
+func TestGetBoardInfo_Success(t *testing.T) {
+
+	mockBoardPort := new(MockBoardPort)
+	boardData := domain.Board{
+		IsRaspberryPi: true,
+		Model:         "Raspberry Pi 4 Model B Rev 1.4",
+		SerialNumber:  "10000000a1b2c3d4",
+		Revision: &domain.BoardRevision{
+			Code: "c03114", NewStyle: true, Model: "4B", Revision: "1.4",
+			MemoryMB: 4096, Manufacturer: "Sony UK", Processor: "BCM2711",
+		},
+		CPU: domain.CPUInfo{Model: "ARM implementer 0x41 part 0xd08", Cores: 4, Features: []string{"fp", "asimd"}},
+	}
+	mockBoardPort.On("GetBoardInfo").Return(boardData, nil)
+
+	boardHandler := handler.NewBoardHandler(mockBoardPort)
+
+	req, err := http.NewRequest("GET", "/v1/board", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	boardHandler.GetBoardInfo(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responseBoard domain.Board
+	err = json.NewDecoder(rr.Body).Decode(&responseBoard)
+	assert.NoError(t, err)
+
+	assert.Equal(t, boardData, responseBoard)
+	mockBoardPort.AssertExpectations(t)
+}
+
+func TestGetBoardInfo_Error(t *testing.T) {
+
+	mockBoardPort := new(MockBoardPort)
+	mockBoardPort.On("GetBoardInfo").Return(domain.Board{}, assert.AnError)
+
+	boardHandler := handler.NewBoardHandler(mockBoardPort)
+
+	req, err := http.NewRequest("GET", "/v1/board", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	boardHandler.GetBoardInfo(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve board info")
+	mockBoardPort.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type CgroupHandler struct {
+	CgroupService ports.CgroupsPort
+}
+
+func NewCgroupHandler(service ports.CgroupsPort) *CgroupHandler {
+	return &CgroupHandler{CgroupService: service}
+}
+
+func (h *CgroupHandler) GetCgroups(w http.ResponseWriter, r *http.Request) {
+	cgroups, err := h.CgroupService.GetCgroups()
+	if err != nil {
+		log.Printf("Error retrieving cgroups info: %v", err)
+		http.Error(w, "Failed to retrieve cgroups info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Cgroups info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cgroups)
+}
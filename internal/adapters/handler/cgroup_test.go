@@ -0,0 +1,77 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCgroupsPort struct {
+	mock.Mock
+}
+
+func (m *MockCgroupsPort) GetCgroups() ([]domain.Cgroup, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Cgroup), args.Error(1)
+}
+
+func TestGetCgroups_Success(t *testing.T) {
+
+	mockCgroupsPort := new(MockCgroupsPort)
+	memoryMax := uint64(268435456)
+	cgroupData := []domain.Cgroup{
+		{
+			Path:   "/system.slice/myapp.service",
+			CPU:    domain.CgroupCPU{UsageUsec: 98765432, UserUsec: 90000000, SystemUsec: 8765432},
+			Memory: domain.CgroupMemory{Current: 268369920, Max: &memoryMax, OOM: 3, OOMKills: 2},
+			IO:     []domain.CgroupIO{{Device: "mmcblk0", ReadBytes: 2097152, WriteBytes: 10485760}},
+			Pids:   domain.CgroupPids{Current: 17},
+		},
+	}
+	mockCgroupsPort.On("GetCgroups").Return(cgroupData, nil)
+
+	cgroupHandler := handler.NewCgroupHandler(mockCgroupsPort)
+
+	req, err := http.NewRequest("GET", "/v1/cgroups", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	cgroupHandler.GetCgroups(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"Max":null`)
+
+	var responseCgroups []domain.Cgroup
+	err = json.NewDecoder(rr.Body).Decode(&responseCgroups)
+	assert.NoError(t, err)
+
+	assert.Equal(t, cgroupData, responseCgroups)
+	mockCgroupsPort.AssertExpectations(t)
+}
+
+func TestGetCgroups_Error(t *testing.T) {
+
+	mockCgroupsPort := new(MockCgroupsPort)
+	mockCgroupsPort.On("GetCgroups").Return([]domain.Cgroup{}, assert.AnError)
+
+	cgroupHandler := handler.NewCgroupHandler(mockCgroupsPort)
+
+	req, err := http.NewRequest("GET", "/v1/cgroups", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	cgroupHandler.GetCgroups(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve cgroups info")
+	mockCgroupsPort.AssertExpectations(t)
+}
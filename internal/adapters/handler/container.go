@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type ContainerHandler struct {
+	ContainerService ports.ContainerPort
+}
+
+// NewContainerHandler takes a nil service when the collector is disabled
+func NewContainerHandler(service ports.ContainerPort) *ContainerHandler {
+	return &ContainerHandler{ContainerService: service}
+}
+
+func (h *ContainerHandler) GetContainers(w http.ResponseWriter, r *http.Request) {
+	if h.ContainerService == nil {
+		http.Error(w, "Docker collector is disabled", http.StatusNotFound)
+		return
+	}
+
+	containers, err := h.ContainerService.GetContainers()
+	if err != nil {
+		log.Printf("Error retrieving containers info: %v", err)
+		http.Error(w, "Failed to retrieve containers info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Containers info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(containers)
+}
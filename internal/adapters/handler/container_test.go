@@ -0,0 +1,88 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockContainerPort struct {
+	mock.Mock
+}
+
+func (m *MockContainerPort) GetContainers() ([]domain.Container, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Container), args.Error(1)
+}
+
+func TestGetContainers_Success(t *testing.T) {
+
+	mockContainerPort := new(MockContainerPort)
+	containerData := []domain.Container{
+		{ID: "a1b2c3", Name: "pihole", Image: "pihole/pihole:latest", State: "running",
+			Status: "Up 3 days (healthy)", Health: "healthy", Created: time.Unix(1718000000, 0).UTC(),
+			CPUPercent: 12.5, MemoryUsage: 100 << 20, MemoryLimit: 4 << 30, MemoryPercent: 2.44140625,
+			NetworkRx: 1010, NetworkTx: 2020, BlockRead: 4096, BlockWrite: 8192},
+	}
+	mockContainerPort.On("GetContainers").Return(containerData, nil)
+
+	containerHandler := handler.NewContainerHandler(mockContainerPort)
+
+	req, err := http.NewRequest("GET", "/v1/containers", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	containerHandler.GetContainers(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responseContainers []domain.Container
+	err = json.NewDecoder(rr.Body).Decode(&responseContainers)
+	assert.NoError(t, err)
+
+	assert.Equal(t, containerData, responseContainers)
+	mockContainerPort.AssertExpectations(t)
+}
+
+func TestGetContainers_Disabled(t *testing.T) {
+
+	containerHandler := handler.NewContainerHandler(nil)
+
+	req, err := http.NewRequest("GET", "/v1/containers", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	containerHandler.GetContainers(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Docker collector is disabled")
+}
+
+func TestGetContainers_Error(t *testing.T) {
+
+	mockContainerPort := new(MockContainerPort)
+	mockContainerPort.On("GetContainers").Return([]domain.Container{}, assert.AnError)
+
+	containerHandler := handler.NewContainerHandler(mockContainerPort)
+
+	req, err := http.NewRequest("GET", "/v1/containers", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	containerHandler.GetContainers(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve containers info")
+	mockContainerPort.AssertExpectations(t)
+}
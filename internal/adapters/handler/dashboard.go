@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// The dashboard is a static page built on top of the JSON endpoints. It is
+// embedded so the binary is all a Pi needs, even when it is offline.
+//
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+type DashboardHandler struct {
+	fileServer http.Handler
+}
+
+func NewDashboardHandler() *DashboardHandler {
+	files, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		// Only possible if the embed directive above is broken
+		panic(err)
+	}
+	return &DashboardHandler{fileServer: http.FileServer(http.FS(files))}
+}
+
+func (h *DashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-cache")
+	h.fileServer.ServeHTTP(w, r)
+}
@@ -0,0 +1,156 @@
+// Polls the JSON endpoints and renders them. No external dependencies, so it
+// works on Pis without internet access.
+"use strict";
+
+const REFRESH_MS = 2000;
+
+// Previous network counters, to turn them into throughput
+let previousNetwork = null;
+
+function formatBytes(bytes) {
+  const units = ["B", "KB", "MB", "GB", "TB"];
+  let value = bytes;
+  let unit = 0;
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024;
+    unit++;
+  }
+  return value.toFixed(unit === 0 ? 0 : 1) + " " + units[unit];
+}
+
+function formatBits(bitsPerSecond) {
+  const units = ["b/s", "Kb/s", "Mb/s", "Gb/s"];
+  let value = bitsPerSecond;
+  let unit = 0;
+  while (value >= 1000 && unit < units.length - 1) {
+    value /= 1000;
+    unit++;
+  }
+  return value.toFixed(unit === 0 ? 0 : 1) + " " + units[unit];
+}
+
+function severity(percent) {
+  if (percent >= 90) return "fill crit";
+  if (percent >= 75) return "fill warn";
+  return "fill";
+}
+
+function element(tag, className, text) {
+  const el = document.createElement(tag);
+  if (className) el.className = className;
+  if (text !== undefined) el.textContent = text;
+  return el;
+}
+
+async function getJSON(path) {
+  const response = await fetch(path, { cache: "no-store" });
+  if (!response.ok) {
+    throw new Error(path + ": " + response.status);
+  }
+  return response.json();
+}
+
+function renderCPU(cpu) {
+  document.getElementById("load1").textContent = cpu.LoadAvg1Min.toFixed(2);
+  document.getElementById("load5").textContent = cpu.LoadAvg5Min.toFixed(2);
+  document.getElementById("load15").textContent = cpu.LoadAvg15Min.toFixed(2);
+}
+
+function renderRAM(ram) {
+  const used = ram.Total - ram.Available;
+  const percent = ram.Total ? (used / ram.Total) * 100 : 0;
+  const bar = document.getElementById("ram-bar");
+  bar.className = severity(percent);
+  bar.style.width = percent.toFixed(1) + "%";
+  document.getElementById("ram-text").textContent =
+    formatBytes(used) + " of " + formatBytes(ram.Total) + " in use (" + percent.toFixed(0) + "%)";
+}
+
+function renderStorage(devices) {
+  const container = document.getElementById("storage");
+  container.replaceChildren();
+
+  const partitions = [];
+  for (const device of devices || []) {
+    for (const partition of Object.values(device.Partitions || {})) {
+      if (partition.MountPoint && partition.Total > 0) {
+        partitions.push(partition);
+      }
+    }
+  }
+  partitions.sort((a, b) => a.MountPoint.localeCompare(b.MountPoint));
+
+  if (partitions.length === 0) {
+    container.append(element("p", "detail", "No mounted partitions"));
+    return;
+  }
+
+  for (const partition of partitions) {
+    const percent = (partition.Used / partition.Total) * 100;
+    const row = element("div", "partition");
+    const name = element("div", "name");
+    name.append(element("span", "", partition.MountPoint + " (" + partition.Name + ")"));
+    name.append(element("span", "", formatBytes(partition.Used) + " / " + formatBytes(partition.Total)));
+    const bar = element("div", "bar");
+    const fill = element("div", severity(percent));
+    fill.style.width = percent.toFixed(1) + "%";
+    bar.append(fill);
+    row.append(name, bar);
+    container.append(row);
+  }
+}
+
+function renderNetwork(interfaces) {
+  const now = Date.now();
+  const current = {};
+  const tbody = document.getElementById("network");
+  tbody.replaceChildren();
+
+  for (const iface of interfaces || []) {
+    current[iface.InterfaceName] = { time: now, rx: iface.Rx.Bytes, tx: iface.Tx.Bytes };
+
+    let rx = "–";
+    let tx = "–";
+    const previous = previousNetwork && previousNetwork[iface.InterfaceName];
+    if (previous && now > previous.time && iface.Rx.Bytes >= previous.rx && iface.Tx.Bytes >= previous.tx) {
+      const seconds = (now - previous.time) / 1000;
+      rx = formatBits(((iface.Rx.Bytes - previous.rx) * 8) / seconds);
+      tx = formatBits(((iface.Tx.Bytes - previous.tx) * 8) / seconds);
+    }
+
+    const row = element("tr");
+    row.append(
+      element("td", "", iface.InterfaceName),
+      element("td", "", iface.BitRate ? formatBits(iface.BitRate) : "–"),
+      element("td", "", rx),
+      element("td", "", tx),
+      element("td", "", String(iface.Rx.Errors + iface.Tx.Errors)),
+      element("td", "", String(iface.Rx.Drops + iface.Tx.Drops))
+    );
+    tbody.append(row);
+  }
+
+  previousNetwork = current;
+}
+
+async function refresh() {
+  const status = document.getElementById("status");
+  const results = await Promise.allSettled([
+    getJSON("v1/cpu").then(renderCPU),
+    getJSON("v1/ram").then(renderRAM),
+    getJSON("v1/storage").then(renderStorage),
+    getJSON("v1/network").then(renderNetwork),
+  ]);
+
+  const failed = results.filter((result) => result.status === "rejected");
+  if (failed.length > 0) {
+    status.className = "status error";
+    status.textContent = failed.map((result) => result.reason.message).join(", ");
+  } else {
+    status.className = "status";
+    status.textContent = "updated " + new Date().toLocaleTimeString();
+  }
+}
+
+refresh();
+setInterval(refresh, REFRESH_MS);
@@ -0,0 +1,68 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboard(t *testing.T) {
+
+	dashboardHandler := handler.NewDashboardHandler()
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Index": {
+			"path":        "/",
+			"status":      http.StatusOK,
+			"contentType": "text/html; charset=utf-8",
+			"contains":    `<script src="app.js"></script>`,
+		},
+		"Case 2 - Script": {
+			"path":        "/app.js",
+			"status":      http.StatusOK,
+			"contentType": "text/javascript; charset=utf-8",
+			"contains":    `getJSON("v1/storage")`,
+		},
+		"Case 3 - Missing file": {
+			"path":   "/missing.js",
+			"status": http.StatusNotFound,
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		req, err := http.NewRequest("GET", caseData["path"].(string), nil)
+		assert.NoError(t, err)
+		rr := httptest.NewRecorder()
+
+		dashboardHandler.ServeHTTP(rr, req)
+
+		assert.Equal(t, caseData["status"].(int), rr.Code)
+		if contentType, exists := caseData["contentType"]; exists {
+			assert.Equal(t, contentType.(string), rr.Header().Get("Content-Type"))
+			assert.Contains(t, rr.Body.String(), caseData["contains"].(string))
+		}
+	}
+}
+
+func TestDashboard_NoExternalAssets(t *testing.T) {
+
+	dashboardHandler := handler.NewDashboardHandler()
+
+	for _, path := range []string{"/", "/app.js", "/style.css"} {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoError(t, err)
+		rr := httptest.NewRecorder()
+
+		dashboardHandler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotContains(t, rr.Body.String(), "http://", path)
+		assert.NotContains(t, rr.Body.String(), "https://", path)
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type DirectoryHandler struct {
+	DirectoryService ports.DirectoriesPort
+}
+
+func NewDirectoryHandler(service ports.DirectoriesPort) *DirectoryHandler {
+	return &DirectoryHandler{DirectoryService: service}
+}
+
+func (h *DirectoryHandler) GetDirectories(w http.ResponseWriter, r *http.Request) {
+	directories, err := h.DirectoryService.GetDirectories()
+	if err != nil {
+		log.Printf("Error retrieving directories info: %v", err)
+		http.Error(w, "Failed to retrieve directories info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Directories info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(directories)
+}
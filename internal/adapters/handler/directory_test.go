@@ -0,0 +1,79 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDirectoriesPort struct {
+	mock.Mock
+}
+
+func (m *MockDirectoriesPort) GetDirectories() ([]domain.DirectoryUsage, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.DirectoryUsage), args.Error(1)
+}
+
+func TestGetDirectories_Success(t *testing.T) {
+
+	mockDirectoriesPort := new(MockDirectoriesPort)
+	scannedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	directoryData := []domain.DirectoryUsage{
+		{
+			Path: "/var/log", SoftLimit: 500 << 20, OverLimit: true, ScannedAt: &scannedAt,
+			Scan: &domain.DirectoryScan{
+				Size: 600 << 20, Files: 120, Directories: 8, Duration: 0.4,
+				Largest: []domain.DirectoryFile{{Path: "/var/log/syslog", Size: 400 << 20}},
+			},
+		},
+		{Path: "/home/pi/recordings"},
+	}
+	mockDirectoriesPort.On("GetDirectories").Return(directoryData, nil)
+
+	directoryHandler := handler.NewDirectoryHandler(mockDirectoriesPort)
+
+	req, err := http.NewRequest("GET", "/v1/directories", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	directoryHandler.GetDirectories(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"Scan":null`)
+
+	var responseDirectories []domain.DirectoryUsage
+	err = json.NewDecoder(rr.Body).Decode(&responseDirectories)
+	assert.NoError(t, err)
+
+	assert.Equal(t, directoryData, responseDirectories)
+	mockDirectoriesPort.AssertExpectations(t)
+}
+
+func TestGetDirectories_Error(t *testing.T) {
+
+	mockDirectoriesPort := new(MockDirectoriesPort)
+	mockDirectoriesPort.On("GetDirectories").Return([]domain.DirectoryUsage{}, assert.AnError)
+
+	directoryHandler := handler.NewDirectoryHandler(mockDirectoriesPort)
+
+	req, err := http.NewRequest("GET", "/v1/directories", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	directoryHandler.GetDirectories(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve directories info")
+	mockDirectoriesPort.AssertExpectations(t)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type HealthStatus struct {
+	Status string
+}
+
+type HealthHandler struct {
+	DiagnosticsService ports.DiagnosticsPort
+}
+
+func NewHealthHandler(service ports.DiagnosticsPort) *HealthHandler {
+	return &HealthHandler{DiagnosticsService: service}
+}
+
+// GetHealthz only tells the process is alive, it checks nothing else
+func (h *HealthHandler) GetHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(HealthStatus{Status: "ok"})
+}
+
+// GetReadyz checks the data sources of every collector, replying 503 when any
+// of the required ones is missing
+func (h *HealthHandler) GetReadyz(w http.ResponseWriter, r *http.Request) {
+	readiness := h.DiagnosticsService.Readiness()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !readiness.Ready {
+		log.Printf("Not ready: %v", readiness.Collectors)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(readiness)
+}
+
+func (h *HealthHandler) GetDiagnostics(w http.ResponseWriter, r *http.Request) {
+	diagnostics := h.DiagnosticsService.Diagnostics()
+
+	log.Printf("Diagnostics retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diagnostics)
+}
@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDiagnosticsService struct {
+	mock.Mock
+}
+
+func (m *MockDiagnosticsService) Readiness() domain.Readiness {
+	args := m.Called()
+	return args.Get(0).(domain.Readiness)
+}
+
+func (m *MockDiagnosticsService) Diagnostics() []domain.CollectorDiagnostics {
+	args := m.Called()
+	return args.Get(0).([]domain.CollectorDiagnostics)
+}
+
+func TestGetHealthz(t *testing.T) {
+	mockService := new(MockDiagnosticsService)
+	healthHandler := handler.NewHealthHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/healthz", nil)
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+
+	healthHandler.GetHealthz(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"Status":"ok"}`, rr.Body.String())
+
+	// Liveness never checks the collectors
+	mockService.AssertNotCalled(t, "Readiness")
+}
+
+func TestGetReadyz(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Ready": {
+			"readiness": domain.Readiness{Ready: true, Collectors: map[string]bool{"cpu": true}},
+			"status":    http.StatusOK,
+		},
+		"Case 2 - Not ready": {
+			"readiness": domain.Readiness{Ready: false, Collectors: map[string]bool{"cpu": true, "storage": false}},
+			"status":    http.StatusServiceUnavailable,
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		mockService := new(MockDiagnosticsService)
+		mockService.On("Readiness").Return(caseData["readiness"].(domain.Readiness))
+		healthHandler := handler.NewHealthHandler(mockService)
+
+		req, err := http.NewRequest("GET", "/readyz", nil)
+		assert.NoError(t, err)
+		rr := httptest.NewRecorder()
+
+		healthHandler.GetReadyz(rr, req)
+
+		assert.Equal(t, caseData["status"].(int), rr.Code)
+		var readiness domain.Readiness
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&readiness))
+		assert.Equal(t, caseData["readiness"].(domain.Readiness), readiness)
+		mockService.AssertExpectations(t)
+	}
+}
+
+func TestGetDiagnostics(t *testing.T) {
+	diagnostics := []domain.CollectorDiagnostics{
+		{
+			Name:  "network",
+			Ready: true,
+			Sources: []domain.DataSource{
+				{Name: "iwconfig", Kind: "tool", Available: false, Provides: "wireless bit rate"},
+			},
+			Capabilities: []string{},
+			Stats: domain.CollectorStats{
+				Runs: 3, Failures: 1, LastError: "boom",
+				LastRun: time.Date(2024, 8, 10, 12, 0, 0, 0, time.UTC), LastDuration: 15 * time.Millisecond,
+			},
+		},
+	}
+
+	mockService := new(MockDiagnosticsService)
+	mockService.On("Diagnostics").Return(diagnostics)
+	healthHandler := handler.NewHealthHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/v1/diagnostics", nil)
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+
+	healthHandler.GetDiagnostics(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedResponse, _ := json.Marshal(diagnostics)
+	assert.JSONEq(t, string(expectedResponse), rr.Body.String())
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type MountHandler struct {
+	MountService ports.MountsPort
+}
+
+func NewMountHandler(service ports.MountsPort) *MountHandler {
+	return &MountHandler{MountService: service}
+}
+
+func (h *MountHandler) GetMounts(w http.ResponseWriter, r *http.Request) {
+	mounts, err := h.MountService.GetMounts()
+	if err != nil {
+		log.Printf("Error retrieving mounts info: %v", err)
+		http.Error(w, "Failed to retrieve mounts info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Mounts info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(mounts)
+}
@@ -0,0 +1,77 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMountsPort struct {
+	mock.Mock
+}
+
+func (m *MockMountsPort) GetMounts() ([]domain.MountedFilesystem, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.MountedFilesystem), args.Error(1)
+}
+
+func TestGetMounts_Success(t *testing.T) {
+
+	mockMountsPort := new(MockMountsPort)
+	mountData := []domain.MountedFilesystem{
+		{
+			Source: "/dev/mmcblk0p2", MountPoint: "/", Filesystem: "ext4", Options: []string{"rw", "noatime"},
+			Usage: &domain.FilesystemUsage{Total: 31 << 30, Used: 9 << 30, Free: 22 << 30, Available: 20 << 30},
+		},
+		{
+			Source: "nas:/export/media", MountPoint: "/mnt/media", Filesystem: "nfs4", Options: []string{"rw", "hard"},
+			Error: "statfs of /mnt/media timed out after 2s",
+		},
+	}
+	mockMountsPort.On("GetMounts").Return(mountData, nil)
+
+	mountHandler := handler.NewMountHandler(mockMountsPort)
+
+	req, err := http.NewRequest("GET", "/v1/mounts", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	mountHandler.GetMounts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"Usage":null`)
+
+	var responseMounts []domain.MountedFilesystem
+	err = json.NewDecoder(rr.Body).Decode(&responseMounts)
+	assert.NoError(t, err)
+
+	assert.Equal(t, mountData, responseMounts)
+	mockMountsPort.AssertExpectations(t)
+}
+
+func TestGetMounts_Error(t *testing.T) {
+
+	mockMountsPort := new(MockMountsPort)
+	mockMountsPort.On("GetMounts").Return([]domain.MountedFilesystem{}, assert.AnError)
+
+	mountHandler := handler.NewMountHandler(mockMountsPort)
+
+	req, err := http.NewRequest("GET", "/v1/mounts", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	mountHandler.GetMounts(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve mounts info")
+	mockMountsPort.AssertExpectations(t)
+}
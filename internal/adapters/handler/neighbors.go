@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type NeighborHandler struct {
+	NeighborService ports.NeighborPort
+}
+
+func NewNeighborHandler(service ports.NeighborPort) *NeighborHandler {
+	return &NeighborHandler{NeighborService: service}
+}
+
+func (h *NeighborHandler) GetNeighbors(w http.ResponseWriter, r *http.Request) {
+	neighbors, err := h.NeighborService.GetNeighbors()
+	if err != nil {
+		log.Printf("Error retrieving neighbors info: %v", err)
+		http.Error(w, "Failed to retrieve neighbors info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Neighbors info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(neighbors)
+}
@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNeighborPort struct {
+	mock.Mock
+}
+
+func (m *MockNeighborPort) GetNeighbors() ([]domain.Neighbor, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Neighbor), args.Error(1)
+}
+
+func TestGetNeighbors_Success(t *testing.T) {
+
+	mockNeighborPort := new(MockNeighborPort)
+	neighborData := []domain.Neighbor{
+		{Family: "inet", Address: "192.168.1.23", MAC: "b8:27:eb:12:34:56", Interface: "eth0", State: "COMPLETE", Vendor: "Raspberry Pi Foundation"},
+		{Family: "inet6", Address: "fe80::dead", Interface: "eth0", State: "FAILED"},
+	}
+	mockNeighborPort.On("GetNeighbors").Return(neighborData, nil)
+
+	neighborHandler := handler.NewNeighborHandler(mockNeighborPort)
+
+	req, err := http.NewRequest("GET", "/v1/network/neighbors", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	neighborHandler.GetNeighbors(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responseNeighbors []domain.Neighbor
+	err = json.NewDecoder(rr.Body).Decode(&responseNeighbors)
+	assert.NoError(t, err)
+
+	assert.Equal(t, neighborData, responseNeighbors)
+	mockNeighborPort.AssertExpectations(t)
+}
+
+func TestGetNeighbors_Error(t *testing.T) {
+
+	mockNeighborPort := new(MockNeighborPort)
+	mockNeighborPort.On("GetNeighbors").Return([]domain.Neighbor{}, assert.AnError)
+
+	neighborHandler := handler.NewNeighborHandler(mockNeighborPort)
+
+	req, err := http.NewRequest("GET", "/v1/network/neighbors", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	neighborHandler.GetNeighbors(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve neighbors info")
+	mockNeighborPort.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type NetworkProtocolHandler struct {
+	NetworkProtocolService ports.NetworkProtocolPort
+}
+
+func NewNetworkProtocolHandler(service ports.NetworkProtocolPort) *NetworkProtocolHandler {
+	return &NetworkProtocolHandler{NetworkProtocolService: service}
+}
+
+func (h *NetworkProtocolHandler) GetProtocols(w http.ResponseWriter, r *http.Request) {
+	protocols, err := h.NetworkProtocolService.GetProtocols()
+	if err != nil {
+		log.Printf("Error retrieving network protocols info: %v", err)
+		http.Error(w, "Failed to retrieve network protocols info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Network protocols info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(protocols)
+}
@@ -0,0 +1,73 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNetworkProtocolPort struct {
+	mock.Mock
+}
+
+func (m *MockNetworkProtocolPort) GetProtocols() (domain.NetworkProtocols, error) {
+	args := m.Called()
+	return args.Get(0).(domain.NetworkProtocols), args.Error(1)
+}
+
+func TestGetNetworkProtocols_Success(t *testing.T) {
+
+	mockNetworkProtocolPort := new(MockNetworkProtocolPort)
+	overflows := uint64(17)
+	protocolData := domain.NetworkProtocols{
+		TCP:     domain.TCPStats{ActiveOpens: 10234, PassiveOpens: 2345, CurrentEstablished: 7, RetransSegments: 1523, ListenOverflows: &overflows},
+		UDP:     domain.UDPStats{InDatagrams: 120345, OutDatagrams: 118976, InErrors: 7},
+		Sockets: domain.SocketStats{Used: 291, TCPInUse: 9, TCPMemory: 12288},
+	}
+	mockNetworkProtocolPort.On("GetProtocols").Return(protocolData, nil)
+
+	networkProtocolHandler := handler.NewNetworkProtocolHandler(mockNetworkProtocolPort)
+
+	req, err := http.NewRequest("GET", "/v1/network/protocols", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	networkProtocolHandler.GetProtocols(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"TCP6InUse":null`)
+
+	var responseProtocols domain.NetworkProtocols
+	err = json.NewDecoder(rr.Body).Decode(&responseProtocols)
+	assert.NoError(t, err)
+
+	assert.Equal(t, protocolData, responseProtocols)
+	mockNetworkProtocolPort.AssertExpectations(t)
+}
+
+func TestGetNetworkProtocols_Error(t *testing.T) {
+
+	mockNetworkProtocolPort := new(MockNetworkProtocolPort)
+	mockNetworkProtocolPort.On("GetProtocols").Return(domain.NetworkProtocols{}, assert.AnError)
+
+	networkProtocolHandler := handler.NewNetworkProtocolHandler(mockNetworkProtocolPort)
+
+	req, err := http.NewRequest("GET", "/v1/network/protocols", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	networkProtocolHandler.GetProtocols(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve network protocols info")
+	mockNetworkProtocolPort.AssertExpectations(t)
+}
@@ -0,0 +1,420 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** DOCUMENTATION ******************************************** */
+
+// APIParameter documents a query parameter of an operation
+type APIParameter struct {
+	Name        string
+	Description string
+	Type        string // OpenAPI primitive type, string when empty
+}
+
+// APIOperation documents a route. Response is a zero value of the type the
+// route encodes, from which its schema is generated.
+type APIOperation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Parameters  []APIParameter
+	Response    any
+	ContentType string            // application/json when empty
+	Errors      map[string]string // status to description, a 500 when nil
+}
+
+// V1Operations documents every route of version 1 of the API. Adding a route
+// without documenting it here makes the route coverage test fail.
+var V1Operations = []APIOperation{
+	{
+		Method: "GET", Path: "/v1/cpu",
+		Summary:  "CPU load averages",
+		Response: domain.CPU{},
+	},
+	{
+		Method: "GET", Path: "/v1/ram",
+		Summary:  "RAM usage in bytes",
+		Response: domain.RAM{},
+	},
+	{
+		Method: "GET", Path: "/v1/storage",
+		Summary:     "Storage devices and their partitions",
+		Description: "Partitions are keyed by partition name. Sizes are in bytes. Mounts lists every mount of a partition, the shallowest first. UnexpectedReadOnly flags partitions mounted read-only at a mount point expected to be read-write. Slaves and Holders describe stacked devices, like LVM volumes or md arrays, whose state is in RAID. Health is the device's entry of /v1/storage/health, null when unknown.",
+		Response:    []domain.Device{},
+	},
+	{
+		Method: "GET", Path: "/v1/storage/health",
+		Summary:     "Wear and health of the SD cards, eMMC, SATA and NVMe devices",
+		Description: "SD cards and eMMC are read from sysfs, SATA and NVMe disks with smartctl when installed. Devices are named as in /v1/storage. WearPercent is the life used, over 100 when the estimated life is exceeded.",
+		Response:    []domain.DeviceHealth{},
+	},
+	{
+		Method: "GET", Path: "/v1/storage/forecast",
+		Summary:     "Fill rate and time until full of every mounted partition",
+		Description: "Fitted with a Theil-Sen regression, robust to log rotations and temporary files, over the usage sampled in the background during the configured window. FillRate is in bytes per day, and null until the samples span an hour. SecondsUntilFull and EstimatedFull are null when the partition isn't filling up.",
+		Response:    []domain.UsageForecast{},
+	},
+	{
+		Method: "GET", Path: "/v1/mounts",
+		Summary:     "Mount table with the capacity of every filesystem",
+		Description: "Every mount selected by the mounts configuration, including tmpfs, overlay and network filesystems, in the order of /proc/mounts. Usage is null, with the reason in Error, when the filesystem couldn't be read. TimedOut tells whether it didn't answer within the configured timeout, rather than failing at once. Sizes are in bytes.",
+		Response:    []domain.MountedFilesystem{},
+	},
+	{
+		Method: "GET", Path: "/v1/directories",
+		Summary:     "Size, file count and largest files of the watched directories",
+		Description: "Directories are configured in directories.paths and walked in the background, without following symbolic links or crossing into other filesystems, so results can be as old as the configured interval. Scan is null until the first walk completes, and Error tells why the latest walk failed. OverLimit is set when the size exceeds the soft limit. Sizes are in bytes.",
+		Response:    []domain.DirectoryUsage{},
+	},
+	{
+		Method: "GET", Path: "/v1/network",
+		Summary:  "Network interfaces counters and link bit rate",
+		Response: []domain.NetworkInterface{},
+	},
+	{
+		Method: "GET", Path: "/v1/network/protocols",
+		Summary:     "TCP and UDP counters and sockets in use",
+		Description: "Counters are cumulative since boot, from /proc/net/snmp and /proc/net/netstat, but for CurrentEstablished. Sockets in use come from /proc/net/sockstat and sockstat6, with their memory in bytes. The counters of /proc/net/netstat and the IPv6 sockets are null when missing.",
+		Response:    domain.NetworkProtocols{},
+	},
+	{
+		Method: "GET", Path: "/v1/network/sockets",
+		Summary:     "TCP and UDP sockets with the processes holding them",
+		Description: "Every entry of /proc/net/tcp, tcp6, udp and udp6, with decoded addresses and states named as in include/net/tcp_states.h, like TIME_WAIT rather than the TIME-WAIT of ss. Listening UDP sockets are UNCONN. Processes are found through /proc/[pid]/fd, so without root only the ones of the API user are known. Queues are in bytes.",
+		Parameters: []APIParameter{
+			{Name: "state", Description: "Comma separated states, like LISTEN,UNCONN for every listening socket. All of them by default."},
+			{Name: "port", Description: "Local or remote port", Type: "integer"},
+		},
+		Response: []domain.Socket{},
+		Errors: map[string]string{
+			"400": "Unknown state or invalid port",
+			"500": "The information couldn't be retrieved",
+		},
+	},
+	{
+		Method: "GET", Path: "/v1/network/routes",
+		Summary:     "Routing tables, default gateways and DNS resolver",
+		Description: "IPv4 routes from /proc/net/route and IPv6 routes from /proc/net/ipv6_route, leaving out reject routes, multicast routes and the ones of the host's own addresses. DefaultGateway and DefaultGateway6 are the default routes in use, null without any, and without Gateway on point-to-point links. Nameservers, search domains and options come from /etc/resolv.conf.",
+		Response:    domain.Routing{},
+	},
+	{
+		Method: "GET", Path: "/v1/network/neighbors",
+		Summary:     "ARP and IPv6 neighbor tables, with the vendor of each MAC address",
+		Description: "IPv4 neighbors come from /proc/net/arp, in state INCOMPLETE, COMPLETE or PERMANENT, and IPv6 ones from ip -j -6 neigh, left out when ip isn't installed. MAC is empty until resolved. Vendor comes from the OUI table embedded in the binary, or the one set in neighbors.oui_file, and is empty for unknown and locally administered addresses.",
+		Response:    []domain.Neighbor{},
+	},
+	{
+		Method: "GET", Path: "/v1/probes",
+		Summary:     "Reachability, loss and latency of the configured connectivity probes",
+		Description: "Probes are configured in probes.targets: tcp connects to a host:port, http expects a status from a GET without following redirects, dns resolves a name and icmp sends an unprivileged echo request, where net.ipv4.ping_group_range allows it. They run in the background, each on its own interval. Loss is the percentage of failed attempts within the latest probes.window ones, latencies are in seconds and null without a success. Probes the host doesn't permit report their Error without attempts.",
+		Response:    []domain.Probe{},
+	},
+	{
+		Method: "GET", Path: "/v1/system",
+		Summary:     "Hostname, kernel, OS release, architecture, uptime and boot time",
+		Description: "Only fails when the uptime can't be read. Fields whose source is missing are left empty.",
+		Response:    domain.System{},
+	},
+	{
+		Method: "GET", Path: "/v1/board",
+		Summary:     "Board model, serial number and decoded Raspberry Pi revision",
+		Description: "Revision is null on other hardware, where only the generic CPU model and flags are reported.",
+		Response:    domain.Board{},
+	},
+	{
+		Method: "GET", Path: "/v1/processes",
+		Summary:     "Heaviest running processes",
+		Description: "CPUPercent is the usage since the previous sample of any client, 100 being one whole core. The first sample reports the average since each process started. RSS is in bytes.",
+		Parameters: []APIParameter{
+			{Name: "sort", Description: "One of cpu, memory, threads, pid or name, cpu by default"},
+			{Name: "limit", Description: "How many processes to return, 10 by default and 0 for all", Type: "integer"},
+		},
+		Response: []domain.Process{},
+		Errors: map[string]string{
+			"400": "Unknown sort key or invalid limit",
+			"500": "The information couldn't be retrieved",
+		},
+	},
+	{
+		Method: "GET", Path: "/v1/watch",
+		Summary:     "Liveness of the configured watched processes",
+		Description: "Processes are matched by pidfile, exact name or command line expression. When several match, the oldest is reported. They are checked in the background every watch_interval, and Restarts counts the PID changes detected since the API started.",
+		Response:    []domain.WatchedProcess{},
+	},
+	{
+		Method: "GET", Path: "/v1/services",
+		Summary:     "State of the configured systemd units and every failed unit",
+		Description: "Units keep the order of the configuration. Units that don't exist have a LoadState of not-found.",
+		Response:    domain.Services{},
+	},
+	{
+		Method: "GET", Path: "/v1/containers",
+		Summary:     "Docker containers with their state, health, restarts and resource usage",
+		Description: "Requires the docker collector to be enabled. Usage is only reported for running containers, computed as docker stats does.",
+		Response:    []domain.Container{},
+		Errors: map[string]string{
+			"404": "The docker collector is disabled",
+			"500": "The information couldn't be retrieved",
+		},
+	},
+	{
+		Method: "GET", Path: "/v1/cgroups",
+		Summary:     "CPU, memory, IO and PIDs accounting of the configured cgroups v2",
+		Description: "Paths are relative to /sys/fs/cgroup. Cgroups that don't exist are skipped. Limits are null when unlimited.",
+		Response:    []domain.Cgroup{},
+	},
+	{
+		Method: "GET", Path: "/v1/power",
+		Summary:     "Power supplies, under-voltage and throttling status",
+		Description: "Throttled is the decoded get_throttled bitmask of the Raspberry Pi firmware, null elsewhere. Voltage, current and power of the supplies are in volts, amperes and watts.",
+		Response:    domain.Power{},
+	},
+	{
+		Method: "GET", Path: "/v1/sensors",
+		Summary:     "Temperatures, fan speeds, voltages, currents and powers of the hwmon chips",
+		Description: "Every *_input attribute of /sys/class/hwmon/hwmon*, with its label when the driver provides one. Values are in °C, RPM, V, A and W.",
+		Response:    []domain.Sensor{},
+	},
+	{
+		Method: "GET", Path: "/v1/stream",
+		Summary:     "Live stream of samples as Server-Sent Events",
+		Description: "One event per subscribed collector on each tick, named after the collector, whose data is a StreamMessage.",
+		Parameters:  streamParameters,
+		Response:    StreamMessage{},
+		ContentType: "text/event-stream",
+		Errors:      streamErrors,
+	},
+	{
+		Method: "GET", Path: "/v1/stream/ws",
+		Summary:     "Live stream of samples over WebSocket",
+		Description: "Upgrades to a WebSocket sending one StreamMessage per subscribed collector on each tick. Send a StreamSubscription to change it.",
+		Parameters:  streamParameters,
+		Response:    StreamMessage{},
+		Errors:      streamErrors,
+	},
+	{
+		Method: "GET", Path: "/v1/diagnostics",
+		Summary:     "Data sources, capabilities and last run of every collector",
+		Description: "Runs are accounted for whatever triggered them: endpoints, streams or the metrics output.",
+		Response:    []domain.CollectorDiagnostics{},
+		Errors:      map[string]string{},
+	},
+	{
+		Method: "GET", Path: "/v1/openapi.json",
+		Summary:  "This document",
+		Response: map[string]any{},
+		Errors:   map[string]string{},
+	},
+}
+
+// HealthOperations documents the probes, which live outside any version
+var HealthOperations = []APIOperation{
+	{
+		Method: "GET", Path: "/healthz",
+		Summary:  "Liveness probe, the process is up",
+		Response: HealthStatus{},
+		Errors:   map[string]string{},
+	},
+	{
+		Method: "GET", Path: "/readyz",
+		Summary:     "Readiness probe, every required data source of the core collectors is available",
+		Description: "Replies 503 when cpu, ram, storage or network misses a required data source. Every other collector is reported in Collectors without affecting Ready. No collector is run.",
+		Response:    domain.Readiness{},
+		Errors:      map[string]string{"503": "Some required data source is missing"},
+	},
+}
+
+var streamErrors = map[string]string{"400": "Unknown collector or invalid interval"}
+
+var streamParameters = []APIParameter{
+	{Name: "collectors", Description: "Comma separated collectors to subscribe to, all of them by default"},
+	{Name: "interval", Description: "Duration like 2s or number of seconds, clamped to the server minimum"},
+}
+
+/* ******************************************** SCHEMAS ******************************************** */
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type schemaBuilder struct {
+	components map[string]any
+}
+
+// schemaFor returns the schema of t, registering named structs as components
+func (b *schemaBuilder) schemaFor(t reflect.Type) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.schemaFor(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		if _, exists := b.components[t.Name()]; !exists {
+			// Reserve the name first, for recursive types
+			b.components[t.Name()] = nil
+			b.components[t.Name()] = b.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		// interfaces (any) accept whatever value
+		return map[string]any{}
+	}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		properties[name] = b.schemaFor(field.Type)
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+/* ******************************************** DOCUMENT ******************************************** */
+
+// OpenAPIDocument builds the OpenAPI 3 document describing the operations
+func OpenAPIDocument(operations []APIOperation) map[string]any {
+	builder := &schemaBuilder{components: map[string]any{}}
+
+	// Always describe the main domain types, even if no route returns them
+	for _, model := range []any{domain.CPU{}, domain.RAM{}, domain.Device{}, domain.NetworkInterface{}} {
+		builder.schemaFor(reflect.TypeOf(model))
+	}
+
+	paths := map[string]any{}
+	for _, op := range operations {
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+
+		parameters := []any{}
+		for _, param := range op.Parameters {
+			paramType := param.Type
+			if paramType == "" {
+				paramType = "string"
+			}
+			parameters = append(parameters, map[string]any{
+				"name":        param.Name,
+				"in":          "query",
+				"description": param.Description,
+				"schema":      map[string]any{"type": paramType},
+			})
+		}
+
+		operation := map[string]any{
+			"summary":    op.Summary,
+			"parameters": parameters,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content": map[string]any{
+						contentType: map[string]any{"schema": builder.schemaFor(reflect.TypeOf(op.Response))},
+					},
+				},
+			},
+		}
+		errorResponses := op.Errors
+		if errorResponses == nil {
+			errorResponses = map[string]string{"500": "The information couldn't be retrieved"}
+		}
+		for status, description := range errorResponses {
+			operation["responses"].(map[string]any)[status] = map[string]any{"description": description}
+		}
+		if op.Description != "" {
+			operation["description"] = op.Description
+		}
+
+		pathItem, exists := paths[op.Path].(map[string]any)
+		if !exists {
+			pathItem = map[string]any{}
+			paths[op.Path] = pathItem
+		}
+		pathItem[strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "pi-monitor-api",
+			"description": "Lightweight monitoring API for Raspberry Pi and similar Linux devices",
+			"version":     "v1",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": builder.components},
+	}
+}
+
+// DocumentedRoutes lists the "METHOD /path" of every operation, sorted
+func DocumentedRoutes(operations []APIOperation) []string {
+	routes := make([]string, 0, len(operations))
+	for _, op := range operations {
+		routes = append(routes, op.Method+" "+op.Path)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+/* ******************************************** HANDLER ******************************************** */
+
+type OpenAPIHandler struct {
+	document []byte
+}
+
+func NewOpenAPIHandler(operations []APIOperation) *OpenAPIHandler {
+	document, err := json.MarshalIndent(OpenAPIDocument(operations), "", "  ")
+	if err != nil {
+		// Only possible if a documented type can't be encoded
+		panic(err)
+	}
+	return &OpenAPIHandler{document: document}
+}
+
+func (h *OpenAPIHandler) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	log.Printf("OpenAPI document retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(h.document)
+}
@@ -0,0 +1,57 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOpenAPI(t *testing.T) {
+
+	openAPIHandler := handler.NewOpenAPIHandler(handler.V1Operations)
+
+	req, err := http.NewRequest("GET", "/v1/openapi.json", nil)
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+
+	openAPIHandler.GetOpenAPI(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var document map[string]any
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+
+	schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+	for _, name := range []string{"CPU", "RAM", "Device", "Partition", "NetworkInterface", "NetworkStats"} {
+		assert.Contains(t, schemas, name)
+	}
+
+	// Nested types are referenced, not inlined
+	device := schemas["Device"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type":                 "object",
+		"additionalProperties": map[string]any{"$ref": "#/components/schemas/Partition"},
+	}, device["Partitions"])
+
+	storage := document["paths"].(map[string]any)["/v1/storage"].(map[string]any)["get"].(map[string]any)
+	schema := storage["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+	assert.Equal(t, map[string]any{
+		"type":  "array",
+		"items": map[string]any{"$ref": "#/components/schemas/Device"},
+	}, schema)
+}
+
+func TestDocumentedRoutes(t *testing.T) {
+	routes := handler.DocumentedRoutes([]handler.APIOperation{
+		{Method: "GET", Path: "/v1/ram"},
+		{Method: "GET", Path: "/v1/cpu"},
+	})
+	assert.Equal(t, []string{"GET /v1/cpu", "GET /v1/ram"}, routes)
+}
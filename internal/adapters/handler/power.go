@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type PowerHandler struct {
+	PowerService ports.PowerPort
+}
+
+func NewPowerHandler(service ports.PowerPort) *PowerHandler {
+	return &PowerHandler{PowerService: service}
+}
+
+func (h *PowerHandler) GetPowerInfo(w http.ResponseWriter, r *http.Request) {
+	power, err := h.PowerService.GetPower()
+	if err != nil {
+		log.Printf("Error retrieving power info: %v", err)
+		http.Error(w, "Failed to retrieve power info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Power info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(power)
+}
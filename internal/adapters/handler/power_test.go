@@ -0,0 +1,76 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPowerPort struct {
+	mock.Mock
+}
+
+func (m *MockPowerPort) GetPower() (domain.Power, error) {
+	args := m.Called()
+	return args.Get(0).(domain.Power), args.Error(1)
+}
+
+func TestGetPower_Success(t *testing.T) {
+
+	mockPowerPort := new(MockPowerPort)
+	capacity := 87
+	powerData := domain.Power{
+		Supplies: []domain.PowerSupply{
+			{Name: "ups-hat", Type: "Battery", Status: "Discharging", Capacity: &capacity},
+		},
+		Throttled: &domain.ThrottledStatus{
+			Raw: "0x50000", Source: "vcgencmd", UnderVoltageOccurred: true, ThrottledOccurred: true,
+			Flags: []string{"under-voltage-occurred", "throttled-occurred"},
+		},
+	}
+	mockPowerPort.On("GetPower").Return(powerData, nil)
+
+	powerHandler := handler.NewPowerHandler(mockPowerPort)
+
+	req, err := http.NewRequest("GET", "/v1/power", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	powerHandler.GetPowerInfo(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responsePower domain.Power
+	err = json.NewDecoder(rr.Body).Decode(&responsePower)
+	assert.NoError(t, err)
+
+	assert.Equal(t, powerData, responsePower)
+	mockPowerPort.AssertExpectations(t)
+}
+
+func TestGetPower_Error(t *testing.T) {
+
+	mockPowerPort := new(MockPowerPort)
+	mockPowerPort.On("GetPower").Return(domain.Power{}, assert.AnError)
+
+	powerHandler := handler.NewPowerHandler(mockPowerPort)
+
+	req, err := http.NewRequest("GET", "/v1/power", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	powerHandler.GetPowerInfo(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve power info")
+	mockPowerPort.AssertExpectations(t)
+}
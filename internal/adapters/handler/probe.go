@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type ProbeHandler struct {
+	ProbeService ports.ProbesPort
+}
+
+func NewProbeHandler(service ports.ProbesPort) *ProbeHandler {
+	return &ProbeHandler{ProbeService: service}
+}
+
+func (h *ProbeHandler) GetProbes(w http.ResponseWriter, r *http.Request) {
+	probes, err := h.ProbeService.GetProbes()
+	if err != nil {
+		log.Printf("Error retrieving probes info: %v", err)
+		http.Error(w, "Failed to retrieve probes info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Probes info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(probes)
+}
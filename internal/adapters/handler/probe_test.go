@@ -0,0 +1,78 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProbesPort struct {
+	mock.Mock
+}
+
+func (m *MockProbesPort) GetProbes() ([]domain.Probe, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Probe), args.Error(1)
+}
+
+func TestGetProbes_Success(t *testing.T) {
+
+	mockProbesPort := new(MockProbesPort)
+	lastAttempt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	latency := 0.012
+	probeData := []domain.Probe{
+		{
+			Name: "backend", Type: "http", Target: "https://backend/health", Interval: 60,
+			Up: true, Attempts: 20, Failures: 1, Loss: 5, Latency: &latency, AverageLatency: &latency,
+			Detail: "200 OK", LastAttempt: &lastAttempt, LastSuccess: &lastAttempt,
+		},
+		{Name: "gateway", Type: "icmp", Target: "192.168.1.1", Interval: 30},
+	}
+	mockProbesPort.On("GetProbes").Return(probeData, nil)
+
+	probeHandler := handler.NewProbeHandler(mockProbesPort)
+
+	req, err := http.NewRequest("GET", "/v1/probes", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	probeHandler.GetProbes(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"LastSuccess":null`)
+
+	var responseProbes []domain.Probe
+	err = json.NewDecoder(rr.Body).Decode(&responseProbes)
+	assert.NoError(t, err)
+
+	assert.Equal(t, probeData, responseProbes)
+	mockProbesPort.AssertExpectations(t)
+}
+
+func TestGetProbes_Error(t *testing.T) {
+
+	mockProbesPort := new(MockProbesPort)
+	mockProbesPort.On("GetProbes").Return([]domain.Probe{}, assert.AnError)
+
+	probeHandler := handler.NewProbeHandler(mockProbesPort)
+
+	req, err := http.NewRequest("GET", "/v1/probes", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	probeHandler.GetProbes(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve probes info")
+	mockProbesPort.AssertExpectations(t)
+}
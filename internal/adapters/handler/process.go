@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+const defaultProcessLimit = 10
+
+type ProcessHandler struct {
+	ProcessService ports.TopProcessesPort
+}
+
+func NewProcessHandler(service ports.TopProcessesPort) *ProcessHandler {
+	return &ProcessHandler{ProcessService: service}
+}
+
+func (h *ProcessHandler) GetProcesses(w http.ResponseWriter, r *http.Request) {
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy == "" {
+		sortBy = "cpu"
+	}
+	if !slices.Contains(domain.ProcessSortKeys, sortBy) {
+		http.Error(w, fmt.Sprintf("Invalid sort, use one of %s", strings.Join(domain.ProcessSortKeys, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultProcessLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid limit, use a number of processes or 0 for all", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	processes, err := h.ProcessService.GetTopProcesses(sortBy, limit)
+	if err != nil {
+		log.Printf("Error retrieving processes info: %v", err)
+		http.Error(w, "Failed to retrieve processes info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Processes info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(processes)
+}
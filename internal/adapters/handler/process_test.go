@@ -0,0 +1,92 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTopProcessesPort struct {
+	mock.Mock
+}
+
+func (m *MockTopProcessesPort) GetTopProcesses(sortBy string, limit int) ([]domain.Process, error) {
+	args := m.Called(sortBy, limit)
+	return args.Get(0).([]domain.Process), args.Error(1)
+}
+
+func TestGetProcesses_Success(t *testing.T) {
+
+	mockProcessPort := new(MockTopProcessesPort)
+	processData := []domain.Process{
+		{PID: 512, PPID: 1, Name: "python3", User: "pi", UID: 1000, State: "R", StateName: "running",
+			RSS: 52 << 20, Threads: 4, CPUPercent: 95, Command: "/usr/bin/python3 app.py"},
+	}
+	mockProcessPort.On("GetTopProcesses", "memory", 5).Return(processData, nil)
+	mockProcessPort.On("GetTopProcesses", "cpu", 10).Return(processData, nil)
+
+	processHandler := handler.NewProcessHandler(mockProcessPort)
+
+	for _, url := range []string{"/v1/processes?sort=memory&limit=5", "/v1/processes"} {
+		req, err := http.NewRequest("GET", url, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+
+		processHandler.GetProcesses(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var responseProcesses []domain.Process
+		err = json.NewDecoder(rr.Body).Decode(&responseProcesses)
+		assert.NoError(t, err)
+
+		assert.Equal(t, processData, responseProcesses)
+	}
+	mockProcessPort.AssertExpectations(t)
+}
+
+func TestGetProcesses_BadRequest(t *testing.T) {
+
+	mockProcessPort := new(MockTopProcessesPort)
+
+	processHandler := handler.NewProcessHandler(mockProcessPort)
+
+	for _, url := range []string{"/v1/processes?sort=size", "/v1/processes?limit=-1", "/v1/processes?limit=ten"} {
+		req, err := http.NewRequest("GET", url, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+
+		processHandler.GetProcesses(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+	}
+	mockProcessPort.AssertNotCalled(t, "GetTopProcesses", mock.Anything, mock.Anything)
+}
+
+func TestGetProcesses_Error(t *testing.T) {
+
+	mockProcessPort := new(MockTopProcessesPort)
+	mockProcessPort.On("GetTopProcesses", "cpu", 10).Return([]domain.Process{}, assert.AnError)
+
+	processHandler := handler.NewProcessHandler(mockProcessPort)
+
+	req, err := http.NewRequest("GET", "/v1/processes", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	processHandler.GetProcesses(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve processes info")
+	mockProcessPort.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type RouteHandler struct {
+	RouteService ports.RoutePort
+}
+
+func NewRouteHandler(service ports.RoutePort) *RouteHandler {
+	return &RouteHandler{RouteService: service}
+}
+
+func (h *RouteHandler) GetRoutes(w http.ResponseWriter, r *http.Request) {
+	routing, err := h.RouteService.GetRouting()
+	if err != nil {
+		log.Printf("Error retrieving routes info: %v", err)
+		http.Error(w, "Failed to retrieve routes info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Routes info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(routing)
+}
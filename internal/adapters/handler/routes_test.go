@@ -0,0 +1,76 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRoutePort struct {
+	mock.Mock
+}
+
+func (m *MockRoutePort) GetRouting() (domain.Routing, error) {
+	args := m.Called()
+	return args.Get(0).(domain.Routing), args.Error(1)
+}
+
+func TestGetRoutes_Success(t *testing.T) {
+
+	mockRoutePort := new(MockRoutePort)
+	defaultRoute := domain.Route{Family: "inet", Destination: "0.0.0.0/0", Gateway: "192.168.1.1", Interface: "eth0", Metric: 100, Default: true}
+	routingData := domain.Routing{
+		Routes: []domain.Route{
+			defaultRoute,
+			{Family: "inet", Destination: "192.168.1.0/24", Interface: "eth0", Metric: 100},
+		},
+		DefaultGateway: &defaultRoute,
+		Resolver:       domain.Resolver{Nameservers: []string{"192.168.1.1"}, Search: []string{"home.lan"}, Options: []string{}},
+	}
+	mockRoutePort.On("GetRouting").Return(routingData, nil)
+
+	routeHandler := handler.NewRouteHandler(mockRoutePort)
+
+	req, err := http.NewRequest("GET", "/v1/network/routes", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	routeHandler.GetRoutes(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"DefaultGateway6":null`)
+
+	var responseRouting domain.Routing
+	err = json.NewDecoder(rr.Body).Decode(&responseRouting)
+	assert.NoError(t, err)
+
+	assert.Equal(t, routingData, responseRouting)
+	mockRoutePort.AssertExpectations(t)
+}
+
+func TestGetRoutes_Error(t *testing.T) {
+
+	mockRoutePort := new(MockRoutePort)
+	mockRoutePort.On("GetRouting").Return(domain.Routing{}, assert.AnError)
+
+	routeHandler := handler.NewRouteHandler(mockRoutePort)
+
+	req, err := http.NewRequest("GET", "/v1/network/routes", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	routeHandler.GetRoutes(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve routes info")
+	mockRoutePort.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type SensorHandler struct {
+	SensorService ports.SensorPort
+}
+
+func NewSensorHandler(service ports.SensorPort) *SensorHandler {
+	return &SensorHandler{SensorService: service}
+}
+
+func (h *SensorHandler) GetSensors(w http.ResponseWriter, r *http.Request) {
+	sensors, err := h.SensorService.GetSensors()
+	if err != nil {
+		log.Printf("Error retrieving sensors info: %v", err)
+		http.Error(w, "Failed to retrieve sensors info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Sensors info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sensors)
+}
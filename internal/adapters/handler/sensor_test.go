@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSensorPort struct {
+	mock.Mock
+}
+
+func (m *MockSensorPort) GetSensors() ([]domain.Sensor, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Sensor), args.Error(1)
+}
+
+func TestGetSensors_Success(t *testing.T) {
+
+	mockSensorPort := new(MockSensorPort)
+	sensorData := []domain.Sensor{
+		{Chip: "cpu_thermal", Device: "hwmon0", Name: "temp1", Type: "temperature", Value: 52.35, Unit: "°C"},
+		{Chip: "pwmfan", Device: "hwmon3", Name: "fan1", Type: "fan", Value: 2945, Unit: "RPM"},
+	}
+	mockSensorPort.On("GetSensors").Return(sensorData, nil)
+
+	sensorHandler := handler.NewSensorHandler(mockSensorPort)
+
+	req, err := http.NewRequest("GET", "/v1/sensors", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	sensorHandler.GetSensors(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responseSensors []domain.Sensor
+	err = json.NewDecoder(rr.Body).Decode(&responseSensors)
+	assert.NoError(t, err)
+
+	assert.Equal(t, sensorData, responseSensors)
+	mockSensorPort.AssertExpectations(t)
+}
+
+func TestGetSensors_Error(t *testing.T) {
+
+	mockSensorPort := new(MockSensorPort)
+	mockSensorPort.On("GetSensors").Return([]domain.Sensor{}, assert.AnError)
+
+	sensorHandler := handler.NewSensorHandler(mockSensorPort)
+
+	req, err := http.NewRequest("GET", "/v1/sensors", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	sensorHandler.GetSensors(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve sensors info")
+	mockSensorPort.AssertExpectations(t)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type SocketHandler struct {
+	SocketService ports.SocketsPort
+}
+
+func NewSocketHandler(service ports.SocketsPort) *SocketHandler {
+	return &SocketHandler{SocketService: service}
+}
+
+func (h *SocketHandler) GetSockets(w http.ResponseWriter, r *http.Request) {
+	var filter domain.SocketFilter
+	if value := r.URL.Query().Get("state"); value != "" {
+		for _, state := range strings.Split(strings.ToUpper(value), ",") {
+			if !slices.Contains(domain.SocketStates, state) {
+				http.Error(w, fmt.Sprintf("Invalid state, use any of %s", strings.Join(domain.SocketStates, ", ")), http.StatusBadRequest)
+				return
+			}
+			filter.States = append(filter.States, state)
+		}
+	}
+	if value := r.URL.Query().Get("port"); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			http.Error(w, "Invalid port, use a number between 1 and 65535", http.StatusBadRequest)
+			return
+		}
+		filter.Port = port
+	}
+
+	sockets, err := h.SocketService.GetSockets(filter)
+	if err != nil {
+		log.Printf("Error retrieving sockets info: %v", err)
+		http.Error(w, "Failed to retrieve sockets info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Sockets info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sockets)
+}
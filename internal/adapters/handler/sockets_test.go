@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSocketsPort struct {
+	mock.Mock
+}
+
+func (m *MockSocketsPort) GetSockets(filter domain.SocketFilter) ([]domain.Socket, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]domain.Socket), args.Error(1)
+}
+
+func TestGetSockets_Success(t *testing.T) {
+
+	mockSocketsPort := new(MockSocketsPort)
+	socketData := []domain.Socket{
+		{
+			Protocol: "tcp", LocalAddress: "0.0.0.0", LocalPort: 22, RemoteAddress: "0.0.0.0", State: "LISTEN", Inode: 1001,
+			Processes: []domain.SocketProcess{{PID: 101, Name: "sshd"}},
+		},
+	}
+	mockSocketsPort.On("GetSockets", domain.SocketFilter{States: []string{"LISTEN", "UNCONN"}, Port: 22}).Return(socketData, nil)
+	mockSocketsPort.On("GetSockets", domain.SocketFilter{}).Return(socketData, nil)
+
+	socketHandler := handler.NewSocketHandler(mockSocketsPort)
+
+	for _, url := range []string{"/v1/network/sockets?state=listen,UNCONN&port=22", "/v1/network/sockets"} {
+		req, err := http.NewRequest("GET", url, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+
+		socketHandler.GetSockets(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var responseSockets []domain.Socket
+		err = json.NewDecoder(rr.Body).Decode(&responseSockets)
+		assert.NoError(t, err)
+
+		assert.Equal(t, socketData, responseSockets)
+	}
+	mockSocketsPort.AssertExpectations(t)
+}
+
+func TestGetSockets_BadRequest(t *testing.T) {
+
+	mockSocketsPort := new(MockSocketsPort)
+
+	socketHandler := handler.NewSocketHandler(mockSocketsPort)
+
+	for _, url := range []string{"/v1/network/sockets?state=open", "/v1/network/sockets?state=LISTEN,", "/v1/network/sockets?port=0", "/v1/network/sockets?port=ssh"} {
+		req, err := http.NewRequest("GET", url, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+
+		socketHandler.GetSockets(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+	}
+	mockSocketsPort.AssertNotCalled(t, "GetSockets", mock.Anything)
+}
+
+func TestGetSockets_Error(t *testing.T) {
+
+	mockSocketsPort := new(MockSocketsPort)
+	mockSocketsPort.On("GetSockets", domain.SocketFilter{}).Return([]domain.Socket{}, assert.AnError)
+
+	socketHandler := handler.NewSocketHandler(mockSocketsPort)
+
+	req, err := http.NewRequest("GET", "/v1/network/sockets", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	socketHandler.GetSockets(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve sockets info")
+	mockSocketsPort.AssertExpectations(t)
+}
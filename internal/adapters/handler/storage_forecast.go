@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type StorageForecastHandler struct {
+	StorageForecastService ports.StorageForecastPort
+}
+
+func NewStorageForecastHandler(service ports.StorageForecastPort) *StorageForecastHandler {
+	return &StorageForecastHandler{StorageForecastService: service}
+}
+
+func (h *StorageForecastHandler) GetStorageForecast(w http.ResponseWriter, r *http.Request) {
+	forecasts, err := h.StorageForecastService.GetForecasts()
+	if err != nil {
+		log.Printf("Error retrieving storage forecast info: %v", err)
+		http.Error(w, "Failed to retrieve storage forecast info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Storage forecast info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forecasts)
+}
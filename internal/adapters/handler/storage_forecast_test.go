@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStorageForecastPort struct {
+	mock.Mock
+}
+
+func (m *MockStorageForecastPort) GetForecasts() ([]domain.UsageForecast, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.UsageForecast), args.Error(1)
+}
+
+func TestGetStorageForecast_Success(t *testing.T) {
+
+	mockStorageForecastPort := new(MockStorageForecastPort)
+	rate := float64(1 << 30)
+	seconds := float64(38 * 24 * 3600)
+	full := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	forecastData := []domain.UsageForecast{
+		{
+			MountPoint: "/", Partition: "mmcblk0p2", Total: 50 << 30, Used: 12 << 30, Free: 38 << 30,
+			Samples: 49, WindowSeconds: 48 * 3600, FillRate: &rate, SecondsUntilFull: &seconds, EstimatedFull: &full,
+		},
+		{
+			MountPoint: "/boot/firmware", Partition: "mmcblk0p1", Total: 512 << 20, Used: 64 << 20, Free: 448 << 20,
+			Samples: 1,
+		},
+	}
+	mockStorageForecastPort.On("GetForecasts").Return(forecastData, nil)
+
+	storageForecastHandler := handler.NewStorageForecastHandler(mockStorageForecastPort)
+
+	req, err := http.NewRequest("GET", "/v1/storage/forecast", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	storageForecastHandler.GetStorageForecast(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"FillRate":null`)
+
+	var responseForecasts []domain.UsageForecast
+	err = json.NewDecoder(rr.Body).Decode(&responseForecasts)
+	assert.NoError(t, err)
+
+	assert.Equal(t, forecastData, responseForecasts)
+	mockStorageForecastPort.AssertExpectations(t)
+}
+
+func TestGetStorageForecast_Error(t *testing.T) {
+
+	mockStorageForecastPort := new(MockStorageForecastPort)
+	mockStorageForecastPort.On("GetForecasts").Return([]domain.UsageForecast{}, assert.AnError)
+
+	storageForecastHandler := handler.NewStorageForecastHandler(mockStorageForecastPort)
+
+	req, err := http.NewRequest("GET", "/v1/storage/forecast", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	storageForecastHandler.GetStorageForecast(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve storage forecast info")
+	mockStorageForecastPort.AssertExpectations(t)
+}
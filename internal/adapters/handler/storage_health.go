@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type StorageHealthHandler struct {
+	StorageHealthService ports.StorageHealthPort
+}
+
+func NewStorageHealthHandler(service ports.StorageHealthPort) *StorageHealthHandler {
+	return &StorageHealthHandler{StorageHealthService: service}
+}
+
+func (h *StorageHealthHandler) GetStorageHealth(w http.ResponseWriter, r *http.Request) {
+	devices, err := h.StorageHealthService.GetDevicesHealth()
+	if err != nil {
+		log.Printf("Error retrieving storage health info: %v", err)
+		http.Error(w, "Failed to retrieve storage health info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Storage health info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(devices)
+}
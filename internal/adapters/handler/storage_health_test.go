@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStorageHealthPort struct {
+	mock.Mock
+}
+
+func (m *MockStorageHealthPort) GetDevicesHealth() ([]domain.DeviceHealth, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.DeviceHealth), args.Error(1)
+}
+
+func TestGetStorageHealth_Success(t *testing.T) {
+
+	mockStorageHealthPort := new(MockStorageHealthPort)
+	wear := 20
+	healthData := []domain.DeviceHealth{
+		{
+			Device: "mmcblk0", Type: "MMC", Source: "sysfs", Model: "DG4016", Serial: "0x9a4f51c2",
+			ManufacturerID: "0x000045", OEMID: "0x0100", Date: "11/2020", WearPercent: &wear, PreEOL: "normal",
+		},
+	}
+	mockStorageHealthPort.On("GetDevicesHealth").Return(healthData, nil)
+
+	storageHealthHandler := handler.NewStorageHealthHandler(mockStorageHealthPort)
+
+	req, err := http.NewRequest("GET", "/v1/storage/health", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	storageHealthHandler.GetStorageHealth(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"Temperature":null`)
+
+	var responseHealth []domain.DeviceHealth
+	err = json.NewDecoder(rr.Body).Decode(&responseHealth)
+	assert.NoError(t, err)
+
+	assert.Equal(t, healthData, responseHealth)
+	mockStorageHealthPort.AssertExpectations(t)
+}
+
+func TestGetStorageHealth_Error(t *testing.T) {
+
+	mockStorageHealthPort := new(MockStorageHealthPort)
+	mockStorageHealthPort.On("GetDevicesHealth").Return([]domain.DeviceHealth{}, assert.AnError)
+
+	storageHealthHandler := handler.NewStorageHealthHandler(mockStorageHealthPort)
+
+	req, err := http.NewRequest("GET", "/v1/storage/health", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	storageHealthHandler.GetStorageHealth(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve storage health info")
+	mockStorageHealthPort.AssertExpectations(t)
+}
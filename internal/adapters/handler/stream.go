@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+
+	"github.com/gorilla/websocket"
+)
+
+// StreamMessage is sent for every collector on each tick. Data holds the same
+// JSON the collector's own endpoint returns.
+type StreamMessage struct {
+	Collector string
+	Timestamp time.Time
+	Data      any    `json:",omitempty"`
+	Error     string `json:",omitempty"`
+}
+
+// StreamSubscription is what a client asks for: which collectors, how often.
+// WebSocket clients may send it as JSON at any time to change it.
+type StreamSubscription struct {
+	Collectors []string
+	Interval   string
+}
+
+type StreamHandler struct {
+	Collectors      ports.CollectorPort
+	MinInterval     time.Duration
+	DefaultInterval time.Duration
+	upgrader        websocket.Upgrader
+}
+
+func NewStreamHandler(collectors ports.CollectorPort, minInterval, defaultInterval time.Duration) *StreamHandler {
+	return &StreamHandler{
+		Collectors:      collectors,
+		MinInterval:     minInterval,
+		DefaultInterval: defaultInterval,
+	}
+}
+
+// subscribe validates a subscription, defaulting to every collector at the
+// default interval, and clamping the interval to the server minimum
+func (h *StreamHandler) subscribe(sub StreamSubscription) ([]string, time.Duration, error) {
+	available := h.Collectors.Names()
+
+	collectors := available
+	if len(sub.Collectors) > 0 {
+		collectors = nil
+		for _, name := range sub.Collectors {
+			name = strings.TrimSpace(name)
+			if name == "" || slices.Contains(collectors, name) {
+				continue
+			}
+			if !slices.Contains(available, name) {
+				return nil, 0, fmt.Errorf("unknown collector %q", name)
+			}
+			collectors = append(collectors, name)
+		}
+	}
+
+	interval := h.DefaultInterval
+	if sub.Interval != "" {
+		parsed, err := time.ParseDuration(sub.Interval)
+		if err != nil {
+			// Plain numbers are seconds
+			seconds, convErr := strconv.ParseFloat(sub.Interval, 64)
+			if convErr != nil {
+				return nil, 0, fmt.Errorf("invalid interval %q", sub.Interval)
+			}
+			parsed = time.Duration(seconds * float64(time.Second))
+		}
+		interval = parsed
+	}
+	if interval < h.MinInterval {
+		interval = h.MinInterval
+	}
+
+	return collectors, interval, nil
+}
+
+func (h *StreamHandler) subscriptionFromQuery(r *http.Request) StreamSubscription {
+	sub := StreamSubscription{Interval: r.URL.Query().Get("interval")}
+	if collectors := r.URL.Query().Get("collectors"); collectors != "" {
+		sub.Collectors = strings.Split(collectors, ",")
+	}
+	return sub
+}
+
+func (h *StreamHandler) collect(collectors []string) []StreamMessage {
+	timestamp := time.Now()
+	messages := make([]StreamMessage, 0, len(collectors))
+	for _, name := range collectors {
+		message := StreamMessage{Collector: name, Timestamp: timestamp}
+		data, err := h.Collectors.Collect(name)
+		if err != nil {
+			log.Printf("Error collecting %s for stream: %v", name, err)
+			message.Error = "Failed to retrieve " + name
+		} else {
+			message.Data = data
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// GetStream pushes the subscribed collectors as Server-Sent Events, one event
+// per collector named after it, until the client goes away
+func (h *StreamHandler) GetStream(w http.ResponseWriter, r *http.Request) {
+	collectors, interval, err := h.subscribe(h.subscriptionFromQuery(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	log.Printf("SSE stream opened for %v every %s", collectors, interval)
+	defer log.Printf("SSE stream closed")
+
+	// Tell the client how long to wait before reconnecting
+	fmt.Fprintf(w, "retry: %d\n\n", interval.Milliseconds())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, message := range h.collect(collectors) {
+			payload, err := json.Marshal(message)
+			if err != nil {
+				log.Printf("Error encoding stream message: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", message.Collector, payload); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetStreamWS pushes the subscribed collectors as JSON messages over a
+// WebSocket. The client can replace its subscription by sending a new one.
+func (h *StreamHandler) GetStreamWS(w http.ResponseWriter, r *http.Request) {
+	collectors, interval, err := h.subscribe(h.subscriptionFromQuery(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied to the client
+		log.Printf("Error upgrading to WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	log.Printf("WebSocket stream opened for %v every %s", collectors, interval)
+	defer log.Printf("WebSocket stream closed")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// Only the reader notices a closed connection, so it cancels the writer
+	subscriptions := make(chan StreamSubscription)
+	go func() {
+		defer cancel()
+		for {
+			_, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var sub StreamSubscription
+			if err := json.Unmarshal(payload, &sub); err != nil {
+				log.Printf("Ignoring invalid stream subscription: %v", err)
+				continue
+			}
+			select {
+			case subscriptions <- sub:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, message := range h.collect(collectors) {
+			conn.SetWriteDeadline(time.Now().Add(interval + 10*time.Second))
+			if err := conn.WriteJSON(message); err != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			return
+		case sub := <-subscriptions:
+			newCollectors, newInterval, err := h.subscribe(sub)
+			if err != nil {
+				conn.WriteJSON(StreamMessage{Timestamp: time.Now(), Error: err.Error()})
+				continue
+			}
+			collectors, interval = newCollectors, newInterval
+			ticker.Reset(interval)
+		case <-ticker.C:
+		}
+	}
+}
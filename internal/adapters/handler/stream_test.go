@@ -0,0 +1,142 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCollectors struct {
+	mock.Mock
+}
+
+func (m *MockCollectors) Names() []string {
+	return []string{"cpu", "ram"}
+}
+
+func (m *MockCollectors) Collect(name string) (any, error) {
+	args := m.Called(name)
+	return args.Get(0), args.Error(1)
+}
+
+func newStreamServer(collectors *MockCollectors) *httptest.Server {
+	streamHandler := handler.NewStreamHandler(collectors, 10*time.Millisecond, time.Second)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/stream", streamHandler.GetStream)
+	mux.HandleFunc("/v1/stream/ws", streamHandler.GetStreamWS)
+	return httptest.NewServer(mux)
+}
+
+func TestGetStream_SSE(t *testing.T) {
+
+	collectors := new(MockCollectors)
+	collectors.On("Collect", "cpu").Return(domain.CPU{LoadAvg1Min: 0.5}, nil)
+	collectors.On("Collect", "ram").Return(nil, assert.AnError)
+
+	server := newStreamServer(collectors)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Below the server minimum, so it gets clamped to 10ms
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/v1/stream?collectors=cpu,ram&interval=1ms", nil)
+	assert.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Read two rounds of events
+	var events []string
+	var messages []handler.StreamMessage
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && len(messages) < 4 {
+		line := scanner.Text()
+		if line == "retry: 10" {
+			continue
+		}
+		if event, found := strings.CutPrefix(line, "event: "); found {
+			events = append(events, event)
+		}
+		if data, found := strings.CutPrefix(line, "data: "); found {
+			var message handler.StreamMessage
+			assert.NoError(t, json.Unmarshal([]byte(data), &message))
+			messages = append(messages, message)
+		}
+	}
+	cancel()
+
+	assert.Equal(t, []string{"cpu", "ram", "cpu", "ram"}, events)
+	assert.Equal(t, map[string]any{"LoadAvg1Min": 0.5, "LoadAvg5Min": 0.0, "LoadAvg15Min": 0.0}, messages[0].Data)
+	assert.Equal(t, "Failed to retrieve ram", messages[1].Error)
+	assert.Nil(t, messages[1].Data)
+}
+
+func TestGetStream_BadSubscription(t *testing.T) {
+
+	collectors := new(MockCollectors)
+	server := newStreamServer(collectors)
+	defer server.Close()
+
+	for _, query := range []string{"collectors=gpu", "interval=soon"} {
+		resp, err := http.Get(server.URL + "/v1/stream?" + query)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+	collectors.AssertNotCalled(t, "Collect", mock.Anything)
+}
+
+func TestGetStreamWS(t *testing.T) {
+
+	collectors := new(MockCollectors)
+	collectors.On("Collect", "cpu").Return(domain.CPU{LoadAvg1Min: 0.5}, nil)
+	collectors.On("Collect", "ram").Return(domain.RAM{Total: 4096}, nil)
+
+	server := newStreamServer(collectors)
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/stream/ws?collectors=cpu&interval=1h"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var message handler.StreamMessage
+	assert.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, "cpu", message.Collector)
+
+	// Switch the subscription, which is applied right away
+	assert.NoError(t, conn.WriteJSON(handler.StreamSubscription{Collectors: []string{"ram"}, Interval: "10ms"}))
+	for message.Collector != "ram" {
+		assert.NoError(t, conn.ReadJSON(&message))
+	}
+	assert.Equal(t, map[string]any{"Total": 4096.0, "Available": 0.0, "Free": 0.0, "Used": 0.0}, message.Data)
+
+	// An unknown collector is reported without closing the stream
+	assert.NoError(t, conn.WriteJSON(handler.StreamSubscription{Collectors: []string{"gpu"}}))
+	for message.Error == "" {
+		assert.NoError(t, conn.ReadJSON(&message))
+	}
+	assert.Contains(t, message.Error, "unknown collector")
+
+	assert.NoError(t, conn.WriteMessage(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+}
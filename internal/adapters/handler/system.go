@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type SystemHandler struct {
+	SystemService ports.SystemPort
+}
+
+func NewSystemHandler(service ports.SystemPort) *SystemHandler {
+	return &SystemHandler{SystemService: service}
+}
+
+func (h *SystemHandler) GetSystemInfo(w http.ResponseWriter, r *http.Request) {
+	systemInfo, err := h.SystemService.GetSystemInfo()
+	if err != nil {
+		log.Printf("Error retrieving system info: %v", err)
+		http.Error(w, "Failed to retrieve system info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("System info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(systemInfo)
+}
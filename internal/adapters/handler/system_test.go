@@ -0,0 +1,76 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSystemPort struct {
+	mock.Mock
+}
+
+func (m *MockSystemPort) GetSystemInfo() (domain.System, error) {
+	args := m.Called()
+	return args.Get(0).(domain.System), args.Error(1)
+}
+
+func TestGetSystemInfo_Success(t *testing.T) {
+
+	mockSystemPort := new(MockSystemPort)
+	systemData := domain.System{
+		Hostname:      "raspberrypi",
+		Kernel:        domain.Kernel{Name: "Linux", Release: "6.6.31+rpt-rpi-v8"},
+		OS:            domain.OSRelease{ID: "debian", Fields: map[string]string{"ID": "debian"}},
+		Architecture:  "aarch64",
+		UptimeSeconds: 350735.47,
+		BootTime:      time.Unix(1718000000, 0).UTC(),
+		MachineID:     "4c4c4544003957108052b4c04f384b32",
+	}
+	mockSystemPort.On("GetSystemInfo").Return(systemData, nil)
+
+	systemHandler := handler.NewSystemHandler(mockSystemPort)
+
+	req, err := http.NewRequest("GET", "/v1/system", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	systemHandler.GetSystemInfo(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responseSystem domain.System
+	err = json.NewDecoder(rr.Body).Decode(&responseSystem)
+	assert.NoError(t, err)
+
+	assert.Equal(t, systemData, responseSystem)
+	mockSystemPort.AssertExpectations(t)
+}
+
+func TestGetSystemInfo_Error(t *testing.T) {
+
+	mockSystemPort := new(MockSystemPort)
+	mockSystemPort.On("GetSystemInfo").Return(domain.System{}, assert.AnError)
+
+	systemHandler := handler.NewSystemHandler(mockSystemPort)
+
+	req, err := http.NewRequest("GET", "/v1/system", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	systemHandler.GetSystemInfo(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve system info")
+	mockSystemPort.AssertExpectations(t)
+}
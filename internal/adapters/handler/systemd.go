@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type SystemdHandler struct {
+	SystemdService ports.ServicesPort
+}
+
+func NewSystemdHandler(service ports.ServicesPort) *SystemdHandler {
+	return &SystemdHandler{SystemdService: service}
+}
+
+func (h *SystemdHandler) GetServices(w http.ResponseWriter, r *http.Request) {
+	services, err := h.SystemdService.GetServices()
+	if err != nil {
+		log.Printf("Error retrieving services info: %v", err)
+		http.Error(w, "Failed to retrieve services info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Services info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(services)
+}
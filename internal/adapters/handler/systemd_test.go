@@ -0,0 +1,77 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockServicesPort struct {
+	mock.Mock
+}
+
+func (m *MockServicesPort) GetServices() (domain.Services, error) {
+	args := m.Called()
+	return args.Get(0).(domain.Services), args.Error(1)
+}
+
+func TestGetServices_Success(t *testing.T) {
+
+	mockServicesPort := new(MockServicesPort)
+	servicesData := domain.Services{
+		Units: []domain.SystemdUnit{
+			{Name: "ssh.service", Description: "OpenBSD Secure Shell server", LoadState: "loaded",
+				ActiveState: "active", SubState: "running", UnitFileState: "enabled", MainPID: 512,
+				ActiveSince: time.Date(2024, 6, 10, 6, 14, 0, 0, time.UTC)},
+		},
+		Failed: []domain.SystemdUnit{
+			{Name: "hciuart.service", LoadState: "loaded", ActiveState: "failed", SubState: "failed"},
+		},
+	}
+	mockServicesPort.On("GetServices").Return(servicesData, nil)
+
+	systemdHandler := handler.NewSystemdHandler(mockServicesPort)
+
+	req, err := http.NewRequest("GET", "/v1/services", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	systemdHandler.GetServices(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responseServices domain.Services
+	err = json.NewDecoder(rr.Body).Decode(&responseServices)
+	assert.NoError(t, err)
+
+	assert.Equal(t, servicesData, responseServices)
+	mockServicesPort.AssertExpectations(t)
+}
+
+func TestGetServices_Error(t *testing.T) {
+
+	mockServicesPort := new(MockServicesPort)
+	mockServicesPort.On("GetServices").Return(domain.Services{}, assert.AnError)
+
+	systemdHandler := handler.NewSystemdHandler(mockServicesPort)
+
+	req, err := http.NewRequest("GET", "/v1/services", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	systemdHandler.GetServices(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve services info")
+	mockServicesPort.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+type WatchHandler struct {
+	WatchService ports.WatchPort
+}
+
+func NewWatchHandler(service ports.WatchPort) *WatchHandler {
+	return &WatchHandler{WatchService: service}
+}
+
+func (h *WatchHandler) GetWatchedProcesses(w http.ResponseWriter, r *http.Request) {
+	watched, err := h.WatchService.GetWatchedProcesses()
+	if err != nil {
+		log.Printf("Error retrieving watched processes info: %v", err)
+		http.Error(w, "Failed to retrieve watched processes info", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Watched processes info retrieved successfully")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watched)
+}
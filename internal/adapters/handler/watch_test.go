@@ -0,0 +1,72 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/adapters/handler"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWatchPort struct {
+	mock.Mock
+}
+
+func (m *MockWatchPort) GetWatchedProcesses() ([]domain.WatchedProcess, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.WatchedProcess), args.Error(1)
+}
+
+func TestGetWatchedProcesses_Success(t *testing.T) {
+
+	mockWatchPort := new(MockWatchPort)
+	watchData := []domain.WatchedProcess{
+		{Name: "app", Up: true, PID: 512, Matches: 1, StartTime: time.Unix(1718000060, 0).UTC(),
+			UptimeSeconds: 7140, Restarts: 2, CPUPercent: 12.5, RSS: 52 << 20},
+		{Name: "nginx", Up: false},
+	}
+	mockWatchPort.On("GetWatchedProcesses").Return(watchData, nil)
+
+	watchHandler := handler.NewWatchHandler(mockWatchPort)
+
+	req, err := http.NewRequest("GET", "/v1/watch", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	watchHandler.GetWatchedProcesses(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var responseWatch []domain.WatchedProcess
+	err = json.NewDecoder(rr.Body).Decode(&responseWatch)
+	assert.NoError(t, err)
+
+	assert.Equal(t, watchData, responseWatch)
+	mockWatchPort.AssertExpectations(t)
+}
+
+func TestGetWatchedProcesses_Error(t *testing.T) {
+
+	mockWatchPort := new(MockWatchPort)
+	mockWatchPort.On("GetWatchedProcesses").Return([]domain.WatchedProcess{}, assert.AnError)
+
+	watchHandler := handler.NewWatchHandler(mockWatchPort)
+
+	req, err := http.NewRequest("GET", "/v1/watch", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+
+	watchHandler.GetWatchedProcesses(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to retrieve watched processes info")
+	mockWatchPort.AssertExpectations(t)
+}
@@ -0,0 +1,80 @@
+package output
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/* ******************************************** DISK BUFFER ******************************************** */
+
+// diskBuffer keeps undelivered lines on disk, so they survive both an
+// unreachable server and a restart. It never grows beyond maxBytes: when full,
+// the oldest lines are discarded first.
+type diskBuffer struct {
+	path     string
+	maxBytes int64
+}
+
+func newDiskBuffer(path string, maxBytes int64) *diskBuffer {
+	return &diskBuffer{path: path, maxBytes: maxBytes}
+}
+
+// Lines returns every buffered line, oldest first
+func (b *diskBuffer) Lines() ([]string, error) {
+	file, err := os.Open(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// Replace overwrites the buffer with the given lines, keeping only the newest
+// ones that fit. It returns how many lines were evicted.
+func (b *diskBuffer) Replace(lines []string) (int, error) {
+	var size int64
+	first := len(lines)
+	for first > 0 {
+		lineSize := int64(len(lines[first-1]) + 1)
+		if size+lineSize > b.maxBytes {
+			break
+		}
+		size += lineSize
+		first--
+	}
+	kept := lines[first:]
+
+	if len(kept) == 0 {
+		err := os.Remove(b.path)
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return first, err
+	}
+
+	// Write to a temporary file first so a crash never leaves half a buffer
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return 0, err
+	}
+	tmpPath := b.path + ".tmp"
+	content := strings.Join(kept, "\n") + "\n"
+	if err := os.WriteFile(tmpPath, []byte(content), 0o600); err != nil {
+		return 0, err
+	}
+	return first, os.Rename(tmpPath, b.path)
+}
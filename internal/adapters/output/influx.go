@@ -0,0 +1,301 @@
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/config"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// Keeps each datagram under the usual Ethernet MTU
+const maxUDPPayload = 1400
+
+// permanentError marks a batch the server rejected for its content, so
+// retrying it later would fail again
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func chunkLines(lines []string, size int) [][]string {
+	var chunks [][]string
+	for size < len(lines) {
+		chunks = append(chunks, lines[:size])
+		lines = lines[size:]
+	}
+	if len(lines) > 0 {
+		chunks = append(chunks, lines)
+	}
+	return chunks
+}
+
+/* ************************************* TRANSPORTS ************************************* */
+
+type lineWriter interface {
+	writeLines(lines []string) error
+}
+
+type httpLineWriter struct {
+	client   *http.Client
+	endpoint string
+	headers  map[string]string
+	username string
+	password string
+	gzip     bool
+}
+
+func newHTTPLineWriter(cfg config.InfluxConfig) (*httpLineWriter, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.URL, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &httpLineWriter{
+		client:  &http.Client{Timeout: time.Duration(cfg.Timeout)},
+		headers: map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+		gzip:    cfg.Gzip,
+	}
+
+	query := url.Values{}
+	query.Set("precision", "ns")
+	if cfg.APIVersion == 1 {
+		base.Path += "/write"
+		query.Set("db", cfg.Database)
+		if cfg.RetentionPolicy != "" {
+			query.Set("rp", cfg.RetentionPolicy)
+		}
+		writer.username = cfg.Username
+		writer.password = cfg.Password
+	} else {
+		base.Path += "/api/v2/write"
+		query.Set("org", cfg.Org)
+		query.Set("bucket", cfg.Bucket)
+		if cfg.Token != "" {
+			writer.headers["Authorization"] = "Token " + cfg.Token
+		}
+	}
+	base.RawQuery = query.Encode()
+	writer.endpoint = base.String()
+
+	if cfg.Gzip {
+		writer.headers["Content-Encoding"] = "gzip"
+	}
+	return writer, nil
+}
+
+func (w *httpLineWriter) writeLines(lines []string) error {
+	payload := []byte(strings.Join(lines, "\n") + "\n")
+
+	if w.gzip {
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, err := gz.Write(payload); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		payload = compressed.Bytes()
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+	if w.username != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("influx write failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout {
+		return &permanentError{err: err}
+	}
+	return err
+}
+
+type udpLineWriter struct {
+	address string
+	timeout time.Duration
+}
+
+func (w *udpLineWriter) writeLines(lines []string) error {
+	conn, err := net.DialTimeout("udp", w.address, w.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Pack as many lines as possible in each datagram
+	var packet bytes.Buffer
+	send := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := conn.Write(packet.Bytes())
+		packet.Reset()
+		return err
+	}
+
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+len(line)+1 > maxUDPPayload {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+		packet.WriteString(line)
+		packet.WriteByte('\n')
+	}
+	return send()
+}
+
+/* ******************************************** INFLUX ******************************************** */
+
+// InfluxSink pushes samples to InfluxDB in line protocol. Lines are batched
+// in memory and sent once a batch is full or the flush interval has elapsed.
+// When the server can't be reached they are kept in a bounded disk buffer,
+// if configured, and retried before any newer line.
+type InfluxSink struct {
+	mu            sync.Mutex
+	writer        lineWriter
+	buffer        *diskBuffer
+	batchSize     int
+	flushInterval time.Duration
+	pending       []string
+	lastFlush     time.Time
+	now           func() time.Time
+}
+
+func NewInfluxSink(cfg config.InfluxConfig) (*InfluxSink, error) {
+	target, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var writer lineWriter
+	switch target.Scheme {
+	case "http", "https":
+		writer, err = newHTTPLineWriter(cfg)
+		if err != nil {
+			return nil, err
+		}
+	case "udp":
+		writer = &udpLineWriter{address: target.Host, timeout: time.Duration(cfg.Timeout)}
+	default:
+		return nil, fmt.Errorf("unsupported influx url scheme %q", target.Scheme)
+	}
+
+	return newInfluxSink(writer, cfg), nil
+}
+
+func newInfluxSink(writer lineWriter, cfg config.InfluxConfig) *InfluxSink {
+	sink := &InfluxSink{
+		writer:        writer,
+		batchSize:     cfg.BatchSize,
+		flushInterval: time.Duration(cfg.FlushInterval),
+		now:           time.Now,
+	}
+	if sink.batchSize <= 0 {
+		sink.batchSize = 5000
+	}
+	if cfg.BufferPath != "" {
+		sink.buffer = newDiskBuffer(cfg.BufferPath, cfg.BufferMaxBytes)
+	}
+	sink.lastFlush = sink.now()
+	return sink
+}
+
+func (s *InfluxSink) Write(samples []domain.Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = append(s.pending, EncodeLines(samples)...)
+	if len(s.pending) < s.batchSize && s.now().Sub(s.lastFlush) < s.flushInterval {
+		return nil
+	}
+	return s.flush()
+}
+
+// Close sends anything still pending
+func (s *InfluxSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.flush()
+}
+
+func (s *InfluxSink) flush() error {
+	s.lastFlush = s.now()
+	pending := s.pending
+	s.pending = nil
+
+	if s.buffer != nil {
+		buffered, err := s.buffer.Lines()
+		if err != nil {
+			log.Printf("Error reading influx retry buffer: %v", err)
+		}
+		pending = append(buffered, pending...)
+	}
+
+	sent, err := s.send(pending)
+	if s.buffer == nil {
+		if err != nil {
+			log.Printf("Dropping %d influx lines: %v", len(pending)-sent, err)
+		}
+		return err
+	}
+
+	evicted, bufErr := s.buffer.Replace(pending[sent:])
+	if evicted > 0 {
+		log.Printf("Influx retry buffer full, dropped %d oldest lines", evicted)
+	}
+	return errors.Join(err, bufErr)
+}
+
+// send writes lines batch by batch, stopping at the first retryable failure.
+// It returns how many lines don't need to be retried.
+func (s *InfluxSink) send(lines []string) (int, error) {
+	handled := 0
+	for _, batch := range chunkLines(lines, s.batchSize) {
+		err := s.writer.writeLines(batch)
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			log.Printf("Influx rejected %d lines, discarding them: %v", len(batch), err)
+		} else if err != nil {
+			return handled, err
+		}
+		handled += len(batch)
+	}
+	return handled, nil
+}
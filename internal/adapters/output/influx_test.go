@@ -0,0 +1,263 @@
+package output
+
+import (
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/config"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+type recordedRequest struct {
+	path    string
+	query   map[string]string
+	headers http.Header
+	user    string
+	body    string
+}
+
+type fakeInflux struct {
+	mu       sync.Mutex
+	status   int
+	requests []recordedRequest
+}
+
+func (f *fakeInflux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reader = gz
+	}
+	body, _ := io.ReadAll(reader)
+
+	query := map[string]string{}
+	for key := range r.URL.Query() {
+		query[key] = r.URL.Query().Get(key)
+	}
+	user, _, _ := r.BasicAuth()
+	f.requests = append(f.requests, recordedRequest{
+		path:    r.URL.Path,
+		query:   query,
+		headers: r.Header.Clone(),
+		user:    user,
+		body:    string(body),
+	})
+
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeInflux) lines() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var lines []string
+	for _, req := range f.requests {
+		lines = append(lines, strings.Split(strings.TrimSpace(req.body), "\n")...)
+	}
+	return lines
+}
+
+func testSamples(count int) []domain.Sample {
+	samples := make([]domain.Sample, count)
+	for i := range samples {
+		samples[i] = domain.Sample{
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "pi"},
+			Fields:      map[string]any{"load1": float64(i)},
+			Timestamp:   time.Unix(int64(i), 0),
+		}
+	}
+	return samples
+}
+
+/* ******************************************** INFLUX TEST ******************************************** */
+
+func TestInfluxSink_HTTPVersions(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - v1 with basic auth": {
+			"config": config.InfluxConfig{
+				APIVersion: 1, Database: "telegraf", RetentionPolicy: "week",
+				Username: "pi", Password: "secret",
+			},
+			"path":  "/write",
+			"query": map[string]string{"db": "telegraf", "rp": "week", "precision": "ns"},
+		},
+		"Case 2 - v2 with token and gzip": {
+			"config": config.InfluxConfig{
+				APIVersion: 2, Org: "home", Bucket: "pis", Token: "abc", Gzip: true,
+			},
+			"path":  "/api/v2/write",
+			"query": map[string]string{"org": "home", "bucket": "pis", "precision": "ns"},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		server := &fakeInflux{}
+		ts := httptest.NewServer(server)
+
+		cfg := caseData["config"].(config.InfluxConfig)
+		cfg.URL = ts.URL
+		cfg.Timeout = config.Duration(time.Second)
+		cfg.BatchSize = 2
+
+		sink, err := NewInfluxSink(cfg)
+		assert.NoError(t, err)
+
+		assert.NoError(t, sink.Write(testSamples(3)))
+		assert.NoError(t, sink.Close())
+		ts.Close()
+
+		// Three lines with batches of two are two requests
+		assert.Len(t, server.requests, 2)
+		assert.Len(t, server.lines(), 3)
+		for _, req := range server.requests {
+			assert.Equal(t, caseData["path"].(string), req.path)
+			assert.Equal(t, caseData["query"].(map[string]string), req.query)
+			if cfg.APIVersion == 1 {
+				assert.Equal(t, "pi", req.user)
+			} else {
+				assert.Equal(t, "Token abc", req.headers.Get("Authorization"))
+				assert.Equal(t, "gzip", req.headers.Get("Content-Encoding"))
+			}
+		}
+	}
+
+}
+
+func TestInfluxSink_BatchesUntilFlushInterval(t *testing.T) {
+	server := &fakeInflux{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	now := time.Unix(0, 0)
+	sink, err := NewInfluxSink(config.InfluxConfig{
+		URL: ts.URL, APIVersion: 2, BatchSize: 100, FlushInterval: config.Duration(time.Minute),
+	})
+	assert.NoError(t, err)
+	sink.now = func() time.Time { return now }
+	sink.lastFlush = now
+
+	assert.NoError(t, sink.Write(testSamples(1)))
+	assert.Empty(t, server.requests)
+
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, sink.Write(testSamples(1)))
+	assert.Len(t, server.requests, 1)
+	assert.Len(t, server.lines(), 2)
+}
+
+func TestInfluxSink_RetryBuffer(t *testing.T) {
+	server := &fakeInflux{status: http.StatusServiceUnavailable}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	bufferPath := filepath.Join(t.TempDir(), "influx.buffer")
+	sink, err := NewInfluxSink(config.InfluxConfig{
+		URL: ts.URL, APIVersion: 2, BatchSize: 10,
+		BufferPath: bufferPath, BufferMaxBytes: 80,
+	})
+	assert.NoError(t, err)
+
+	// Server down: lines end in the buffer, which only keeps the newest ones
+	assert.Error(t, sink.Write(testSamples(5)))
+	assert.Error(t, sink.Close())
+	buffered, err := newDiskBuffer(bufferPath, 80).Lines()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, buffered)
+	assert.Less(t, len(buffered), 5)
+	assert.Equal(t, "cpu,host=pi load1=4 4000000000", buffered[len(buffered)-1])
+
+	// Server back: buffered lines go first, then the new ones
+	server.mu.Lock()
+	server.status = 0
+	server.requests = nil
+	server.mu.Unlock()
+
+	newSample := testSamples(7)[6:]
+	assert.NoError(t, sink.Write(newSample))
+	assert.NoError(t, sink.Close())
+
+	lines := server.lines()
+	assert.Equal(t, append(buffered, "cpu,host=pi load1=6 6000000000"), lines)
+
+	buffered, err = newDiskBuffer(bufferPath, 80).Lines()
+	assert.NoError(t, err)
+	assert.Empty(t, buffered)
+}
+
+func TestInfluxSink_RejectedBatchIsDropped(t *testing.T) {
+	server := &fakeInflux{status: http.StatusBadRequest}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	bufferPath := filepath.Join(t.TempDir(), "influx.buffer")
+	sink, err := NewInfluxSink(config.InfluxConfig{
+		URL: ts.URL, APIVersion: 2, BatchSize: 1, BufferPath: bufferPath, BufferMaxBytes: 1024,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, sink.Write(testSamples(2)))
+
+	buffered, err := newDiskBuffer(bufferPath, 1024).Lines()
+	assert.NoError(t, err)
+	assert.Empty(t, buffered)
+}
+
+func TestInfluxSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewInfluxSink(config.InfluxConfig{
+		URL: "udp://" + conn.LocalAddr().String(), BatchSize: 1000, Timeout: config.Duration(time.Second),
+	})
+	assert.NoError(t, err)
+
+	// Enough lines to need several datagrams
+	assert.NoError(t, sink.Write(testSamples(100)))
+	assert.NoError(t, sink.Close())
+
+	var received []string
+	packet := make([]byte, 65536)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for len(received) < 100 {
+		n, _, err := conn.ReadFrom(packet)
+		if !assert.NoError(t, err) {
+			break
+		}
+		assert.LessOrEqual(t, n, maxUDPPayload)
+		received = append(received, strings.Split(strings.TrimSpace(string(packet[:n])), "\n")...)
+	}
+	assert.Len(t, received, 100)
+}
+
+func TestNewInfluxSink_InvalidScheme(t *testing.T) {
+	_, err := NewInfluxSink(config.InfluxConfig{URL: "ftp://influx:8086"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// Newlines end a line wherever they are, even escaped, so they are replaced
+// by spaces
+var measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\ `, "\r", `\ `)
+var keyEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\ `, "\r", `\ `)
+var stringFieldEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ", "\r", " ")
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFieldValue(value any) (string, bool) {
+	switch v := value.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return formatFieldValue(float64(v))
+	case int:
+		return strconv.FormatInt(int64(v), 10) + "i", true
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", true
+	case uint64:
+		// Unsigned fields are not supported by InfluxDB v1, keep them signed
+		if v > math.MaxInt64 {
+			v = math.MaxInt64
+		}
+		return strconv.FormatUint(v, 10) + "i", true
+	case bool:
+		return strconv.FormatBool(v), true
+	case string:
+		return `"` + stringFieldEscaper.Replace(v) + `"`, true
+	default:
+		return "", false
+	}
+}
+
+/* ******************************************** LINE PROTOCOL ******************************************** */
+
+// EncodeLine renders a sample as an InfluxDB line protocol line, without the
+// trailing newline. Tags with empty keys or values, and fields with empty keys
+// or of unsupported types are dropped; ok is false when no field is left.
+func EncodeLine(sample domain.Sample) (line string, ok bool) {
+	var b strings.Builder
+
+	b.WriteString(measurementEscaper.Replace(sample.Measurement))
+	for _, key := range sortedKeys(sample.Tags) {
+		value := sample.Tags[key]
+		if key == "" || value == "" {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(keyEscaper.Replace(key))
+		b.WriteByte('=')
+		b.WriteString(keyEscaper.Replace(value))
+	}
+
+	fieldCount := 0
+	for _, key := range sortedKeys(sample.Fields) {
+		value, valid := formatFieldValue(sample.Fields[key])
+		if key == "" || !valid {
+			continue
+		}
+		if fieldCount == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(keyEscaper.Replace(key))
+		b.WriteByte('=')
+		b.WriteString(value)
+		fieldCount++
+	}
+	if fieldCount == 0 {
+		return "", false
+	}
+
+	if !sample.Timestamp.IsZero() {
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(sample.Timestamp.UnixNano(), 10))
+	}
+
+	return b.String(), true
+}
+
+// EncodeLines renders every valid sample, one line each
+func EncodeLines(samples []domain.Sample) []string {
+	lines := make([]string, 0, len(samples))
+	for _, sample := range samples {
+		if line, ok := EncodeLine(sample); ok {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** LINE PROTOCOL TEST ******************************************** */
+
+func TestEncodeLine(t *testing.T) {
+
+	timestamp := time.Unix(1700000000, 123)
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Types and ordering": {
+			"input": domain.Sample{
+				Measurement: "network",
+				Tags:        map[string]string{"interface": "eth0", "host": "pi"},
+				Fields: map[string]any{
+					"rx_bytes": uint64(100),
+					"ratio":    0.5,
+					"up":       true,
+					"state":    "ok",
+				},
+				Timestamp: timestamp,
+			},
+			"expected": `network,host=pi,interface=eth0 ratio=0.5,rx_bytes=100i,state="ok",up=true 1700000000000000123`,
+		},
+		"Case 2 - Escaping": {
+			"input": domain.Sample{
+				Measurement: "my storage,x",
+				Tags:        map[string]string{"mountpoint": "/mnt/my disk", "a=b": "c,d"},
+				Fields:      map[string]any{"note": `say "hi" \o/`},
+			},
+			"expected": `my\ storage\,x,a\=b=c\,d,mountpoint=/mnt/my\ disk note="say \"hi\" \\o/"`,
+		},
+		"Case 3 - Empty tags are skipped": {
+			"input": domain.Sample{
+				Measurement: "cpu",
+				Tags:        map[string]string{"host": ""},
+				Fields:      map[string]any{"load1": 1.0},
+			},
+			"expected": `cpu load1=1`,
+		},
+		"Case 4 - Newlines are replaced, empty keys skipped": {
+			"input": domain.Sample{
+				Measurement: "unit",
+				Tags:        map[string]string{"name": "my\nunit", "": "x"},
+				Fields:      map[string]any{"status": "failed\nat boot", "": int64(1), "up": false},
+			},
+			"expected": `unit,name=my\ unit status="failed at boot",up=false`,
+		},
+		"Case 5 - Big unsigned is clamped": {
+			"input": domain.Sample{
+				Measurement: "ram",
+				Fields:      map[string]any{"total": uint64(math.MaxUint64)},
+			},
+			"expected": `ram total=9223372036854775807i`,
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+		line, ok := EncodeLine(caseData["input"].(domain.Sample))
+		assert.True(t, ok)
+		assert.Equal(t, caseData["expected"].(string), line)
+	}
+
+}
+
+func TestEncodeLine_NoValidFields(t *testing.T) {
+	samples := []domain.Sample{
+		{Measurement: "cpu", Fields: map[string]any{}},
+		{Measurement: "cpu", Fields: map[string]any{"load1": math.NaN(), "other": []int{1}}},
+		{Measurement: "neighbors", Fields: map[string]any{"": int64(2)}},
+	}
+
+	for _, sample := range samples {
+		_, ok := EncodeLine(sample)
+		assert.False(t, ok)
+	}
+	assert.Empty(t, EncodeLines(samples))
+}
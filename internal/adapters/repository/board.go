@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// Tables from the Raspberry Pi documentation about revision codes
+var piModels = map[uint64]string{
+	0x00: "A", 0x01: "B", 0x02: "A+", 0x03: "B+", 0x04: "2B", 0x05: "Alpha",
+	0x06: "CM1", 0x08: "3B", 0x09: "Zero", 0x0a: "CM3", 0x0c: "Zero W",
+	0x0d: "3B+", 0x0e: "3A+", 0x0f: "Internal use only", 0x10: "CM3+", 0x11: "4B",
+	0x12: "Zero 2 W", 0x13: "400", 0x14: "CM4", 0x15: "CM4S", 0x16: "Internal use only",
+	0x17: "5", 0x18: "CM5", 0x19: "500", 0x1a: "CM5 Lite",
+}
+
+var piProcessors = map[uint64]string{
+	0: "BCM2835", 1: "BCM2836", 2: "BCM2837", 3: "BCM2711", 4: "BCM2712",
+}
+
+var piManufacturers = map[uint64]string{
+	0: "Sony UK", 1: "Egoman", 2: "Embest", 3: "Sony Japan", 4: "Embest", 5: "Stadium",
+}
+
+// Old style codes identify the whole board, all of them with a BCM2835
+var piOldStyleRevisions = map[uint64]domain.BoardRevision{
+	0x0002: {Model: "B", Revision: "1.0", MemoryMB: 256, Manufacturer: "Egoman"},
+	0x0003: {Model: "B", Revision: "1.0", MemoryMB: 256, Manufacturer: "Egoman"},
+	0x0004: {Model: "B", Revision: "2.0", MemoryMB: 256, Manufacturer: "Sony UK"},
+	0x0005: {Model: "B", Revision: "2.0", MemoryMB: 256, Manufacturer: "Qisda"},
+	0x0006: {Model: "B", Revision: "2.0", MemoryMB: 256, Manufacturer: "Egoman"},
+	0x0007: {Model: "A", Revision: "2.0", MemoryMB: 256, Manufacturer: "Egoman"},
+	0x0008: {Model: "A", Revision: "2.0", MemoryMB: 256, Manufacturer: "Sony UK"},
+	0x0009: {Model: "A", Revision: "2.0", MemoryMB: 256, Manufacturer: "Qisda"},
+	0x000d: {Model: "B", Revision: "2.0", MemoryMB: 512, Manufacturer: "Egoman"},
+	0x000e: {Model: "B", Revision: "2.0", MemoryMB: 512, Manufacturer: "Sony UK"},
+	0x000f: {Model: "B", Revision: "2.0", MemoryMB: 512, Manufacturer: "Egoman"},
+	0x0010: {Model: "B+", Revision: "1.2", MemoryMB: 512, Manufacturer: "Sony UK"},
+	0x0011: {Model: "CM1", Revision: "1.0", MemoryMB: 512, Manufacturer: "Sony UK"},
+	0x0012: {Model: "A+", Revision: "1.1", MemoryMB: 256, Manufacturer: "Sony UK"},
+	0x0013: {Model: "B+", Revision: "1.2", MemoryMB: 512, Manufacturer: "Embest"},
+	0x0014: {Model: "CM1", Revision: "1.0", MemoryMB: 512, Manufacturer: "Embest"},
+	0x0015: {Model: "A+", Revision: "1.1", MemoryMB: 256, Manufacturer: "Embest"},
+}
+
+// decodePiRevision decodes a revision code like "c03114". New style codes
+// follow the NOQuuuWuFMMMCCCCPPPPTTTTTTTTRRRR bit layout.
+func decodePiRevision(code string) (domain.BoardRevision, error) {
+	value, err := strconv.ParseUint(strings.TrimSpace(code), 16, 32)
+	if err != nil {
+		return domain.BoardRevision{}, fmt.Errorf("invalid revision code %q", code)
+	}
+
+	const newStyleFlag = 1 << 23
+	if value&newStyleFlag == 0 {
+		// Old style codes prefix a 1 in bit 24 when the warranty is voided
+		revision, exists := piOldStyleRevisions[value&0xffffff]
+		if !exists {
+			return domain.BoardRevision{}, fmt.Errorf("unknown revision code %q", code)
+		}
+		revision.Code = code
+		revision.Processor = "BCM2835"
+		revision.WarrantyVoided = value&(1<<24) != 0
+		return revision, nil
+	}
+
+	boardType := (value >> 4) & 0xff
+	model, exists := piModels[boardType]
+	if !exists {
+		model = fmt.Sprintf("Unknown (0x%x)", boardType)
+	}
+	processor, exists := piProcessors[(value>>12)&0xf]
+	if !exists {
+		processor = "Unknown"
+	}
+	manufacturer, exists := piManufacturers[(value>>16)&0xf]
+	if !exists {
+		manufacturer = "Unknown"
+	}
+
+	return domain.BoardRevision{
+		Code:           code,
+		NewStyle:       true,
+		Model:          model,
+		Revision:       fmt.Sprintf("1.%d", value&0xf),
+		MemoryMB:       256 << ((value >> 20) & 0x7),
+		Manufacturer:   manufacturer,
+		Processor:      processor,
+		WarrantyVoided: value&(1<<25) != 0,
+	}, nil
+}
+
+// parseCPUInfo returns the first value of every key, and how many processors
+// are listed
+func parseCPUInfo(content string) (map[string]string, int) {
+	fields := map[string]string{}
+	processors := 0
+	for _, line := range strings.Split(content, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key == "processor" {
+			processors++
+		}
+		if _, exists := fields[key]; !exists {
+			fields[key] = value
+		}
+	}
+	return fields, processors
+}
+
+func genericCPUInfo(fields map[string]string, cores int) domain.CPUInfo {
+	info := domain.CPUInfo{
+		Hardware: fields["Hardware"],
+		Cores:    cores,
+		Features: []string{},
+	}
+
+	// x86 names it "model name", some ARM kernels "Processor"
+	for _, key := range []string{"model name", "Processor", "cpu model", "uarch"} {
+		if fields[key] != "" {
+			info.Model = fields[key]
+			break
+		}
+	}
+	if info.Model == "" && fields["CPU implementer"] != "" {
+		info.Model = fmt.Sprintf("ARM implementer %s part %s", fields["CPU implementer"], fields["CPU part"])
+	}
+
+	for _, key := range []string{"flags", "Features", "isa"} {
+		if fields[key] != "" {
+			info.Features = strings.Fields(fields[key])
+			break
+		}
+	}
+	return info
+}
+
+/* ******************************************** BOARD ******************************************** */
+
+type BoardRepository struct {
+	fileReader FileReader
+}
+
+func NewBoardRepository(fr FileReader) *BoardRepository {
+	return &BoardRepository{fileReader: fr}
+}
+
+func (r *BoardRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/cpuinfo", true, "processor model"),
+		fileSource(r.fileReader, "/proc/device-tree/model", false, "board model"),
+		fileSource(r.fileReader, "/proc/device-tree/serial-number", false, "serial number"),
+	}
+}
+
+func (r *BoardRepository) GetBoardInfo() (domain.Board, error) {
+	cpuinfo, err := readFileString(r.fileReader, "/proc/cpuinfo")
+	if err != nil {
+		return domain.Board{}, err
+	}
+	fields, cores := parseCPUInfo(cpuinfo)
+	if len(fields) == 0 {
+		return domain.Board{}, errors.New("unexpected file format")
+	}
+
+	board := domain.Board{
+		CPU: genericCPUInfo(fields, cores),
+	}
+
+	// The device tree is the most precise source, cpuinfo is a fallback
+	board.Model, _ = readFileString(r.fileReader, "/proc/device-tree/model")
+	if board.Model == "" {
+		board.Model = fields["Model"]
+	}
+	board.SerialNumber, _ = readFileString(r.fileReader, "/proc/device-tree/serial-number")
+	if board.SerialNumber == "" {
+		board.SerialNumber = fields["Serial"]
+	}
+
+	isPi := strings.HasPrefix(board.Model, "Raspberry Pi")
+	if code := fields["Revision"]; code != "" && (isPi || board.Model == "") {
+		if revision, err := decodePiRevision(code); err == nil {
+			board.Revision = &revision
+			isPi = true
+			if board.Model == "" {
+				board.Model = "Raspberry Pi " + revision.Model
+			}
+		}
+	}
+	board.IsRaspberryPi = isPi
+
+	return board, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+// All the mock structures and functions are defined already in other repositories files
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestDecodePiRevision(t *testing.T) {
+
+	testBattery := map[string]domain.BoardRevision{
+		"c03114":  {Code: "c03114", NewStyle: true, Model: "4B", Revision: "1.4", MemoryMB: 4096, Manufacturer: "Sony UK", Processor: "BCM2711"},
+		"a020d3":  {Code: "a020d3", NewStyle: true, Model: "3B+", Revision: "1.3", MemoryMB: 1024, Manufacturer: "Sony UK", Processor: "BCM2837"},
+		"a22082":  {Code: "a22082", NewStyle: true, Model: "3B", Revision: "1.2", MemoryMB: 1024, Manufacturer: "Embest", Processor: "BCM2837"},
+		"9000c1":  {Code: "9000c1", NewStyle: true, Model: "Zero W", Revision: "1.1", MemoryMB: 512, Manufacturer: "Sony UK", Processor: "BCM2835"},
+		"902120":  {Code: "902120", NewStyle: true, Model: "Zero 2 W", Revision: "1.0", MemoryMB: 512, Manufacturer: "Sony UK", Processor: "BCM2837"},
+		"d04170":  {Code: "d04170", NewStyle: true, Model: "5", Revision: "1.0", MemoryMB: 8192, Manufacturer: "Sony UK", Processor: "BCM2712"},
+		"b03140":  {Code: "b03140", NewStyle: true, Model: "CM4", Revision: "1.0", MemoryMB: 2048, Manufacturer: "Sony UK", Processor: "BCM2711"},
+		"2a22082": {Code: "2a22082", NewStyle: true, Model: "3B", Revision: "1.2", MemoryMB: 1024, Manufacturer: "Embest", Processor: "BCM2837", WarrantyVoided: true},
+		"0002":    {Code: "0002", Model: "B", Revision: "1.0", MemoryMB: 256, Manufacturer: "Egoman", Processor: "BCM2835"},
+		"0013":    {Code: "0013", Model: "B+", Revision: "1.2", MemoryMB: 512, Manufacturer: "Embest", Processor: "BCM2835"},
+		"100000e": {Code: "100000e", Model: "B", Revision: "2.0", MemoryMB: 512, Manufacturer: "Sony UK", Processor: "BCM2835", WarrantyVoided: true},
+	}
+
+	for input, expected := range testBattery {
+		actual, err := decodePiRevision(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, actual, input)
+	}
+
+	for _, input := range []string{"", "xyz", "0001", "00ff"} {
+		_, err := decodePiRevision(input)
+		assert.Error(t, err, input)
+	}
+}
+
+/* ******************************************** BOARD TEST ******************************************** */
+
+func TestGetBoardInfo(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Pi 4B": {
+			"root":       "testdata/board/pi4b",
+			"isPi":       true,
+			"model":      "Raspberry Pi 4 Model B Rev 1.4",
+			"serial":     "10000000a1b2c3d4",
+			"revision":   &domain.BoardRevision{Code: "c03114", NewStyle: true, Model: "4B", Revision: "1.4", MemoryMB: 4096, Manufacturer: "Sony UK", Processor: "BCM2711"},
+			"cpuModel":   "ARM implementer 0x41 part 0xd08",
+			"cores":      4,
+			"hasFeature": "asimd",
+		},
+		"Case 2 - Pi 3B+ without serial in device tree": {
+			"root":       "testdata/board/pi3bplus",
+			"isPi":       true,
+			"model":      "Raspberry Pi 3 Model B Plus Rev 1.3",
+			"serial":     "00000000deadbeef",
+			"revision":   &domain.BoardRevision{Code: "a020d3", NewStyle: true, Model: "3B+", Revision: "1.3", MemoryMB: 1024, Manufacturer: "Sony UK", Processor: "BCM2837"},
+			"cpuModel":   "ARMv7 Processor rev 4 (v7l)",
+			"cores":      4,
+			"hasFeature": "neon",
+		},
+		"Case 3 - Pi Zero W without device tree": {
+			"root":       "testdata/board/pizerow",
+			"isPi":       true,
+			"model":      "Raspberry Pi Zero W",
+			"serial":     "00000000cafebabe",
+			"revision":   &domain.BoardRevision{Code: "9000c1", NewStyle: true, Model: "Zero W", Revision: "1.1", MemoryMB: 512, Manufacturer: "Sony UK", Processor: "BCM2835"},
+			"cpuModel":   "ARMv6-compatible processor rev 7 (v6l)",
+			"cores":      1,
+			"hasFeature": "vfp",
+		},
+		"Case 4 - Pi 1B old style revision": {
+			"root":       "testdata/board/pi1b",
+			"isPi":       true,
+			"model":      "Raspberry Pi B",
+			"serial":     "000000001234abcd",
+			"revision":   &domain.BoardRevision{Code: "100000e", Model: "B", Revision: "2.0", MemoryMB: 512, Manufacturer: "Sony UK", Processor: "BCM2835", WarrantyVoided: true},
+			"cpuModel":   "ARMv6-compatible processor rev 7 (v6l)",
+			"cores":      1,
+			"hasFeature": "java",
+		},
+		"Case 5 - Pi 5": {
+			"root":       "testdata/board/pi5",
+			"isPi":       true,
+			"model":      "Raspberry Pi 5 Model B Rev 1.0",
+			"serial":     "a1b2c3d4e5f60718",
+			"revision":   &domain.BoardRevision{Code: "d04170", NewStyle: true, Model: "5", Revision: "1.0", MemoryMB: 8192, Manufacturer: "Sony UK", Processor: "BCM2712"},
+			"cpuModel":   "ARM implementer 0x41 part 0xd0b",
+			"cores":      4,
+			"hasFeature": "atomics",
+		},
+		"Case 6 - x86 PC": {
+			"root":       "testdata/board/x86",
+			"isPi":       false,
+			"model":      "",
+			"serial":     "",
+			"revision":   (*domain.BoardRevision)(nil),
+			"cpuModel":   "Intel(R) Core(TM) i5-8250U CPU @ 1.60GHz",
+			"cores":      2,
+			"hasFeature": "sse2",
+		},
+		"Case 7 - Other ARM board": {
+			"root":       "testdata/board/rock64",
+			"isPi":       false,
+			"model":      "Pine64 Rock64",
+			"serial":     "",
+			"revision":   (*domain.BoardRevision)(nil),
+			"cpuModel":   "ARM implementer 0x41 part 0xd03",
+			"cores":      1,
+			"hasFeature": "aes",
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		repo := NewBoardRepository(&FixtureFileReader{Root: caseData["root"].(string)})
+
+		board, err := repo.GetBoardInfo()
+
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["isPi"].(bool), board.IsRaspberryPi)
+		assert.Equal(t, caseData["model"].(string), board.Model)
+		assert.Equal(t, caseData["serial"].(string), board.SerialNumber)
+		assert.Equal(t, caseData["revision"].(*domain.BoardRevision), board.Revision)
+		assert.Equal(t, caseData["cpuModel"].(string), board.CPU.Model)
+		assert.Equal(t, caseData["cores"].(int), board.CPU.Cores)
+		assert.Contains(t, board.CPU.Features, caseData["hasFeature"].(string))
+	}
+
+}
+
+func TestGetBoardInfo_FileError(t *testing.T) {
+
+	repo := NewBoardRepository(&MissingFileReader{})
+	_, err := repo.GetBoardInfo()
+	assert.Error(t, err)
+
+	repo = NewBoardRepository(&MockFileReader{Data: "some incorrect file data"})
+	_, err = repo.GetBoardInfo()
+	assert.Error(t, err, "unexpected file format")
+}
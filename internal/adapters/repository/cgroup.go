@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// parseFlatKeyed parses the "key value" lines of files like cpu.stat or
+// memory.events
+func parseFlatKeyed(content string) map[string]uint64 {
+	values := map[string]uint64{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+	return values
+}
+
+// parseCgroupLimit parses files like memory.max, where "max" means unlimited
+func parseCgroupLimit(content string) (*uint64, error) {
+	if content == "max" {
+		return nil, nil
+	}
+	value, err := strconv.ParseUint(content, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// parseIOStat parses lines like "8:0 rbytes=1 wbytes=2 rios=3 wios=4 dbytes=0
+// dios=0", keeping the major:minor of the device
+func parseIOStat(content string) []domain.CgroupIO {
+	devices := []domain.CgroupIO{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		device := domain.CgroupIO{Device: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			number, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				device.ReadBytes = number
+			case "wbytes":
+				device.WriteBytes = number
+			case "rios":
+				device.ReadIOs = number
+			case "wios":
+				device.WriteIOs = number
+			case "dbytes":
+				device.DiscardedBytes = number
+			}
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+// parseBlockDeviceNumbers maps the major:minor of every block device listed
+// in /proc/partitions to its name
+func parseBlockDeviceNumbers(content string) map[string]string {
+	names := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[0] == "major" {
+			continue
+		}
+		names[fields[0]+":"+fields[1]] = fields[3]
+	}
+	return names
+}
+
+/* ******************************************** CGROUP ******************************************** */
+
+type CgroupRepository struct {
+	fileReader FileReader
+}
+
+func NewCgroupRepository(fr FileReader) *CgroupRepository {
+	return &CgroupRepository{fileReader: fr}
+}
+
+func (r *CgroupRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, cgroupRoot+"/cgroup.controllers", false, "cgroup v2 unified hierarchy"),
+		fileSource(r.fileReader, "/proc/partitions", false, "IO device names"),
+	}
+}
+
+// Unified tells whether the host has the cgroup v2 unified hierarchy, which
+// cgroup v1 hosts, like older Raspberry Pi OS releases, lack
+func (r *CgroupRepository) Unified() bool {
+	_, err := readFileString(r.fileReader, cgroupRoot+"/cgroup.controllers")
+	return err == nil
+}
+
+// GetCgroups returns the accounting of the cgroups at the paths, relative to
+// /sys/fs/cgroup. Paths ending in /* stand for every child cgroup. Cgroups
+// that don't exist are skipped, as containers and services come and go.
+func (r *CgroupRepository) GetCgroups(paths []string) ([]domain.Cgroup, error) {
+	if !r.Unified() {
+		return nil, errors.New("cgroup v2 unified hierarchy not found")
+	}
+
+	deviceNames := map[string]string{}
+	if content, err := readFileString(r.fileReader, "/proc/partitions"); err == nil {
+		deviceNames = parseBlockDeviceNumbers(content)
+	}
+
+	cgroups := []domain.Cgroup{}
+	seen := map[string]bool{}
+	for _, cgroupPath := range r.expandPaths(paths) {
+		if seen[cgroupPath] {
+			continue
+		}
+		seen[cgroupPath] = true
+
+		if !r.isCgroup(cgroupPath) {
+			continue
+		}
+		cgroups = append(cgroups, r.readCgroup(cgroupPath, deviceNames))
+	}
+	return cgroups, nil
+}
+
+func (r *CgroupRepository) expandPaths(paths []string) []string {
+	expanded := []string{}
+	for _, cgroupPath := range paths {
+		parent, wildcard := strings.CutSuffix(cgroupPath, "/*")
+		parent = strings.Trim(path.Clean("/"+parent), "/")
+		if !wildcard {
+			expanded = append(expanded, parent)
+			continue
+		}
+
+		entries, err := listDir(r.fileReader, path.Join(cgroupRoot, parent))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			child := path.Join(parent, entry)
+			if r.isCgroup(child) {
+				expanded = append(expanded, child)
+			}
+		}
+	}
+	return expanded
+}
+
+// isCgroup tells directories of cgroups apart from their interface files
+func (r *CgroupRepository) isCgroup(cgroupPath string) bool {
+	file, err := r.fileReader.Open(path.Join(cgroupRoot, cgroupPath, "cgroup.controllers"))
+	if err != nil {
+		return false
+	}
+	file.Close()
+	return true
+}
+
+// readCgroup reads the files of every controller, leaving empty the ones of
+// disabled controllers. IO devices are named after deviceNames.
+func (r *CgroupRepository) readCgroup(cgroupPath string, deviceNames map[string]string) domain.Cgroup {
+	dir := path.Join(cgroupRoot, cgroupPath)
+	cgroup := domain.Cgroup{Path: "/" + cgroupPath, IO: []domain.CgroupIO{}}
+
+	if content, err := readFileString(r.fileReader, dir+"/cpu.stat"); err == nil {
+		stat := parseFlatKeyed(content)
+		cgroup.CPU = domain.CgroupCPU{
+			UsageUsec:     stat["usage_usec"],
+			UserUsec:      stat["user_usec"],
+			SystemUsec:    stat["system_usec"],
+			Periods:       stat["nr_periods"],
+			Throttled:     stat["nr_throttled"],
+			ThrottledUsec: stat["throttled_usec"],
+		}
+	}
+
+	if content, err := readFileString(r.fileReader, dir+"/memory.current"); err == nil {
+		cgroup.Memory.Current, _ = strconv.ParseUint(content, 10, 64)
+	}
+	if content, err := readFileString(r.fileReader, dir+"/memory.max"); err == nil {
+		cgroup.Memory.Max, _ = parseCgroupLimit(content)
+	}
+	if content, err := readFileString(r.fileReader, dir+"/memory.events"); err == nil {
+		events := parseFlatKeyed(content)
+		cgroup.Memory.OOM = events["oom"]
+		cgroup.Memory.OOMKills = events["oom_kill"]
+	}
+
+	if content, err := readFileString(r.fileReader, dir+"/io.stat"); err == nil {
+		cgroup.IO = parseIOStat(content)
+		for i := range cgroup.IO {
+			if name, exists := deviceNames[cgroup.IO[i].Device]; exists {
+				cgroup.IO[i].Device = name
+			}
+		}
+	}
+
+	if content, err := readFileString(r.fileReader, dir+"/pids.current"); err == nil {
+		cgroup.Pids.Current, _ = strconv.ParseUint(content, 10, 64)
+	}
+	if content, err := readFileString(r.fileReader, dir+"/pids.max"); err == nil {
+		cgroup.Pids.Max, _ = parseCgroupLimit(content)
+	}
+
+	return cgroup
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+// All the mock structures and functions are defined already in other repositories files
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseCgroupLimit(t *testing.T) {
+	limit, err := parseCgroupLimit("max")
+	assert.NoError(t, err)
+	assert.Nil(t, limit)
+
+	limit, err = parseCgroupLimit("268435456")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(268435456), *limit)
+
+	_, err = parseCgroupLimit("lots")
+	assert.Error(t, err)
+}
+
+func TestParseIOStat(t *testing.T) {
+	devices := parseIOStat("8:0 rbytes=512 wbytes=1024 rios=1 wios=2 dbytes=4096 dios=1\n253:0 rbytes=bad\n")
+	assert.Equal(t, []domain.CgroupIO{
+		{Device: "8:0", ReadBytes: 512, WriteBytes: 1024, ReadIOs: 1, WriteIOs: 2, DiscardedBytes: 4096},
+		{Device: "253:0"},
+	}, devices)
+}
+
+/* ******************************************** CGROUP TEST ******************************************** */
+
+func TestGetCgroups(t *testing.T) {
+
+	memoryMax := uint64(268435456)
+	pidsMax := uint64(100)
+
+	ssh := domain.Cgroup{
+		Path: "/system.slice/ssh.service",
+		CPU:  domain.CgroupCPU{UsageUsec: 1234567, UserUsec: 1000000, SystemUsec: 234567},
+		Memory: domain.CgroupMemory{
+			Current: 5242880,
+		},
+		IO: []domain.CgroupIO{
+			{Device: "mmcblk0", ReadBytes: 1048576, WriteBytes: 4096, ReadIOs: 120, WriteIOs: 1},
+		},
+		Pids: domain.CgroupPids{Current: 3},
+	}
+	myapp := domain.Cgroup{
+		Path: "/system.slice/myapp.service",
+		CPU: domain.CgroupCPU{
+			UsageUsec: 98765432, UserUsec: 90000000, SystemUsec: 8765432,
+			Periods: 5000, Throttled: 250, ThrottledUsec: 12000000,
+		},
+		Memory: domain.CgroupMemory{
+			Current:  268369920,
+			Max:      &memoryMax,
+			OOM:      3,
+			OOMKills: 2,
+		},
+		IO: []domain.CgroupIO{
+			{Device: "mmcblk0", ReadBytes: 2097152, WriteBytes: 10485760, ReadIOs: 300, WriteIOs: 2500},
+			{Device: "8:0", ReadBytes: 512, WriteBytes: 1024, ReadIOs: 1, WriteIOs: 2, DiscardedBytes: 4096},
+		},
+		Pids: domain.CgroupPids{Current: 17, Max: &pidsMax},
+	}
+	userSlice := domain.Cgroup{
+		Path:   "/user.slice",
+		Memory: domain.CgroupMemory{Current: 104857600},
+		IO:     []domain.CgroupIO{},
+		Pids:   domain.CgroupPids{Current: 42},
+	}
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Children of a slice and a slice": {
+			"paths":    []string{"system.slice/*", "user.slice"},
+			"expected": []domain.Cgroup{myapp, ssh, userSlice},
+		},
+		"Case 2 - Explicit paths, duplicated and missing": {
+			"paths":    []string{"/system.slice/ssh.service/", "system.slice/ssh.service", "system.slice/gone.service", "docker/*"},
+			"expected": []domain.Cgroup{ssh},
+		},
+		"Case 3 - Root cgroup": {
+			"paths":    []string{"/"},
+			"expected": []domain.Cgroup{{Path: "/", IO: []domain.CgroupIO{}}},
+		},
+		"Case 4 - Nothing configured": {
+			"paths":    []string{},
+			"expected": []domain.Cgroup{},
+		},
+	}
+
+	repo := NewCgroupRepository(&FixtureFileReader{Root: "testdata/cgroup/v2"})
+	assert.True(t, repo.Unified())
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		cgroups, err := repo.GetCgroups(caseData["paths"].([]string))
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"].([]domain.Cgroup), cgroups)
+	}
+}
+
+func TestGetCgroups_FileError(t *testing.T) {
+
+	// Without the unified hierarchy, as on cgroup v1 hosts
+	repo := NewCgroupRepository(&MissingFileReader{})
+	_, err := repo.GetCgroups([]string{"system.slice/*"})
+	assert.Error(t, err)
+	assert.False(t, repo.Unified())
+
+	// Not required, so that those hosts are still ready
+	assert.False(t, repo.DataSources()[0].Required)
+}
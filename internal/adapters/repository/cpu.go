@@ -32,6 +32,12 @@ func NewCPURepository(fr FileReader) *CPURepository {
 	return &CPURepository{fileReader: fr}
 }
 
+func (r *CPURepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/loadavg", true, "load averages"),
+	}
+}
+
 func (r *CPURepository) GetCPULoad() (domain.CPU, error) {
 	file, err := r.fileReader.Open("/proc/loadavg")
 	if err != nil {
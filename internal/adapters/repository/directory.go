@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// deviceOf returns the filesystem holding the file, when known
+func deviceOf(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
+
+// keepLargest inserts the file into the list, sorted from the largest, if it
+// is among the top largest
+func keepLargest(largest []domain.DirectoryFile, file domain.DirectoryFile, top int) []domain.DirectoryFile {
+	if top <= 0 || (len(largest) == top && file.Size <= largest[top-1].Size) {
+		return largest
+	}
+
+	index := sort.Search(len(largest), func(i int) bool { return largest[i].Size < file.Size })
+	if len(largest) < top {
+		largest = append(largest, domain.DirectoryFile{})
+	}
+	copy(largest[index+1:], largest[index:])
+	largest[index] = file
+	return largest
+}
+
+// sleepContext sleeps for d, or until the context is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+/* ******************************************** DIRECTORY ******************************************** */
+
+// DirectoryRepository walks directories one entry after another, pausing to
+// stay under rate entries per second, so that a large tree on an SD card
+// doesn't starve the rest of the system
+type DirectoryRepository struct {
+	fileReader FileReader
+	paths      []string
+	rate       int // Entries per second, 0 for no limit
+	maxEntries int // 0 for no limit
+	sleep      func(context.Context, time.Duration) error
+}
+
+func NewDirectoryRepository(fr FileReader, paths []string, rate int, maxEntries int) *DirectoryRepository {
+	return &DirectoryRepository{
+		fileReader: fr,
+		paths:      paths,
+		rate:       rate,
+		maxEntries: maxEntries,
+		sleep:      sleepContext,
+	}
+}
+
+func (r *DirectoryRepository) DataSources() []domain.DataSource {
+	sources := make([]domain.DataSource, 0, len(r.paths))
+	for _, dirPath := range r.paths {
+		sources = append(sources, fileSource(r.fileReader, dirPath, false, "size of "+dirPath))
+	}
+	return sources
+}
+
+// ScanDirectory walks the directory, giving up as soon as the context is
+// cancelled
+func (r *DirectoryRepository) ScanDirectory(ctx context.Context, root string, top int) (domain.DirectoryScan, error) {
+	start := time.Now()
+
+	dir, err := r.fileReader.Open(root)
+	if err != nil {
+		return domain.DirectoryScan{}, err
+	}
+	info, err := dir.Stat()
+	dir.Close()
+	if err != nil {
+		return domain.DirectoryScan{}, err
+	}
+	if !info.IsDir() {
+		return domain.DirectoryScan{}, fmt.Errorf("%s is not a directory", root)
+	}
+	rootDevice, knownDevice := deviceOf(info)
+
+	scan := domain.DirectoryScan{Largest: []domain.DirectoryFile{}}
+	entries := 0
+	pending := []string{root}
+	for len(pending) > 0 && !scan.Truncated {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		children, err := r.readDir(current)
+		if err != nil {
+			scan.Unreadable++
+			continue
+		}
+		scan.Directories++
+
+		for _, child := range children {
+			if err := ctx.Err(); err != nil {
+				return domain.DirectoryScan{}, err
+			}
+			if r.maxEntries > 0 && entries >= r.maxEntries {
+				scan.Truncated = true
+				break
+			}
+			entries++
+			if err := r.throttle(ctx, start, entries); err != nil {
+				return domain.DirectoryScan{}, err
+			}
+
+			childPath := path.Join(current, child.Name())
+			if child.Type()&os.ModeSymlink != 0 {
+				continue
+			}
+			childInfo, err := child.Info()
+			if err != nil {
+				scan.Unreadable++
+				continue
+			}
+			if device, ok := deviceOf(childInfo); knownDevice && ok && device != rootDevice {
+				continue // Another filesystem mounted inside
+			}
+
+			if child.IsDir() {
+				pending = append(pending, childPath)
+			} else if childInfo.Mode().IsRegular() {
+				scan.Files++
+				scan.Size += uint64(childInfo.Size())
+				scan.Largest = keepLargest(scan.Largest, domain.DirectoryFile{Path: childPath, Size: uint64(childInfo.Size())}, top)
+			}
+		}
+	}
+
+	scan.Duration = time.Since(start).Seconds()
+	return scan, nil
+}
+
+func (r *DirectoryRepository) readDir(dirPath string) ([]os.DirEntry, error) {
+	dir, err := r.fileReader.Open(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	children, err := dir.ReadDir(-1)
+	if err != nil {
+		return nil, err
+	}
+	// Sorted for the largest files of the same size to come out the same
+	sort.Slice(children, func(i, j int) bool { return children[i].Name() < children[j].Name() })
+	return children, nil
+}
+
+// throttle sleeps, every hundred entries, for as long as the walk is ahead
+// of the rate. It fails when the context is cancelled meanwhile.
+func (r *DirectoryRepository) throttle(ctx context.Context, start time.Time, entries int) error {
+	if r.rate <= 0 || entries%100 != 0 {
+		return nil
+	}
+	expected := time.Duration(entries) * time.Second / time.Duration(r.rate)
+	if ahead := expected - time.Since(start); ahead > 0 {
+		return r.sleep(ctx, ahead)
+	}
+	return nil
+}
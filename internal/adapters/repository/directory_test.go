@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestKeepLargest(t *testing.T) {
+	var largest []domain.DirectoryFile
+	for _, file := range []domain.DirectoryFile{
+		{Path: "a", Size: 10}, {Path: "b", Size: 30}, {Path: "c", Size: 20}, {Path: "d", Size: 5}, {Path: "e", Size: 25},
+	} {
+		largest = keepLargest(largest, file, 3)
+	}
+
+	assert.Equal(t, []domain.DirectoryFile{{Path: "b", Size: 30}, {Path: "e", Size: 25}, {Path: "c", Size: 20}}, largest)
+	assert.Empty(t, keepLargest(nil, domain.DirectoryFile{Path: "a", Size: 1}, 0))
+}
+
+/* ******************************************** DIRECTORY TEST ******************************************** */
+
+func TestScanDirectory(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Whole tree": {
+			"maxEntries": 0,
+			"top":        3,
+			"expected": domain.DirectoryScan{
+				Size: 1900, Files: 5, Directories: 4,
+				Largest: []domain.DirectoryFile{
+					{Path: "/var/log/journal/abc/system.journal", Size: 1000},
+					{Path: "/var/log/nginx/access.log", Size: 500},
+					{Path: "/var/log/syslog", Size: 300},
+				},
+			},
+		},
+		"Case 2 - Entries limit": {
+			"maxEntries": 4,
+			"top":        10,
+			// Stops within /var/log, the symbolic link counts but is not followed
+			"expected": domain.DirectoryScan{
+				Size: 300, Files: 1, Directories: 1, Truncated: true,
+				Largest: []domain.DirectoryFile{{Path: "/var/log/syslog", Size: 300}},
+			},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		repo := NewDirectoryRepository(&FixtureFileReader{Root: "testdata/directory"}, []string{"/var/log"}, 0, caseData["maxEntries"].(int))
+
+		scan, err := repo.ScanDirectory(context.Background(), "/var/log", caseData["top"].(int))
+		assert.NoError(t, err)
+
+		scan.Duration = 0
+		assert.Equal(t, caseData["expected"], scan)
+	}
+}
+
+func TestScanDirectory_Errors(t *testing.T) {
+	repo := NewDirectoryRepository(&FixtureFileReader{Root: "testdata/directory"}, []string{"/var/log"}, 0, 0)
+
+	_, err := repo.ScanDirectory(context.Background(), "/var/missing", 10)
+	assert.Error(t, err)
+
+	_, err = repo.ScanDirectory(context.Background(), "/var/log/syslog", 10)
+	assert.Error(t, err, "/var/log/syslog is not a directory")
+
+	sources := repo.DataSources()
+	assert.Len(t, sources, 1)
+	assert.True(t, sources[0].Available)
+}
+
+func TestScanDirectory_Throttle(t *testing.T) {
+	repo := NewDirectoryRepository(&MissingFileReader{}, nil, 10, 0)
+
+	var slept time.Duration
+	repo.sleep = func(_ context.Context, d time.Duration) error {
+		slept += d
+		return nil
+	}
+
+	// A hundred entries at ten per second take ten seconds
+	assert.NoError(t, repo.throttle(context.Background(), time.Now(), 100))
+	assert.InDelta(t, float64(10*time.Second), float64(slept), float64(time.Second))
+
+	// Not checked between hundreds
+	slept = 0
+	assert.NoError(t, repo.throttle(context.Background(), time.Now(), 150))
+	assert.Zero(t, slept)
+}
+
+func TestScanDirectory_Cancel(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 250; i++ {
+		assert.NoError(t, os.WriteFile(filepath.Join(root, fmt.Sprintf("file%03d", i)), []byte("x"), 0o600))
+	}
+
+	// At ten entries per second, the walk would take 25 seconds
+	repo := NewDirectoryRepository(&RealFileReader{}, []string{root}, 10, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := repo.ScanDirectory(ctx, root, 10)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Not even started when already cancelled
+	_, err = repo.ScanDirectory(ctx, root, 10)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// Subset of the Docker Engine API responses that is used
+
+type dockerListedContainer struct {
+	ID      string   `json:"Id"`
+	Names   []string `json:"Names"`
+	Image   string   `json:"Image"`
+	State   string   `json:"State"`
+	Status  string   `json:"Status"`
+	Created int64    `json:"Created"`
+}
+
+type dockerInspect struct {
+	RestartCount int `json:"RestartCount"`
+	State        struct {
+		StartedAt string `json:"StartedAt"`
+		Health    *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+}
+
+type dockerCPUStats struct {
+	CPUUsage struct {
+		TotalUsage  uint64   `json:"total_usage"`
+		PercpuUsage []uint64 `json:"percpu_usage"`
+	} `json:"cpu_usage"`
+	SystemCPUUsage uint64 `json:"system_cpu_usage"`
+	OnlineCPUs     int    `json:"online_cpus"`
+}
+
+type dockerStats struct {
+	CPUStats    dockerCPUStats `json:"cpu_stats"`
+	PreCPUStats dockerCPUStats `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IOServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+}
+
+// applyDockerStats fills the usage of a container the same way docker stats
+// computes it
+func applyDockerStats(container *domain.Container, stats dockerStats) {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemCPUUsage) - float64(stats.PreCPUStats.SystemCPUUsage)
+	cpus := stats.CPUStats.OnlineCPUs
+	if cpus == 0 {
+		cpus = len(stats.CPUStats.CPUUsage.PercpuUsage)
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		container.CPUPercent = cpuDelta / systemDelta * float64(cpus) * 100
+	}
+
+	// The page cache is left out, as docker stats does: inactive_file on
+	// cgroup v2, total_inactive_file on v1 and cache on older engines
+	usage := stats.MemoryStats.Usage
+	for _, key := range []string{"inactive_file", "total_inactive_file", "cache"} {
+		if cache, exists := stats.MemoryStats.Stats[key]; exists {
+			if cache < usage {
+				usage -= cache
+			}
+			break
+		}
+	}
+	container.MemoryUsage = usage
+	container.MemoryLimit = stats.MemoryStats.Limit
+	if container.MemoryLimit > 0 {
+		container.MemoryPercent = float64(usage) / float64(container.MemoryLimit) * 100
+	}
+
+	for _, network := range stats.Networks {
+		container.NetworkRx += network.RxBytes
+		container.NetworkTx += network.TxBytes
+	}
+
+	// Older engines capitalize the operations
+	for _, entry := range stats.BlkioStats.IOServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			container.BlockRead += entry.Value
+		case "write":
+			container.BlockWrite += entry.Value
+		}
+	}
+}
+
+/* ******************************************** DOCKER ******************************************** */
+
+type DockerRepository struct {
+	socket string
+	client *http.Client
+}
+
+// NewDockerRepository talks HTTP to the Docker Engine over its Unix socket.
+// The timeout applies to each request.
+func NewDockerRepository(socket string, timeout time.Duration) *DockerRepository {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &DockerRepository{
+		socket: socket,
+		client: &http.Client{Transport: transport, Timeout: timeout},
+	}
+}
+
+func (r *DockerRepository) DataSources() []domain.DataSource {
+	available := false
+	if conn, err := net.DialTimeout("unix", r.socket, time.Second); err == nil {
+		conn.Close()
+		available = true
+	}
+
+	return []domain.DataSource{{
+		Name:      r.socket,
+		Kind:      "socket",
+		Required:  true,
+		Available: available,
+		Provides:  "containers",
+	}}
+}
+
+// get decodes the JSON reply of the engine. The host is ignored, as every
+// connection goes to the socket.
+func (r *DockerRepository) get(path string, target any) error {
+	response, err := r.client.Get("http://docker" + path)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("docker %s: %s", path, response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(target)
+}
+
+// GetContainers lists every container, stopped ones included. The usage of
+// the running ones is requested in parallel, as the engine takes about a
+// second to reply with two CPU samples.
+func (r *DockerRepository) GetContainers() ([]domain.Container, error) {
+	var listed []dockerListedContainer
+	if err := r.get("/containers/json?all=1", &listed); err != nil {
+		return nil, err
+	}
+
+	containers := make([]domain.Container, len(listed))
+	var wg sync.WaitGroup
+	for i, entry := range listed {
+		containers[i] = domain.Container{
+			ID:      entry.ID,
+			Image:   entry.Image,
+			State:   entry.State,
+			Status:  entry.Status,
+			Created: time.Unix(entry.Created, 0).UTC(),
+		}
+		if len(entry.Names) > 0 {
+			containers[i].Name = strings.TrimPrefix(entry.Names[0], "/")
+		}
+
+		wg.Add(1)
+		go func(container *domain.Container) {
+			defer wg.Done()
+			r.fillContainer(container)
+		}(&containers[i])
+	}
+	wg.Wait()
+
+	return containers, nil
+}
+
+// fillContainer adds the details of a container. Containers may be removed
+// meanwhile, so failures are left as missing details.
+func (r *DockerRepository) fillContainer(container *domain.Container) {
+	var inspect dockerInspect
+	if err := r.get("/containers/"+container.ID+"/json", &inspect); err == nil {
+		container.RestartCount = inspect.RestartCount
+		if inspect.State.Health != nil {
+			container.Health = inspect.State.Health.Status
+		}
+		if startedAt, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt); err == nil && startedAt.Year() > 1 {
+			container.StartedAt = startedAt.UTC()
+		}
+	}
+
+	if container.State != "running" {
+		return
+	}
+	var stats dockerStats
+	if err := r.get("/containers/"+container.ID+"/stats?stream=false", &stats); err == nil {
+		applyDockerStats(container, stats)
+	}
+}
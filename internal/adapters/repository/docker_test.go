@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+// startFakeDocker serves the fixtures of testdata/docker over a Unix socket,
+// as the Docker Engine does, and returns the path of the socket
+func startFakeDocker(t *testing.T) string {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("all"))
+		http.ServeFile(w, r, "testdata/docker/containers.json")
+	})
+	mux.HandleFunc("/containers/", func(w http.ResponseWriter, r *http.Request) {
+		// /containers/{id}/json or /containers/{id}/stats
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/containers/"), "/")
+		if len(parts) != 2 {
+			http.NotFound(w, r)
+			return
+		}
+		kind := map[string]string{"json": "inspect", "stats": "stats"}[parts[1]]
+		if kind == "stats" {
+			assert.Equal(t, "false", r.URL.Query().Get("stream"))
+		}
+		path := filepath.Join("testdata/docker", kind+"_"+parts[0]+".json")
+		if _, err := os.Stat(path); kind == "" || err != nil {
+			http.Error(w, `{"message": "No such container"}`, http.StatusNotFound)
+			return
+		}
+		http.ServeFile(w, r, path)
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return socket
+}
+
+/* ******************************************** DOCKER TEST ******************************************** */
+
+func TestGetContainers(t *testing.T) {
+
+	repo := NewDockerRepository(startFakeDocker(t), 5*time.Second)
+
+	containers, err := repo.GetContainers()
+	assert.NoError(t, err)
+	assert.Len(t, containers, 3)
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Healthy container on cgroup v2": {
+			"index": 0,
+			"expected": domain.Container{
+				ID: "a1b2c3", Name: "pihole", Image: "pihole/pihole:latest",
+				State: "running", Status: "Up 3 days (healthy)", Health: "healthy",
+				Created:       time.Unix(1718000000, 0).UTC(),
+				StartedAt:     time.Date(2024, 6, 10, 6, 14, 0, 123456789, time.UTC),
+				CPUPercent:    100,
+				MemoryUsage:   100 << 20,
+				MemoryLimit:   4 << 30,
+				MemoryPercent: 2.44140625,
+				NetworkRx:     1010, NetworkTx: 2020,
+				BlockRead: 4096, BlockWrite: 8192,
+			},
+		},
+		"Case 2 - Restarted container on cgroup v1 without healthcheck": {
+			"index": 1,
+			"expected": domain.Container{
+				ID: "d4e5f6", Name: "homeassistant", Image: "ghcr.io/home-assistant/home-assistant:stable",
+				State: "running", Status: "Up 2 hours", RestartCount: 3,
+				Created:       time.Unix(1718100000, 0).UTC(),
+				StartedAt:     time.Date(2024, 6, 13, 10, 0, 0, 0, time.UTC),
+				CPUPercent:    50,
+				MemoryUsage:   200 << 20,
+				MemoryLimit:   1 << 30,
+				MemoryPercent: 19.53125,
+				NetworkRx:     5000, NetworkTx: 6000,
+				BlockRead: 1000, BlockWrite: 3000,
+			},
+		},
+		"Case 3 - Stopped container": {
+			"index": 2,
+			"expected": domain.Container{
+				ID: "0a0b0c", Name: "backup", Image: "restic/restic",
+				State: "exited", Status: "Exited (0) 5 hours ago",
+				Created: time.Unix(1718200000, 0).UTC(),
+			},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+		assert.Equal(t, caseData["expected"].(domain.Container), containers[caseData["index"].(int)])
+	}
+
+	sources := repo.DataSources()
+	assert.True(t, sources[0].Available)
+}
+
+func TestGetContainers_Error(t *testing.T) {
+
+	// Nothing listening on the socket
+	repo := NewDockerRepository(filepath.Join(t.TempDir(), "docker.sock"), time.Second)
+
+	_, err := repo.GetContainers()
+	assert.Error(t, err)
+	assert.False(t, repo.DataSources()[0].Available)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// readFileString returns the whole content of a small file, such as the ones
+// in /proc or /sys, without the trailing whitespace
+func readFileString(fr FileReader, path string) (string, error) {
+	file, err := fr.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), " \n\t\x00"), nil
+}
+
+// listDir returns the sorted names of the entries of a directory
+func listDir(fr FileReader, path string) ([]string, error) {
+	dir, err := fr.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// parseKeyValues parses lines like KEY=value or KEY="value", as found in
+// /etc/os-release or uevent files
+func parseKeyValues(content string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values
+}
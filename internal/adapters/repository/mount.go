@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ************************************* MOCKING SCAFFOLDING ************************************* */
+
+type FilesystemStat interface {
+	Statfs(path string) (domain.FilesystemUsage, error)
+}
+
+type RealFilesystemStat struct{}
+
+func (s *RealFilesystemStat) Statfs(path string) (domain.FilesystemUsage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return domain.FilesystemUsage{}, err
+	}
+
+	// Blocks are counted in fragments, which are the blocks on most
+	// filesystems
+	blockSize := uint64(stat.Frsize)
+	if blockSize == 0 {
+		blockSize = uint64(stat.Bsize)
+	}
+	return domain.FilesystemUsage{
+		Total:      stat.Blocks * blockSize,
+		Used:       (stat.Blocks - stat.Bfree) * blockSize,
+		Free:       stat.Bfree * blockSize,
+		Available:  stat.Bavail * blockSize,
+		Inodes:     stat.Files,
+		InodesFree: stat.Ffree,
+	}, nil
+}
+
+/* ******************************************** AUX ******************************************** */
+
+// matchMountPath tells whether the mount point is selected by any of the
+// globs, or is below a path they select
+func matchMountPath(patterns []string, mountPoint string) bool {
+	for _, pattern := range patterns {
+		for candidate := mountPoint; ; candidate = path.Dir(candidate) {
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+			if candidate == "/" || candidate == "." {
+				break
+			}
+		}
+	}
+	return false
+}
+
+func mountSelected(filter domain.MountFilter, mount domain.MountedFilesystem) bool {
+	if len(filter.IncludeTypes) > 0 && !slices.Contains(filter.IncludeTypes, mount.Filesystem) {
+		return false
+	}
+	if slices.Contains(filter.ExcludeTypes, mount.Filesystem) {
+		return false
+	}
+	if len(filter.IncludePaths) > 0 && !matchMountPath(filter.IncludePaths, mount.MountPoint) {
+		return false
+	}
+	return !matchMountPath(filter.ExcludePaths, mount.MountPoint)
+}
+
+/* ******************************************** MOUNT ******************************************** */
+
+type MountRepository struct {
+	fileReader FileReader
+	stat       FilesystemStat
+	timeout    time.Duration
+
+	// Mount points whose statfs hasn't returned yet, which aren't asked again
+	// so that hung mounts don't pile up goroutines
+	mu      sync.Mutex
+	pending map[string]*statfsCall
+}
+
+// NewMountRepository reads the capacity of each filesystem waiting up to the
+// timeout, as statfs blocks on unreachable network filesystems
+func NewMountRepository(fr FileReader, stat FilesystemStat, timeout time.Duration) *MountRepository {
+	return &MountRepository{
+		fileReader: fr,
+		stat:       stat,
+		timeout:    timeout,
+		pending:    make(map[string]*statfsCall),
+	}
+}
+
+func (r *MountRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/mounts", true, "mount table"),
+	}
+}
+
+// GetMounts returns the selected entries of the mount table in its order.
+// Filesystems are read in parallel, so the whole table takes at most the
+// timeout.
+func (r *MountRepository) GetMounts(filter domain.MountFilter) ([]domain.MountedFilesystem, error) {
+	file, err := r.fileReader.Open("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mounts := []domain.MountedFilesystem{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		source, mount, valid := parseMountLine(scanner.Text())
+		if !valid {
+			continue
+		}
+		entry := domain.MountedFilesystem{
+			Source:     source,
+			MountPoint: mount.MountPoint,
+			Filesystem: mount.Filesystem,
+			Options:    mount.Options,
+			ReadOnly:   mount.ReadOnly,
+		}
+		if mountSelected(filter, entry) {
+			mounts = append(mounts, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	for i := range mounts {
+		wg.Add(1)
+		go func(mount *domain.MountedFilesystem) {
+			defer wg.Done()
+			usage, err := r.statfs(mount.MountPoint)
+			if err != nil {
+				mount.Error = err.Error()
+				mount.TimedOut = errors.Is(err, domain.ErrStatfsTimeout)
+				return
+			}
+			mount.Usage = &usage
+		}(&mounts[i])
+	}
+	wg.Wait()
+
+	return mounts, nil
+}
+
+// statfsCall is a statfs in flight, shared by every entry of its mount point
+type statfsCall struct {
+	done  chan struct{}
+	usage domain.FilesystemUsage
+	err   error
+}
+
+// statfs gives up after the timeout with domain.ErrStatfsTimeout, leaving the
+// call running in the background until the filesystem answers. A mount point
+// listed more than once, or still hung since a previous request, waits for
+// the call in flight instead of starting another.
+func (r *MountRepository) statfs(mountPoint string) (domain.FilesystemUsage, error) {
+	r.mu.Lock()
+	call, joined := r.pending[mountPoint]
+	if !joined {
+		call = &statfsCall{done: make(chan struct{})}
+		r.pending[mountPoint] = call
+		go func() {
+			call.usage, call.err = r.stat.Statfs(mountPoint)
+			r.mu.Lock()
+			delete(r.pending, mountPoint)
+			r.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	r.mu.Unlock()
+
+	timer := time.NewTimer(r.timeout)
+	defer timer.Stop()
+	select {
+	case <-call.done:
+		return call.usage, call.err
+	case <-timer.C:
+		if joined {
+			return domain.FilesystemUsage{}, fmt.Errorf("%w: %s still pending", domain.ErrStatfsTimeout, mountPoint)
+		}
+		return domain.FilesystemUsage{}, fmt.Errorf("%w: %s didn't answer in %s", domain.ErrStatfsTimeout, mountPoint, r.timeout)
+	}
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+// MockFilesystemStat replies with the usage of each path, after Delay. Paths
+// in Hung block until Release is closed, like an unreachable NFS server.
+type MockFilesystemStat struct {
+	Usage   map[string]domain.FilesystemUsage
+	Hung    map[string]bool
+	Release chan struct{}
+	Delay   time.Duration
+
+	mu    sync.Mutex
+	Calls map[string]int
+}
+
+func (m *MockFilesystemStat) Statfs(path string) (domain.FilesystemUsage, error) {
+	m.mu.Lock()
+	if m.Calls == nil {
+		m.Calls = map[string]int{}
+	}
+	m.Calls[path]++
+	m.mu.Unlock()
+
+	time.Sleep(m.Delay)
+	if m.Hung[path] {
+		<-m.Release
+	}
+	usage, exists := m.Usage[path]
+	if !exists {
+		return domain.FilesystemUsage{}, errors.New("permission denied")
+	}
+	return usage, nil
+}
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestMatchMountPath(t *testing.T) {
+	testBattery := map[string]bool{
+		"/run":                 true,
+		"/run/user/1000":       true,
+		"/runtime":             false,
+		"/var/lib/docker/ab12": true,
+		"/var/lib":             false,
+		"/mnt/media":           true,
+		"/":                    false,
+	}
+
+	patterns := []string{"/run", "/var/lib/docker", "/mnt/*"}
+	for mountPoint, expected := range testBattery {
+		assert.Equal(t, expected, matchMountPath(patterns, mountPoint), mountPoint)
+	}
+	assert.True(t, matchMountPath([]string{"/"}, "/boot/firmware"))
+}
+
+/* ******************************************** MOUNT TEST ******************************************** */
+
+func TestGetMounts(t *testing.T) {
+
+	root := domain.FilesystemUsage{Total: 31 << 30, Used: 9 << 30, Free: 22 << 30, Available: 20 << 30, Inodes: 1900000, InodesFree: 1700000}
+	logs := domain.FilesystemUsage{Total: 50 << 20, Used: 12 << 20, Free: 38 << 20, Available: 38 << 20, Inodes: 100000, InodesFree: 99000}
+	media := domain.FilesystemUsage{Total: 4 << 40, Used: 3 << 40, Free: 1 << 40, Available: 1 << 40}
+	stat := &MockFilesystemStat{Usage: map[string]domain.FilesystemUsage{
+		"/": root, "/var/log": logs, "/mnt/media": media,
+	}}
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Local and network filesystems by type": {
+			"filter": domain.MountFilter{IncludeTypes: []string{"ext4", "tmpfs", "nfs4", "cifs"}, ExcludePaths: []string{"/run", "/dev"}},
+			"expected": []domain.MountedFilesystem{
+				{Source: "/dev/mmcblk0p2", MountPoint: "/", Filesystem: "ext4", Options: []string{"rw", "noatime"}, Usage: &root},
+				{
+					Source: "tmpfs", MountPoint: "/var/log", Filesystem: "tmpfs",
+					Options: []string{"rw", "nosuid", "nodev", "noatime", "size=51200k"}, Usage: &logs,
+				},
+				{
+					Source: "nas:/export/media", MountPoint: "/mnt/media", Filesystem: "nfs4",
+					Options: []string{"rw", "relatime", "vers=4.2", "rsize=131072", "wsize=131072", "namlen=255", "hard", "proto=tcp", "timeo=600", "retrans=2", "sec=sys", "clientaddr=192.168.1.20", "local_lock=none", "addr=192.168.1.10"},
+					Usage:   &media,
+				},
+				{
+					Source: "//nas/backup", MountPoint: "/mnt/backup share", Filesystem: "cifs",
+					Options: []string{"rw", "relatime", "vers=3.1.1", "cache=strict", "username=pi", "uid=1000", "noforceuid", "gid=1000", "noforcegid", "addr=192.168.1.10"},
+					Error:   "permission denied",
+				},
+			},
+		},
+		"Case 2 - Paths only": {
+			"filter": domain.MountFilter{IncludePaths: []string{"/var"}, ExcludeTypes: []string{"overlay"}},
+			"expected": []domain.MountedFilesystem{
+				{
+					Source: "tmpfs", MountPoint: "/var/log", Filesystem: "tmpfs",
+					Options: []string{"rw", "nosuid", "nodev", "noatime", "size=51200k"}, Usage: &logs,
+				},
+			},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		fr := &MockFileReader{Data: readFixture(t, "testdata/mount/mounts")}
+		repo := NewMountRepository(fr, stat, time.Second)
+
+		mounts, err := repo.GetMounts(caseData["filter"].(domain.MountFilter))
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"].([]domain.MountedFilesystem), mounts)
+	}
+}
+
+func TestGetMountsAll(t *testing.T) {
+	fr := &MockFileReader{Data: readFixture(t, "testdata/mount/mounts")}
+	repo := NewMountRepository(fr, &MockFilesystemStat{}, time.Second)
+
+	mounts, err := repo.GetMounts(domain.MountFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, mounts, 14)
+}
+
+func TestGetMountsHungFilesystem(t *testing.T) {
+	stat := &MockFilesystemStat{
+		Usage:   map[string]domain.FilesystemUsage{"/": {Total: 100}, "/mnt/media": {Total: 200}},
+		Hung:    map[string]bool{"/mnt/media": true},
+		Release: make(chan struct{}),
+	}
+	fr := &MockFileReader{Data: readFixture(t, "testdata/mount/mounts")}
+	repo := NewMountRepository(fr, stat, 50*time.Millisecond)
+	filter := domain.MountFilter{IncludePaths: []string{"/mnt/media", "/"}, IncludeTypes: []string{"ext4", "nfs4"}}
+
+	start := time.Now()
+	mounts, err := repo.GetMounts(filter)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Len(t, mounts, 2)
+	assert.Equal(t, uint64(100), mounts[0].Usage.Total)
+	assert.Nil(t, mounts[1].Usage)
+	assert.Equal(t, "statfs timed out: /mnt/media didn't answer in 50ms", mounts[1].Error)
+	assert.True(t, mounts[1].TimedOut)
+	assert.False(t, mounts[0].TimedOut)
+
+	// The hung call isn't repeated while it doesn't return
+	mounts, err = repo.GetMounts(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, "statfs timed out: /mnt/media still pending", mounts[1].Error)
+	assert.True(t, mounts[1].TimedOut)
+
+	close(stat.Release)
+	assert.Eventually(t, func() bool {
+		mounts, _ := repo.GetMounts(filter)
+		return mounts[1].Usage != nil && mounts[1].Usage.Total == 200 && !mounts[1].TimedOut
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGetMountsSameMountPoint(t *testing.T) {
+	// An over-mount, and the root listed twice as on some images
+	fr := &MockFileReader{Data: `/dev/root / ext4 rw,noatime 0 0
+rootfs / rootfs rw 0 0
+/dev/sda1 /mnt/data ext4 rw,relatime 0 0
+/dev/sdb1 /mnt/data ext4 rw,relatime 0 0
+`}
+	stat := &MockFilesystemStat{
+		Usage: map[string]domain.FilesystemUsage{"/": {Total: 100}, "/mnt/data": {Total: 200}},
+		Delay: 20 * time.Millisecond,
+	}
+	repo := NewMountRepository(fr, stat, time.Second)
+
+	// Every entry waits for the single call of its mount point
+	mounts, err := repo.GetMounts(domain.MountFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, mounts, 4)
+	for _, mount := range mounts {
+		assert.NotNil(t, mount.Usage, mount.Source)
+		assert.False(t, mount.TimedOut, mount.Source)
+		assert.Empty(t, mount.Error, mount.Source)
+	}
+	assert.Equal(t, map[string]int{"/": 1, "/mnt/data": 1}, stat.Calls)
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+//go:embed oui/oui.txt
+var embeddedOUI string
+
+// Flags of the ARP entries, from include/uapi/linux/if_arp.h
+const (
+	arpComplete  = 0x02
+	arpPermanent = 0x04
+)
+
+// parseOUITable parses the "(hex)" lines of the IEEE oui.txt, like
+// "B8-27-EB   (hex)		Raspberry Pi Foundation", keyed by the prefix
+// as in MAC addresses: b8:27:eb
+func parseOUITable(content string) map[string]string {
+	vendors := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		prefix, vendor, found := strings.Cut(line, "(hex)")
+		prefix = strings.TrimSpace(prefix)
+		if !found || len(prefix) != 8 {
+			continue
+		}
+		vendors[strings.ToLower(strings.ReplaceAll(prefix, "-", ":"))] = strings.TrimSpace(vendor)
+	}
+	return vendors
+}
+
+// macVendor returns the owner of the prefix of the MAC address. Locally
+// administered addresses, like the random ones of phones or the ones of
+// containers, have none.
+func macVendor(vendors map[string]string, mac string) string {
+	if len(mac) < 8 {
+		return ""
+	}
+	if firstOctet, err := strconv.ParseUint(mac[:2], 16, 8); err != nil || firstOctet&0x02 != 0 {
+		return ""
+	}
+	return vendors[mac[:8]]
+}
+
+// parseArpLine parses an entry of /proc/net/arp
+func parseArpLine(line string) (domain.Neighbor, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 6 || fields[0] == "IP" {
+		return domain.Neighbor{}, false
+	}
+
+	flags, err := strconv.ParseUint(strings.TrimPrefix(fields[2], "0x"), 16, 32)
+	if err != nil {
+		return domain.Neighbor{}, false
+	}
+	neighbor := domain.Neighbor{
+		Family:    "inet",
+		Address:   fields[0],
+		Interface: fields[5],
+		State:     "INCOMPLETE",
+	}
+	switch {
+	case flags&arpPermanent != 0:
+		neighbor.State = "PERMANENT"
+	case flags&arpComplete != 0:
+		neighbor.State = "COMPLETE"
+	}
+	if neighbor.State != "INCOMPLETE" {
+		neighbor.MAC = strings.ToLower(fields[3])
+	}
+	return neighbor, true
+}
+
+type ipNeighbor struct {
+	Dst    string   `json:"dst"`
+	Dev    string   `json:"dev"`
+	Lladdr string   `json:"lladdr"`
+	State  []string `json:"state"`
+}
+
+// parseIPNeigh parses the output of ip -j -6 neigh
+func parseIPNeigh(output []byte) ([]domain.Neighbor, error) {
+	var entries []ipNeighbor
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, err
+	}
+
+	neighbors := make([]domain.Neighbor, 0, len(entries))
+	for _, entry := range entries {
+		neighbors = append(neighbors, domain.Neighbor{
+			Family:    "inet6",
+			Address:   entry.Dst,
+			MAC:       strings.ToLower(entry.Lladdr),
+			Interface: entry.Dev,
+			State:     strings.Join(entry.State, ","),
+		})
+	}
+	return neighbors, nil
+}
+
+// LoadOUITable reads the vendors of MAC address prefixes from a file in the
+// format of the IEEE oui.txt, or from the embedded selection when path is
+// empty
+func LoadOUITable(fr FileReader, path string) (map[string]string, error) {
+	if path == "" {
+		return parseOUITable(embeddedOUI), nil
+	}
+	content, err := readFileString(fr, path)
+	if err != nil {
+		return nil, err
+	}
+	vendors := parseOUITable(content)
+	if len(vendors) == 0 {
+		return nil, errors.New("no vendor found in " + path)
+	}
+	return vendors, nil
+}
+
+/* ******************************************** NEIGHBORS ******************************************** */
+
+type NeighborRepository struct {
+	fileReader  FileReader
+	toolChecker ToolInstalled
+	cmdExec     CmdExecutor
+	vendors     map[string]string
+}
+
+// NewNeighborRepository returns a repository naming the vendor of each MAC
+// address with the vendors table, if any
+func NewNeighborRepository(fr FileReader, ti ToolInstalled, cmd CmdExecutor, vendors map[string]string) *NeighborRepository {
+	return &NeighborRepository{
+		fileReader:  fr,
+		toolChecker: ti,
+		cmdExec:     cmd,
+		vendors:     vendors,
+	}
+}
+
+func (r *NeighborRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/net/arp", true, "IPv4 neighbors"),
+		toolSource(r.toolChecker, "ip", false, "IPv6 neighbors"),
+	}
+}
+
+// GetNeighbors lists the IPv4 neighbors and, when ip supports JSON output,
+// the IPv6 ones
+func (r *NeighborRepository) GetNeighbors() ([]domain.Neighbor, error) {
+	file, err := r.fileReader.Open("/proc/net/arp")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	neighbors := []domain.Neighbor{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if neighbor, ok := parseArpLine(scanner.Text()); ok {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// The ip of busybox has no JSON output, IPv6 neighbors are then left out
+	if r.toolChecker.isToolInstalled("ip") {
+		if output, err := r.cmdExec.Command("ip", "-j", "-6", "neigh", "show").Output(); err == nil {
+			if ipv6, err := parseIPNeigh(output); err == nil {
+				neighbors = append(neighbors, ipv6...)
+			}
+		}
+	}
+
+	for i := range neighbors {
+		neighbors[i].Vendor = macVendor(r.vendors, neighbors[i].MAC)
+	}
+	return neighbors, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseOUITable(t *testing.T) {
+	vendors := parseOUITable(readFixture(t, "testdata/neighbors/oui.txt"))
+	assert.Equal(t, map[string]string{
+		"28:6f:b9": "Nokia Shanghai Bell Co., Ltd.",
+		"b8:27:eb": "Raspberry Pi Foundation",
+	}, vendors)
+
+	// The embedded selection
+	vendors, err := LoadOUITable(&MissingFileReader{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Raspberry Pi Trading Ltd", vendors["dc:a6:32"])
+
+	_, err = LoadOUITable(&MissingFileReader{}, "/usr/share/ieee-data/oui.txt")
+	assert.Error(t, err)
+	_, err = LoadOUITable(&MockFileReader{Data: "some incorrect file data"}, "/usr/share/ieee-data/oui.txt")
+	assert.Error(t, err)
+}
+
+func TestMacVendor(t *testing.T) {
+	vendors := map[string]string{"b8:27:eb": "Raspberry Pi Foundation"}
+
+	assert.Equal(t, "Raspberry Pi Foundation", macVendor(vendors, "b8:27:eb:12:34:56"))
+	assert.Equal(t, "", macVendor(vendors, "dc:a6:32:12:34:56"))
+	// Locally administered
+	assert.Equal(t, "", macVendor(vendors, "ba:27:eb:12:34:56"))
+	assert.Equal(t, "", macVendor(vendors, ""))
+	assert.Equal(t, "", macVendor(nil, "b8:27:eb:12:34:56"))
+}
+
+/* ******************************************** NEIGHBORS TEST ******************************************** */
+
+func TestGetNeighbors(t *testing.T) {
+	vendors := map[string]string{
+		"38:10:d5": "AVM GmbH",
+		"b8:27:eb": "Raspberry Pi Foundation",
+		"00:11:32": "Synology Incorporated",
+	}
+	ipv4 := []domain.Neighbor{
+		{Family: "inet", Address: "192.168.1.1", MAC: "38:10:d5:aa:bb:cc", Interface: "eth0", State: "COMPLETE", Vendor: "AVM GmbH"},
+		{Family: "inet", Address: "192.168.1.23", MAC: "b8:27:eb:12:34:56", Interface: "eth0", State: "COMPLETE", Vendor: "Raspberry Pi Foundation"},
+		{Family: "inet", Address: "192.168.1.40", MAC: "7a:11:22:33:44:55", Interface: "wlan0", State: "COMPLETE"},
+		{Family: "inet", Address: "192.168.1.77", Interface: "eth0", State: "INCOMPLETE"},
+		{Family: "inet", Address: "172.17.0.2", MAC: "02:42:ac:11:00:02", Interface: "docker0", State: "COMPLETE"},
+		{Family: "inet", Address: "192.168.1.250", MAC: "00:11:32:0a:0b:0c", Interface: "eth0", State: "PERMANENT", Vendor: "Synology Incorporated"},
+	}
+	ipv6 := []domain.Neighbor{
+		{Family: "inet6", Address: "fe80::3a10:d5ff:feaa:bbcc", MAC: "38:10:d5:aa:bb:cc", Interface: "eth0", State: "REACHABLE", Vendor: "AVM GmbH"},
+		{Family: "inet6", Address: "2001:db8:1::23", MAC: "b8:27:eb:12:34:56", Interface: "eth0", State: "STALE", Vendor: "Raspberry Pi Foundation"},
+		{Family: "inet6", Address: "fe80::dead", Interface: "eth0", State: "FAILED"},
+	}
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - IPv4 and IPv6": {
+			"installed": true,
+			"outputs":   map[string]string{"ip -j -6 neigh": readFixture(t, "testdata/neighbors/ip_neigh.json")},
+			"expected":  append(append([]domain.Neighbor{}, ipv4...), ipv6...),
+		},
+		"Case 2 - Without ip": {
+			"installed": false,
+			"outputs":   map[string]string{},
+			"expected":  ipv4,
+		},
+		"Case 3 - ip without JSON output": {
+			"installed": true,
+			"outputs":   map[string]string{"ip -j -6 neigh": "fe80::dead dev eth0  FAILED\n"},
+			"expected":  ipv4,
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		fr := &FixtureFileReader{Root: "testdata/neighbors"}
+		ti := &MockToolInstalled{Installed: map[string]bool{"ip": caseData["installed"].(bool)}}
+		cmd := &ScriptedCmdExecutor{Outputs: caseData["outputs"].(map[string]string)}
+
+		repo := NewNeighborRepository(fr, ti, cmd, vendors)
+
+		neighbors, err := repo.GetNeighbors()
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"], neighbors)
+	}
+}
+
+func TestGetNeighbors_Errors(t *testing.T) {
+	repo := NewNeighborRepository(&MissingFileReader{}, &MockToolInstalled{}, &MockCmdExecutor{}, nil)
+	_, err := repo.GetNeighbors()
+	assert.Error(t, err)
+}
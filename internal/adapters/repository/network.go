@@ -77,9 +77,10 @@ type RealCmdExecutor struct {
 	Cmd *exec.Cmd
 }
 
+// Command returns a new executor, so that a shared RealCmdExecutor can be used
+// by several requests at the same time
 func (c *RealCmdExecutor) Command(name string, arg ...string) CmdExecutor {
-	c.Cmd = exec.Command(name, arg...)
-	return c
+	return &RealCmdExecutor{Cmd: exec.Command(name, arg...)}
 }
 
 func (c *RealCmdExecutor) Output() ([]byte, error) {
@@ -102,6 +103,13 @@ func NewNetworkRepository(fr FileReader, ti ToolInstalled, cmd CmdExecutor) *Net
 	}
 }
 
+func (r *NetworkRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/net/dev", true, "interface counters"),
+		toolSource(r.toolChecker, "iwconfig", false, "wireless bit rate"),
+	}
+}
+
 func (r *NetworkRepository) GetNetworkInterfaces() ([]domain.NetworkInterface, error) {
 	file, err := r.fileReader.Open("/proc/net/dev")
 	if err != nil {
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// parseProtocolCounters parses the pairs of lines of /proc/net/snmp and
+// /proc/net/netstat, the names of the counters of a protocol followed by
+// their values, e.g. "Tcp: ActiveOpens ..." and "Tcp: 10234 ...". Some
+// values, like Tcp MaxConn, may be negative.
+func parseProtocolCounters(content string) map[string]map[string]int64 {
+	counters := map[string]map[string]int64{}
+	lines := strings.Split(content, "\n")
+	for i := 0; i+1 < len(lines); i += 2 {
+		protocol, names, found := strings.Cut(lines[i], ":")
+		valuesProtocol, values, valuesFound := strings.Cut(lines[i+1], ":")
+		if !found || !valuesFound || protocol != valuesProtocol {
+			continue
+		}
+
+		nameFields := strings.Fields(names)
+		valueFields := strings.Fields(values)
+		if len(nameFields) != len(valueFields) {
+			continue
+		}
+
+		protocolCounters := make(map[string]int64, len(nameFields))
+		for j, name := range nameFields {
+			if value, err := strconv.ParseInt(valueFields[j], 10, 64); err == nil {
+				protocolCounters[name] = value
+			}
+		}
+		counters[protocol] = protocolCounters
+	}
+	return counters
+}
+
+// parseSockstat parses the lines of /proc/net/sockstat{,6}, made of a
+// protocol and pairs of names and values, e.g. "TCP: inuse 9 orphan 1"
+func parseSockstat(content string) map[string]map[string]uint64 {
+	stats := map[string]map[string]uint64{}
+	for _, line := range strings.Split(content, "\n") {
+		protocol, rest, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		protocolStats := make(map[string]uint64, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			protocolStats[fields[i]] = parseUint(fields[i+1])
+		}
+		stats[strings.TrimSpace(protocol)] = protocolStats
+	}
+	return stats
+}
+
+// counter returns the value of a counter, 0 when missing or negative
+func counter(counters map[string]int64, name string) uint64 {
+	return uint64(max(counters[name], 0))
+}
+
+// optionalCounter returns the value of a counter, or nil when missing
+func optionalCounter(counters map[string]int64, name string) *uint64 {
+	value, exists := counters[name]
+	if !exists {
+		return nil
+	}
+	result := uint64(max(value, 0))
+	return &result
+}
+
+// optionalStat returns a value of /proc/net/sockstat6, or nil when missing
+func optionalStat(stats map[string]map[string]uint64, protocol string, name string) *uint64 {
+	value, exists := stats[protocol][name]
+	if !exists {
+		return nil
+	}
+	return &value
+}
+
+/* ******************************************** NETWORK PROTOCOLS ******************************************** */
+
+type NetworkProtocolRepository struct {
+	fileReader FileReader
+	pageSize   uint64 // The memory of sockets is counted in pages
+}
+
+func NewNetworkProtocolRepository(fr FileReader) *NetworkProtocolRepository {
+	return &NetworkProtocolRepository{
+		fileReader: fr,
+		pageSize:   uint64(os.Getpagesize()),
+	}
+}
+
+func (r *NetworkProtocolRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/net/snmp", true, "TCP and UDP counters"),
+		fileSource(r.fileReader, "/proc/net/netstat", false, "TCP listen overflows and timeouts"),
+		fileSource(r.fileReader, "/proc/net/sockstat", true, "sockets in use"),
+		fileSource(r.fileReader, "/proc/net/sockstat6", false, "IPv6 sockets in use"),
+	}
+}
+
+func (r *NetworkProtocolRepository) GetProtocols() (domain.NetworkProtocols, error) {
+	content, err := readFileString(r.fileReader, "/proc/net/snmp")
+	if err != nil {
+		return domain.NetworkProtocols{}, err
+	}
+	snmp := parseProtocolCounters(content)
+	tcp, tcpFound := snmp["Tcp"]
+	udp, udpFound := snmp["Udp"]
+	if !tcpFound || !udpFound {
+		return domain.NetworkProtocols{}, errors.New("unexpected /proc/net/snmp format")
+	}
+
+	content, err = readFileString(r.fileReader, "/proc/net/sockstat")
+	if err != nil {
+		return domain.NetworkProtocols{}, err
+	}
+	sockstat := parseSockstat(content)
+
+	// Both are missing on some kernels and without IPv6
+	tcpExt := map[string]int64{}
+	if content, err := readFileString(r.fileReader, "/proc/net/netstat"); err == nil {
+		tcpExt = parseProtocolCounters(content)["TcpExt"]
+	}
+	sockstat6 := map[string]map[string]uint64{}
+	if content, err := readFileString(r.fileReader, "/proc/net/sockstat6"); err == nil {
+		sockstat6 = parseSockstat(content)
+	}
+
+	return domain.NetworkProtocols{
+		TCP: domain.TCPStats{
+			ActiveOpens:        counter(tcp, "ActiveOpens"),
+			PassiveOpens:       counter(tcp, "PassiveOpens"),
+			AttemptFails:       counter(tcp, "AttemptFails"),
+			EstablishedResets:  counter(tcp, "EstabResets"),
+			CurrentEstablished: counter(tcp, "CurrEstab"),
+			InSegments:         counter(tcp, "InSegs"),
+			OutSegments:        counter(tcp, "OutSegs"),
+			RetransSegments:    counter(tcp, "RetransSegs"),
+			InErrors:           counter(tcp, "InErrs"),
+			OutResets:          counter(tcp, "OutRsts"),
+			ListenOverflows:    optionalCounter(tcpExt, "ListenOverflows"),
+			ListenDrops:        optionalCounter(tcpExt, "ListenDrops"),
+			Timeouts:           optionalCounter(tcpExt, "TCPTimeouts"),
+		},
+		UDP: domain.UDPStats{
+			InDatagrams:         counter(udp, "InDatagrams"),
+			OutDatagrams:        counter(udp, "OutDatagrams"),
+			NoPorts:             counter(udp, "NoPorts"),
+			InErrors:            counter(udp, "InErrors"),
+			ReceiveBufferErrors: counter(udp, "RcvbufErrors"),
+			SendBufferErrors:    counter(udp, "SndbufErrors"),
+			ChecksumErrors:      counter(udp, "InCsumErrors"),
+		},
+		Sockets: domain.SocketStats{
+			Used:        sockstat["sockets"]["used"],
+			TCPInUse:    sockstat["TCP"]["inuse"],
+			TCPOrphan:   sockstat["TCP"]["orphan"],
+			TCPTimeWait: sockstat["TCP"]["tw"],
+			TCPAlloc:    sockstat["TCP"]["alloc"],
+			TCPMemory:   sockstat["TCP"]["mem"] * r.pageSize,
+			UDPInUse:    sockstat["UDP"]["inuse"],
+			UDPMemory:   sockstat["UDP"]["mem"] * r.pageSize,
+			RawInUse:    sockstat["RAW"]["inuse"],
+			TCP6InUse:   optionalStat(sockstat6, "TCP6", "inuse"),
+			UDP6InUse:   optionalStat(sockstat6, "UDP6", "inuse"),
+			Raw6InUse:   optionalStat(sockstat6, "RAW6", "inuse"),
+		},
+	}, nil
+}
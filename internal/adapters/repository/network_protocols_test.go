@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseProtocolCounters(t *testing.T) {
+	counters := parseProtocolCounters(readFixture(t, "testdata/network_protocols/pi/proc/net/snmp"))
+
+	assert.Len(t, counters, 6)
+	assert.Equal(t, int64(-1), counters["Tcp"]["MaxConn"])
+	assert.Equal(t, int64(1523), counters["Tcp"]["RetransSegs"])
+	assert.Equal(t, int64(42), counters["IcmpMsg"]["OutType3"])
+	assert.Equal(t, int64(0), counters["UdpLite"]["InErrors"])
+
+	// Pairs whose names and values don't match are skipped
+	counters = parseProtocolCounters("Tcp: ActiveOpens PassiveOpens\nTcp: 1\nUdp: NoPorts\nUdp: 4\n")
+	assert.Equal(t, map[string]map[string]int64{"Udp": {"NoPorts": 4}}, counters)
+}
+
+func TestParseSockstat(t *testing.T) {
+	stats := parseSockstat(readFixture(t, "testdata/network_protocols/pi/proc/net/sockstat"))
+
+	assert.Equal(t, map[string]map[string]uint64{
+		"sockets": {"used": 291},
+		"TCP":     {"inuse": 9, "orphan": 1, "tw": 4, "alloc": 12, "mem": 3},
+		"UDP":     {"inuse": 5, "mem": 2},
+		"UDPLITE": {"inuse": 0},
+		"RAW":     {"inuse": 1},
+		"FRAG":    {"inuse": 0, "memory": 0},
+	}, stats)
+}
+
+/* ******************************************** NETWORK PROTOCOLS TEST ******************************************** */
+
+func TestGetProtocols(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Raspberry Pi": {
+			"root": "testdata/network_protocols/pi",
+			"expected": domain.NetworkProtocols{
+				TCP: domain.TCPStats{
+					ActiveOpens: 10234, PassiveOpens: 2345, AttemptFails: 120, EstablishedResets: 340,
+					CurrentEstablished: 7, InSegments: 1402345, OutSegments: 1298765, RetransSegments: 1523,
+					InErrors: 3, OutResets: 890,
+					ListenOverflows: uint64Pointer(17), ListenDrops: uint64Pointer(19), Timeouts: uint64Pointer(95),
+				},
+				UDP: domain.UDPStats{
+					InDatagrams: 120345, OutDatagrams: 118976, NoPorts: 42, InErrors: 7,
+					ReceiveBufferErrors: 5, SendBufferErrors: 0, ChecksumErrors: 2,
+				},
+				Sockets: domain.SocketStats{
+					Used: 291, TCPInUse: 9, TCPOrphan: 1, TCPTimeWait: 4, TCPAlloc: 12, TCPMemory: 3 * 4096,
+					UDPInUse: 5, UDPMemory: 2 * 4096, RawInUse: 1,
+					TCP6InUse: uint64Pointer(4), UDP6InUse: uint64Pointer(3), Raw6InUse: uint64Pointer(1),
+				},
+			},
+		},
+		"Case 2 - Without netstat nor IPv6": {
+			"root": "testdata/network_protocols/no_ipv6",
+			"expected": domain.NetworkProtocols{
+				TCP: domain.TCPStats{
+					ActiveOpens: 10234, PassiveOpens: 2345, AttemptFails: 120, EstablishedResets: 340,
+					CurrentEstablished: 7, InSegments: 1402345, OutSegments: 1298765, RetransSegments: 1523,
+					InErrors: 3, OutResets: 890,
+				},
+				UDP: domain.UDPStats{
+					InDatagrams: 120345, OutDatagrams: 118976, NoPorts: 42, InErrors: 7,
+					ReceiveBufferErrors: 5, SendBufferErrors: 0, ChecksumErrors: 2,
+				},
+				Sockets: domain.SocketStats{
+					Used: 120, TCPInUse: 2, TCPAlloc: 3, TCPMemory: 4096, UDPInUse: 1,
+				},
+			},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		repo := NewNetworkProtocolRepository(&FixtureFileReader{Root: caseData["root"].(string)})
+		repo.pageSize = 4096
+
+		protocols, err := repo.GetProtocols()
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"], protocols)
+	}
+}
+
+func TestGetProtocols_Errors(t *testing.T) {
+	repo := NewNetworkProtocolRepository(&MissingFileReader{})
+	_, err := repo.GetProtocols()
+	assert.Error(t, err)
+
+	repo = NewNetworkProtocolRepository(&MockFileReader{Data: "some incorrect file data"})
+	_, err = repo.GetProtocols()
+	assert.EqualError(t, err, "unexpected /proc/net/snmp format")
+}
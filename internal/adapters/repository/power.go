@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+const (
+	powerSupplyRoot   = "/sys/class/power_supply"
+	firmwareThrottled = "/sys/devices/platform/soc/soc:firmware/get_throttled"
+)
+
+// throttledBits names the bits of get_throttled, as documented for vcgencmd
+var throttledBits = []struct {
+	bit  uint
+	name string
+	set  func(status *domain.ThrottledStatus)
+}{
+	{0, "under-voltage", func(s *domain.ThrottledStatus) { s.UnderVoltage = true }},
+	{1, "freq-capped", func(s *domain.ThrottledStatus) { s.FreqCapped = true }},
+	{2, "throttled", func(s *domain.ThrottledStatus) { s.Throttled = true }},
+	{3, "soft-temp-limit", func(s *domain.ThrottledStatus) { s.SoftTempLimit = true }},
+	{16, "under-voltage-occurred", func(s *domain.ThrottledStatus) { s.UnderVoltageOccurred = true }},
+	{17, "freq-capped-occurred", func(s *domain.ThrottledStatus) { s.FreqCappedOccurred = true }},
+	{18, "throttled-occurred", func(s *domain.ThrottledStatus) { s.ThrottledOccurred = true }},
+	{19, "soft-temp-limit-occurred", func(s *domain.ThrottledStatus) { s.SoftTempLimitOccurred = true }},
+}
+
+// decodeThrottled decodes values like "throttled=0x50005", from vcgencmd, or
+// "50005", from the firmware file, both in hexadecimal
+func decodeThrottled(content string) (domain.ThrottledStatus, error) {
+	value := strings.TrimSpace(content)
+	value = strings.TrimPrefix(value, "throttled=")
+	value = strings.TrimPrefix(value, "0x")
+
+	mask, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return domain.ThrottledStatus{}, fmt.Errorf("invalid throttled value %q", content)
+	}
+
+	status := domain.ThrottledStatus{
+		Raw:   fmt.Sprintf("0x%x", mask),
+		Flags: []string{},
+	}
+	for _, bit := range throttledBits {
+		if mask&(1<<bit.bit) != 0 {
+			bit.set(&status)
+			status.Flags = append(status.Flags, bit.name)
+		}
+	}
+	return status, nil
+}
+
+// parsePowerSupplyUevent reads the POWER_SUPPLY_* properties of a uevent
+// file. Voltages, currents and powers come in micro units.
+func parsePowerSupplyUevent(name, content string) domain.PowerSupply {
+	properties := parseKeyValues(content)
+	get := func(key string) (string, bool) {
+		value, exists := properties["POWER_SUPPLY_"+key]
+		return value, exists && value != ""
+	}
+	flag := func(key string) *bool {
+		value, exists := get(key)
+		if !exists {
+			return nil
+		}
+		result := value == "1"
+		return &result
+	}
+	micro := func(key string) *float64 {
+		value, exists := get(key)
+		if !exists {
+			return nil
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil
+		}
+		result := number / 1e6
+		return &result
+	}
+
+	supply := domain.PowerSupply{
+		Name:    name,
+		Present: flag("PRESENT"),
+		Online:  flag("ONLINE"),
+		Voltage: micro("VOLTAGE_NOW"),
+		Current: micro("CURRENT_NOW"),
+		Power:   micro("POWER_NOW"),
+	}
+	supply.Type, _ = get("TYPE")
+	supply.Status, _ = get("STATUS")
+	supply.Health, _ = get("HEALTH")
+	if value, exists := get("CAPACITY"); exists {
+		if capacity, err := strconv.Atoi(value); err == nil {
+			supply.Capacity = &capacity
+		}
+	}
+	return supply
+}
+
+/* ******************************************** POWER ******************************************** */
+
+type PowerRepository struct {
+	fileReader  FileReader
+	toolChecker ToolInstalled
+	cmdExec     CmdExecutor
+}
+
+func NewPowerRepository(fr FileReader, ti ToolInstalled, cmd CmdExecutor) *PowerRepository {
+	return &PowerRepository{
+		fileReader:  fr,
+		toolChecker: ti,
+		cmdExec:     cmd,
+	}
+}
+
+func (r *PowerRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, powerSupplyRoot, false, "power supplies"),
+		fileSource(r.fileReader, firmwareThrottled, false, "throttling status"),
+		toolSource(r.toolChecker, "vcgencmd", false, "throttling status"),
+	}
+}
+
+// GetPower doesn't fail when there are no power supplies or throttling
+// status, which is the usual case out of a Raspberry Pi
+func (r *PowerRepository) GetPower() (domain.Power, error) {
+	power := domain.Power{Supplies: []domain.PowerSupply{}}
+
+	if names, err := listDir(r.fileReader, powerSupplyRoot); err == nil {
+		for _, name := range names {
+			content, err := readFileString(r.fileReader, path.Join(powerSupplyRoot, name, "uevent"))
+			if err != nil {
+				continue
+			}
+			power.Supplies = append(power.Supplies, parsePowerSupplyUevent(name, content))
+		}
+	}
+
+	throttled, err := r.getThrottled()
+	if err == nil {
+		power.Throttled = &throttled
+	}
+
+	return power, nil
+}
+
+// getThrottled prefers the firmware file, which doesn't spawn a process
+func (r *PowerRepository) getThrottled() (domain.ThrottledStatus, error) {
+	if content, err := readFileString(r.fileReader, firmwareThrottled); err == nil {
+		status, err := decodeThrottled(content)
+		status.Source = firmwareThrottled
+		return status, err
+	}
+
+	if !r.toolChecker.isToolInstalled("vcgencmd") {
+		return domain.ThrottledStatus{}, errors.New("no throttling status source")
+	}
+	output, err := r.cmdExec.Command("vcgencmd", "get_throttled").Output()
+	if err != nil {
+		return domain.ThrottledStatus{}, err
+	}
+	status, err := decodeThrottled(string(output))
+	status.Source = "vcgencmd"
+	return status, err
+}
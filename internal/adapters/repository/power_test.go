@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+// ColonFreeFixtureReader reads fixtures of paths with colons, which can't be
+// part of a module, from the same path with underscores
+type ColonFreeFixtureReader struct {
+	FixtureFileReader
+}
+
+func (f *ColonFreeFixtureReader) Open(name string) (*os.File, error) {
+	return f.FixtureFileReader.Open(strings.ReplaceAll(name, ":", "_"))
+}
+
+func boolPointer(value bool) *bool        { return &value }
+func intPointer(value int) *int           { return &value }
+func floatPointer(value float64) *float64 { return &value }
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestDecodeThrottled(t *testing.T) {
+
+	testBattery := map[string]domain.ThrottledStatus{
+		"throttled=0x0": {Raw: "0x0", Flags: []string{}},
+		"throttled=0x50005\n": {
+			Raw: "0x50005", UnderVoltage: true, Throttled: true,
+			UnderVoltageOccurred: true, ThrottledOccurred: true,
+			Flags: []string{"under-voltage", "throttled", "under-voltage-occurred", "throttled-occurred"},
+		},
+		"e000a": {
+			Raw: "0xe000a", FreqCapped: true, SoftTempLimit: true,
+			FreqCappedOccurred: true, ThrottledOccurred: true, SoftTempLimitOccurred: true,
+			Flags: []string{"freq-capped", "soft-temp-limit", "freq-capped-occurred", "throttled-occurred", "soft-temp-limit-occurred"},
+		},
+	}
+
+	for input, expected := range testBattery {
+		status, err := decodeThrottled(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, status, input)
+	}
+
+	for _, input := range []string{"", "throttled=", "VCHI initialization failed"} {
+		_, err := decodeThrottled(input)
+		assert.Error(t, err, input)
+	}
+}
+
+/* ******************************************** POWER TEST ******************************************** */
+
+func TestGetPower(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Pi with UPS HAT and firmware file": {
+			"root":      "testdata/power/pi",
+			"installed": false,
+			"output":    "",
+			"expected": domain.Power{
+				Supplies: []domain.PowerSupply{{
+					Name: "ups-hat", Type: "Battery", Status: "Discharging",
+					Present: boolPointer(true), Online: boolPointer(false), Capacity: intPointer(87),
+					Voltage: floatPointer(4.012), Current: floatPointer(-0.85),
+				}},
+				Throttled: &domain.ThrottledStatus{
+					Raw: "0x50005", Source: "/sys/devices/platform/soc/soc:firmware/get_throttled",
+					UnderVoltage: true, Throttled: true, UnderVoltageOccurred: true, ThrottledOccurred: true,
+					Flags: []string{"under-voltage", "throttled", "under-voltage-occurred", "throttled-occurred"},
+				},
+			},
+		},
+		"Case 2 - Laptop without throttling status": {
+			"root":      "testdata/power/laptop",
+			"installed": false,
+			"output":    "",
+			"expected": domain.Power{
+				Supplies: []domain.PowerSupply{
+					{Name: "AC", Type: "Mains", Online: boolPointer(true)},
+					{
+						Name: "BAT0", Type: "Battery", Status: "Full", Present: boolPointer(true),
+						Capacity: intPointer(100), Voltage: floatPointer(12.96), Power: floatPointer(0),
+					},
+				},
+			},
+		},
+		"Case 3 - Pi without firmware file nor power supplies": {
+			"root":      "testdata/power/none",
+			"installed": true,
+			"output":    "throttled=0x0\n",
+			"expected": domain.Power{
+				Supplies:  []domain.PowerSupply{},
+				Throttled: &domain.ThrottledStatus{Raw: "0x0", Source: "vcgencmd", Flags: []string{}},
+			},
+		},
+		"Case 4 - vcgencmd failing": {
+			"root":      "testdata/power/none",
+			"installed": true,
+			"output":    "VCHI initialization failed",
+			"expected":  domain.Power{Supplies: []domain.PowerSupply{}},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		fr := &ColonFreeFixtureReader{FixtureFileReader{Root: caseData["root"].(string)}}
+		ti := &MockToolInstalled{Installed: map[string]bool{"vcgencmd": caseData["installed"].(bool)}}
+		repo := NewPowerRepository(fr, ti, &MockCmdExecutor{output: caseData["output"].(string)})
+
+		power, err := repo.GetPower()
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"].(domain.Power), power)
+	}
+}
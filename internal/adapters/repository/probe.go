@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// ICMP message types, from RFC 792 and RFC 4443
+const (
+	icmpEchoRequest  = 8
+	icmpEchoReply    = 0
+	icmp6EchoRequest = 128
+	icmp6EchoReply   = 129
+)
+
+// icmpChecksum is the one's complement of the one's complement sum of the
+// message, as 16-bit words
+func icmpChecksum(message []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(message); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(message[i:]))
+	}
+	if len(message)%2 == 1 {
+		sum += uint32(message[len(message)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
+
+// echoRequest builds an ICMP echo request. The identifier is left to the
+// kernel, which sets it to the one of the ping socket.
+func echoRequest(icmpType byte, sequence uint16) []byte {
+	message := make([]byte, 16)
+	message[0] = icmpType
+	binary.BigEndian.PutUint16(message[6:], sequence)
+	copy(message[8:], "pimonitr")
+	binary.BigEndian.PutUint16(message[2:], icmpChecksum(message))
+	return message
+}
+
+// isEchoReply checks the message is the reply to the request of the sequence
+func isEchoReply(message []byte, icmpType byte, sequence uint16) bool {
+	return len(message) >= 8 && message[0] == icmpType && binary.BigEndian.Uint16(message[6:]) == sequence
+}
+
+// openPingSocket opens an unprivileged ICMP socket of the family, which the
+// kernel only grants to the groups in net.ipv4.ping_group_range
+func openPingSocket(ipv6 bool) (net.PacketConn, error) {
+	family, protocol := syscall.AF_INET, syscall.IPPROTO_ICMP
+	if ipv6 {
+		family, protocol = syscall.AF_INET6, syscall.IPPROTO_ICMPV6
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, protocol)
+	if errors.Is(err, syscall.EACCES) || errors.Is(err, syscall.EPERM) {
+		return nil, fmt.Errorf("%w: ping sockets aren't allowed for the group of the API, see net.ipv4.ping_group_range", domain.ErrProbeNotPermitted)
+	}
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+
+	// The connection takes a copy of the descriptor
+	file := os.NewFile(uintptr(fd), "ping")
+	defer file.Close()
+	return net.FilePacketConn(file)
+}
+
+/* ******************************************** PROBE ******************************************** */
+
+type ProbeRepository struct {
+	sequence atomic.Uint32
+}
+
+// NewProbeRepository returns a repository running probes from the host's
+// own network stack
+func NewProbeRepository() *ProbeRepository {
+	return &ProbeRepository{}
+}
+
+func (r *ProbeRepository) DataSources() []domain.DataSource {
+	available := false
+	if conn, err := openPingSocket(false); err == nil {
+		conn.Close()
+		available = true
+	}
+
+	return []domain.DataSource{{
+		Name:      "ping socket",
+		Kind:      "socket",
+		Required:  false,
+		Available: available,
+		Provides:  "ICMP probes",
+	}}
+}
+
+// Probe runs the check of the target once, failing past its timeout
+func (r *ProbeRepository) Probe(target domain.ProbeTarget) (domain.ProbeAttempt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), target.Timeout)
+	defer cancel()
+
+	switch target.Type {
+	case domain.ProbeTCP:
+		return r.probeTCP(ctx, target)
+	case domain.ProbeHTTP:
+		return r.probeHTTP(ctx, target)
+	case domain.ProbeDNS:
+		return r.probeDNS(ctx, target)
+	case domain.ProbeICMP:
+		return r.probeICMP(ctx, target)
+	}
+	return domain.ProbeAttempt{}, fmt.Errorf("unknown probe type %q", target.Type)
+}
+
+// probeTCP measures how long the connection takes to be established
+func (r *ProbeRepository) probeTCP(ctx context.Context, target domain.ProbeTarget) (domain.ProbeAttempt, error) {
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", target.Target)
+	if err != nil {
+		return domain.ProbeAttempt{}, err
+	}
+	latency := time.Since(start)
+	defer conn.Close()
+
+	return domain.ProbeAttempt{Latency: latency, Detail: "connected to " + conn.RemoteAddr().String()}, nil
+}
+
+// probeHTTP measures how long the response headers take to arrive, over a
+// new connection. Redirects aren't followed, so that they can be expected.
+func (r *ProbeRepository) probeHTTP(ctx context.Context, target domain.ProbeTarget) (domain.ProbeAttempt, error) {
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, DisableKeepAlives: true},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target.Target, nil)
+	if err != nil {
+		return domain.ProbeAttempt{}, err
+	}
+
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		return domain.ProbeAttempt{}, err
+	}
+	latency := time.Since(start)
+	response.Body.Close()
+
+	if response.StatusCode != target.ExpectedStatus {
+		return domain.ProbeAttempt{}, fmt.Errorf("unexpected status %s, expected %d", response.Status, target.ExpectedStatus)
+	}
+	return domain.ProbeAttempt{Latency: latency, Detail: response.Status}, nil
+}
+
+// probeDNS measures how long the name takes to be resolved, by the server
+// of the target or the system resolver
+func (r *ProbeRepository) probeDNS(ctx context.Context, target domain.ProbeTarget) (domain.ProbeAttempt, error) {
+	resolver := net.DefaultResolver
+	if target.Server != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, target.Server)
+			},
+		}
+	}
+
+	start := time.Now()
+	addresses, err := resolver.LookupHost(ctx, target.Target)
+	if err != nil {
+		return domain.ProbeAttempt{}, err
+	}
+	return domain.ProbeAttempt{Latency: time.Since(start), Detail: strings.Join(addresses, ", ")}, nil
+}
+
+// probeICMP measures the round trip of an echo request. The name is resolved
+// beforehand, out of the latency.
+func (r *ProbeRepository) probeICMP(ctx context.Context, target domain.ProbeTarget) (domain.ProbeAttempt, error) {
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, target.Target)
+	if err != nil {
+		return domain.ProbeAttempt{}, err
+	}
+	address := addresses[0]
+
+	ipv6 := address.IP.To4() == nil
+	requestType, replyType := byte(icmpEchoRequest), byte(icmpEchoReply)
+	if ipv6 {
+		requestType, replyType = icmp6EchoRequest, icmp6EchoReply
+	}
+
+	conn, err := openPingSocket(ipv6)
+	if err != nil {
+		return domain.ProbeAttempt{}, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	sequence := uint16(r.sequence.Add(1))
+	start := time.Now()
+	if _, err := conn.WriteTo(echoRequest(requestType, sequence), &net.UDPAddr{IP: address.IP, Zone: address.Zone}); err != nil {
+		return domain.ProbeAttempt{}, err
+	}
+
+	// Replies to earlier requests that timed out may still arrive
+	buffer := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return domain.ProbeAttempt{}, err
+		}
+		if isEchoReply(buffer[:n], replyType, sequence) {
+			return domain.ProbeAttempt{Latency: time.Since(start), Detail: "reply from " + address.String()}, nil
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING SCAFFOLDING ******************************************** */
+
+// serveDNS answers the A queries of name with 127.0.0.1 on a local UDP port,
+// and every other one with NXDOMAIN, until the test ends
+func serveDNS(t *testing.T, name string) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 512)
+		for {
+			n, peer, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			query := buffer[:n]
+			if len(query) < 12 {
+				continue
+			}
+
+			// The question: labels, then type and class
+			offset := 12
+			var labels []string
+			for offset < len(query) && query[offset] != 0 {
+				length := int(query[offset])
+				labels = append(labels, string(query[offset+1:offset+1+length]))
+				offset += 1 + length
+			}
+			questionEnd := offset + 5
+			queryType := binary.BigEndian.Uint16(query[offset+1:])
+
+			response := append([]byte{}, query[:questionEnd]...)
+			binary.BigEndian.PutUint16(response[2:], 0x8180) // Response, recursion available
+			binary.BigEndian.PutUint16(response[6:], 0)      // Answers
+			binary.BigEndian.PutUint16(response[8:], 0)
+			binary.BigEndian.PutUint16(response[10:], 0)
+			switch {
+			case !strings.EqualFold(strings.Join(labels, "."), name):
+				binary.BigEndian.PutUint16(response[2:], 0x8183)
+			case queryType == 1:
+				binary.BigEndian.PutUint16(response[6:], 1)
+				// Pointer to the question, A, IN, TTL 60, 4 bytes
+				response = append(response, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 127, 0, 0, 1)
+			}
+			conn.WriteTo(response, peer)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestEchoRequest(t *testing.T) {
+	request := echoRequest(icmpEchoRequest, 258)
+
+	assert.Len(t, request, 16)
+	assert.Equal(t, byte(icmpEchoRequest), request[0])
+	assert.Equal(t, []byte{1, 2}, request[6:8])
+	// A valid checksum sums up to 0
+	assert.Equal(t, uint16(0), icmpChecksum(request))
+
+	reply := append([]byte{}, request...)
+	reply[0] = icmpEchoReply
+	assert.True(t, isEchoReply(reply, icmpEchoReply, 258))
+	assert.False(t, isEchoReply(reply, icmpEchoReply, 259))
+	assert.False(t, isEchoReply(request, icmpEchoReply, 258))
+	assert.False(t, isEchoReply(reply[:4], icmpEchoReply, 258))
+}
+
+/* ******************************************** PROBE TEST ******************************************** */
+
+func TestProbe_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	repo := NewProbeRepository()
+	target := domain.ProbeTarget{Name: "local", Type: domain.ProbeTCP, Target: listener.Addr().String(), Timeout: time.Second}
+
+	attempt, err := repo.Probe(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "connected to "+listener.Addr().String(), attempt.Detail)
+	assert.Greater(t, attempt.Latency, time.Duration(0))
+
+	// Nothing listens on the port anymore
+	listener.Close()
+	_, err = repo.Probe(target)
+	assert.Error(t, err)
+}
+
+func TestProbe_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.WriteHeader(http.StatusNoContent)
+		case "/old":
+			http.Redirect(w, r, "/health", http.StatusMovedPermanently)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Expected status": {
+			"path":     "/health",
+			"expected": http.StatusNoContent,
+			"detail":   "204 No Content",
+		},
+		"Case 2 - Unexpected status": {
+			"path":     "/missing",
+			"expected": http.StatusOK,
+			"error":    "unexpected status 404 Not Found, expected 200",
+		},
+		"Case 3 - Redirects aren't followed": {
+			"path":     "/old",
+			"expected": http.StatusMovedPermanently,
+			"detail":   "301 Moved Permanently",
+		},
+		"Case 4 - Timeout": {
+			"path":     "/slow",
+			"expected": http.StatusOK,
+			"error":    "context deadline exceeded",
+		},
+	}
+
+	repo := NewProbeRepository()
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		attempt, err := repo.Probe(domain.ProbeTarget{
+			Name:           "backend",
+			Type:           domain.ProbeHTTP,
+			Target:         server.URL + caseData["path"].(string),
+			ExpectedStatus: caseData["expected"].(int),
+			Timeout:        50 * time.Millisecond,
+		})
+		if expected, ok := caseData["error"]; ok {
+			assert.ErrorContains(t, err, expected.(string))
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["detail"], attempt.Detail)
+	}
+}
+
+func TestProbe_DNS(t *testing.T) {
+	server := serveDNS(t, "backend.home.lan")
+
+	repo := NewProbeRepository()
+	target := domain.ProbeTarget{Name: "dns", Type: domain.ProbeDNS, Target: "backend.home.lan", Server: server, Timeout: time.Second}
+
+	attempt, err := repo.Probe(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", attempt.Detail)
+
+	target.Target = "missing.home.lan"
+	_, err = repo.Probe(target)
+	var dnsError *net.DNSError
+	assert.ErrorAs(t, err, &dnsError)
+	assert.True(t, dnsError.IsNotFound)
+}
+
+func TestProbe_ICMP(t *testing.T) {
+	repo := NewProbeRepository()
+
+	attempt, err := repo.Probe(domain.ProbeTarget{Name: "loopback", Type: domain.ProbeICMP, Target: "127.0.0.1", Timeout: time.Second})
+	if errors.Is(err, domain.ErrProbeNotPermitted) {
+		assert.False(t, repo.DataSources()[0].Available)
+		t.Skip("ping sockets aren't allowed on this host")
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, "reply from 127.0.0.1", attempt.Detail)
+	assert.True(t, repo.DataSources()[0].Available)
+}
+
+func TestProbe_UnknownType(t *testing.T) {
+	repo := NewProbeRepository()
+
+	_, err := repo.Probe(domain.ProbeTarget{Name: "udp", Type: "udp", Target: "127.0.0.1:53", Timeout: time.Second})
+	assert.Error(t, err)
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// userHZ is the unit of the clock ticks exported by /proc, fixed to 100 on
+// every architecture regardless of the kernel HZ
+const userHZ = 100
+
+var processStateNames = map[string]string{
+	"R": "running", "S": "sleeping", "D": "disk sleep", "T": "stopped",
+	"t": "tracing stop", "Z": "zombie", "X": "dead", "I": "idle",
+	"P": "parked", "W": "waking", "K": "wakekill",
+}
+
+// procStat holds the fields of /proc/[pid]/stat that are used
+type procStat struct {
+	name      string
+	state     string
+	ppid      int
+	ticks     uint64 // utime + stime
+	threads   int
+	startTime uint64 // Ticks since boot
+}
+
+// parseProcStat parses /proc/[pid]/stat. The name goes between parentheses
+// and may contain spaces and parentheses itself, so the fields are split
+// after the last one.
+func parseProcStat(content string) (procStat, error) {
+	open := strings.Index(content, "(")
+	closing := strings.LastIndex(content, ")")
+	if open < 0 || closing < open {
+		return procStat{}, errors.New("unexpected file format")
+	}
+
+	fields := strings.Fields(content[closing+1:])
+	if len(fields) < 20 {
+		return procStat{}, errors.New("unexpected file format")
+	}
+
+	stat := procStat{name: content[open+1 : closing], state: fields[0]}
+	var err error
+	if stat.ppid, err = strconv.Atoi(fields[1]); err != nil {
+		return procStat{}, err
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return procStat{}, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return procStat{}, err
+	}
+	stat.ticks = utime + stime
+	if stat.threads, err = strconv.Atoi(fields[17]); err != nil {
+		return procStat{}, err
+	}
+	if stat.startTime, err = strconv.ParseUint(fields[19], 10, 64); err != nil {
+		return procStat{}, err
+	}
+	return stat, nil
+}
+
+// parseProcStatus returns the fields of /proc/[pid]/status by name
+func parseProcStatus(content string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if found {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+// parseCPUTotal returns the ticks spent by all the CPUs together, from the
+// cpu line of /proc/stat, and how many CPUs there are
+func parseCPUTotal(content string) (uint64, int, error) {
+	var total uint64
+	cpus := 0
+	found := false
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if fields[0] != "cpu" {
+			cpus++
+			continue
+		}
+
+		found = true
+		// guest and guest_nice are already accounted for in user and nice
+		for i, field := range fields[1:] {
+			if i >= 8 {
+				break
+			}
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+			total += value
+		}
+	}
+	if !found {
+		return 0, 0, errors.New("cpu line not found")
+	}
+	return total, max(cpus, 1), nil
+}
+
+// parsePasswd maps the uids of /etc/passwd to their user names
+func parsePasswd(content string) map[int]string {
+	users := map[int]string{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) < 3 {
+			continue
+		}
+		if uid, err := strconv.Atoi(fields[2]); err == nil {
+			users[uid] = fields[0]
+		}
+	}
+	return users
+}
+
+// parseKilobytes parses values like "1234 kB" into bytes
+func parseKilobytes(value string) uint64 {
+	number, _, _ := strings.Cut(value, " ")
+	kilobytes, err := strconv.ParseUint(number, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return kilobytes * 1024
+}
+
+/* ******************************************** PROCESS ******************************************** */
+
+// processKey tells apart a reused PID by the start time of the process
+type processKey struct {
+	pid       int
+	startTime uint64
+}
+
+// processSnapshot is the CPU time of every process at some point, to compute
+// the CPU usage on the next one
+type processSnapshot struct {
+	total   uint64
+	cpus    int
+	ticks   map[processKey]uint64
+	percent map[processKey]float64
+}
+
+// ProcessRepository keeps the CPU time of its previous call, so every
+// consumer of the CPU usage needs an instance of its own, or it gets the usage
+// since the call of another
+type ProcessRepository struct {
+	fileReader FileReader
+
+	mu       sync.Mutex
+	previous *processSnapshot
+}
+
+func NewProcessRepository(fr FileReader) *ProcessRepository {
+	return &ProcessRepository{fileReader: fr}
+}
+
+func (r *ProcessRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc", true, "process list"),
+		fileSource(r.fileReader, "/proc/stat", true, "CPU usage"),
+		fileSource(r.fileReader, "/proc/uptime", false, "CPU usage of the first sample"),
+		fileSource(r.fileReader, "/etc/passwd", false, "user names"),
+	}
+}
+
+// GetProcesses lists every process. The CPU usage is the one since the
+// previous call, or since the process started on the first one.
+func (r *ProcessRepository) GetProcesses() ([]domain.Process, error) {
+	entries, err := listDir(r.fileReader, "/proc")
+	if err != nil {
+		return nil, err
+	}
+	stat, err := readFileString(r.fileReader, "/proc/stat")
+	if err != nil {
+		return nil, err
+	}
+	total, cpus, err := parseCPUTotal(stat)
+	if err != nil {
+		return nil, err
+	}
+
+	var bootTime time.Time
+	if parsed, err := parseBootTime(stat); err == nil {
+		bootTime = parsed
+	}
+	var uptime float64
+	if content, err := readFileString(r.fileReader, "/proc/uptime"); err == nil {
+		uptime, _ = parseUptime(content)
+	}
+	users := map[int]string{}
+	if content, err := readFileString(r.fileReader, "/etc/passwd"); err == nil {
+		users = parsePasswd(content)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := &processSnapshot{
+		total:   total,
+		cpus:    cpus,
+		ticks:   map[processKey]uint64{},
+		percent: map[processKey]float64{},
+	}
+	// Two calls within the same tick can't tell any usage, so the previous
+	// values are kept
+	previous := r.previous
+	keepPrevious := previous != nil && total <= previous.total
+
+	processes := []domain.Process{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry)
+		if err != nil {
+			continue
+		}
+
+		// Processes may end while being read, they are just skipped
+		process, pstat, err := r.readProcess(pid, users)
+		if err != nil {
+			continue
+		}
+		key := processKey{pid: pid, startTime: pstat.startTime}
+		current.ticks[key] = pstat.ticks
+
+		switch {
+		case keepPrevious:
+			current.percent[key] = previous.percent[key]
+		case previous != nil:
+			elapsed := float64(total-previous.total) / float64(previous.cpus)
+			current.percent[key] = float64(pstat.ticks-min(previous.ticks[key], pstat.ticks)) / elapsed * 100
+		default:
+			elapsed := uptime*userHZ - float64(pstat.startTime)
+			if elapsed > 0 {
+				current.percent[key] = float64(pstat.ticks) / elapsed * 100
+			}
+		}
+		process.CPUPercent = current.percent[key]
+
+		if !bootTime.IsZero() {
+			process.StartTime = bootTime.Add(time.Duration(pstat.startTime) * time.Second / userHZ)
+		}
+		processes = append(processes, process)
+	}
+
+	if keepPrevious {
+		current.total = previous.total
+		current.cpus = previous.cpus
+		for key, ticks := range previous.ticks {
+			if _, exists := current.ticks[key]; exists {
+				current.ticks[key] = ticks
+			}
+		}
+	}
+	r.previous = current
+
+	return processes, nil
+}
+
+func (r *ProcessRepository) readProcess(pid int, users map[int]string) (domain.Process, procStat, error) {
+	dir := fmt.Sprintf("/proc/%d", pid)
+
+	content, err := readFileString(r.fileReader, dir+"/stat")
+	if err != nil {
+		return domain.Process{}, procStat{}, err
+	}
+	pstat, err := parseProcStat(content)
+	if err != nil {
+		return domain.Process{}, procStat{}, err
+	}
+
+	content, err = readFileString(r.fileReader, dir+"/status")
+	if err != nil {
+		return domain.Process{}, procStat{}, err
+	}
+	status := parseProcStatus(content)
+
+	process := domain.Process{
+		PID:       pid,
+		PPID:      pstat.ppid,
+		Name:      pstat.name,
+		State:     pstat.state,
+		StateName: processStateNames[pstat.state],
+		RSS:       parseKilobytes(status["VmRSS"]),
+		Threads:   pstat.threads,
+	}
+	// status holds the whole name, stat truncates it
+	if name := status["Name"]; name != "" {
+		process.Name = name
+	}
+	if uids := strings.Fields(status["Uid"]); len(uids) > 0 {
+		if uid, err := strconv.Atoi(uids[0]); err == nil {
+			process.UID = uid
+			process.User = users[uid]
+			if process.User == "" {
+				process.User = uids[0]
+			}
+		}
+	}
+
+	// Arguments are NUL separated, kernel threads have none
+	if cmdline, err := readFileString(r.fileReader, dir+"/cmdline"); err == nil {
+		process.Command = strings.ReplaceAll(cmdline, "\x00", " ")
+	}
+
+	return process, pstat, nil
+}
+
+// ReadPidfile returns the PID written in a pidfile
+func (r *ProcessRepository) ReadPidfile(path string) (int, error) {
+	content, err := readFileString(r.fileReader, path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(content))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid pidfile %s", path)
+	}
+	return pid, nil
+}
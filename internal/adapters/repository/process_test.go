@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+// All the mock structures and functions are defined already in other repositories files
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseProcStat(t *testing.T) {
+	stat, err := parseProcStat("512 (my (app) srv) R 1 512 512 0 -1 4194560 1200 3000 10 2 5000 1000 5 3 20 0 4 0 4000 170000000 2500")
+	assert.NoError(t, err)
+	assert.Equal(t, procStat{name: "my (app) srv", state: "R", ppid: 1, ticks: 6000, threads: 4, startTime: 4000}, stat)
+
+	for _, input := range []string{"", "512 my app R 1", "512 (app) R 1 512"} {
+		_, err := parseProcStat(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseCPUTotal(t *testing.T) {
+	total, cpus, err := parseCPUTotal("cpu  10 20 30 40 50 60 70 80 90 100\ncpu0 1 2 3\ncpu1 1 2 3\nintr 1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(360), total)
+	assert.Equal(t, 2, cpus)
+
+	_, _, err = parseCPUTotal("intr 1")
+	assert.Error(t, err)
+}
+
+func TestParsePasswd(t *testing.T) {
+	users := parsePasswd("root:x:0:0:root:/root:/bin/bash\n#broken\npi:x:1000:1000::/home/pi:/bin/bash\n")
+	assert.Equal(t, map[int]string{0: "root", 1000: "pi"}, users)
+}
+
+/* ******************************************** PROCESS TEST ******************************************** */
+
+func TestGetProcesses(t *testing.T) {
+
+	reader := &FixtureFileReader{Root: "testdata/process/t0"}
+	repo := NewProcessRepository(reader)
+
+	// The first sample has the average since each process started
+	processes, err := repo.GetProcesses()
+	assert.NoError(t, err)
+	assert.Len(t, processes, 4)
+
+	byPID := map[int]domain.Process{}
+	for _, process := range processes {
+		byPID[process.PID] = process
+	}
+
+	assert.InDelta(t, 6000.0/21000*100, byPID[512].CPUPercent, 0.0001)
+	assert.InDelta(t, 3000.0/19000*100, byPID[777].CPUPercent, 0.0001)
+
+	process := byPID[512]
+	process.CPUPercent = 0
+	assert.Equal(t, domain.Process{
+		PID:       512,
+		PPID:      1,
+		Name:      "my (app) server",
+		User:      "pi",
+		UID:       1000,
+		State:     "R",
+		StateName: "running",
+		RSS:       52000 * 1024,
+		Threads:   4,
+		StartTime: time.Unix(1718000040, 0).UTC(),
+		Command:   "/usr/bin/python3 /home/pi/app.py --port 8000",
+	}, process)
+
+	// Kernel threads have neither command line nor memory
+	assert.Equal(t, "", byPID[42].Command)
+	assert.Equal(t, uint64(0), byPID[42].RSS)
+	assert.Equal(t, "idle", byPID[42].StateName)
+
+	// Unknown users are reported by uid
+	assert.Equal(t, "999", byPID[777].User)
+
+	// Then the usage since the previous sample, where the 4 CPUs ran 100
+	// ticks each. Process 901 ended before being read.
+	reader.Root = "testdata/process/t1"
+	processes, err = repo.GetProcesses()
+	assert.NoError(t, err)
+	assert.Len(t, processes, 4)
+
+	percents := map[int]float64{}
+	for _, process := range processes {
+		percents[process.PID] = process.CPUPercent
+	}
+	assert.Equal(t, map[int]float64{1: 0, 42: 0, 512: 200, 777: 50}, percents)
+
+	// A sample within the same tick keeps the previous usage
+	processes, err = repo.GetProcesses()
+	assert.NoError(t, err)
+	for _, process := range processes {
+		assert.Equal(t, percents[process.PID], process.CPUPercent, process.PID)
+	}
+}
+
+func TestGetProcesses_FileError(t *testing.T) {
+
+	repo := NewProcessRepository(&MissingFileReader{})
+	_, err := repo.GetProcesses()
+	assert.Error(t, err)
+}
+
+func TestReadPidfile(t *testing.T) {
+
+	repo := NewProcessRepository(&FixtureFileReader{Root: "testdata/process/t0"})
+
+	pid, err := repo.ReadPidfile("/run/influxd.pid")
+	assert.NoError(t, err)
+	assert.Equal(t, 777, pid)
+
+	_, err = repo.ReadPidfile("/run/broken.pid")
+	assert.Error(t, err)
+
+	_, err = repo.ReadPidfile("/run/missing.pid")
+	assert.Error(t, err)
+}
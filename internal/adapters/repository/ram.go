@@ -19,6 +19,12 @@ func NewRAMRepository(fr FileReader) *RAMRepository {
 	return &RAMRepository{fileReader: fr}
 }
 
+func (r *RAMRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/meminfo", true, "memory usage"),
+	}
+}
+
 /* ******************************************** RAM ******************************************** */
 
 func (r *RAMRepository) GetRAMStats() (domain.RAM, error) {
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// Flags of include/uapi/linux/route.h and ipv6_route.h
+const (
+	routeGateway = 0x0002
+	routeReject  = 0x0200
+	routeLocal   = 0x80000000 // IPv6 only, the addresses of the host
+)
+
+// decodeRouteIPv4 decodes addresses of /proc/net/route, 32 bits words in
+// host order, little endian on every board this API runs on
+func decodeRouteIPv4(field string) (netip.Addr, error) {
+	value, err := strconv.ParseUint(field, 16, 32)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid route address %q", field)
+	}
+	var raw [4]byte
+	binary.LittleEndian.PutUint32(raw[:], uint32(value))
+	return netip.AddrFrom4(raw), nil
+}
+
+// decodeRouteIPv6 decodes addresses of /proc/net/ipv6_route, in network order
+func decodeRouteIPv6(field string) (netip.Addr, error) {
+	raw, err := hex.DecodeString(field)
+	if err != nil || len(raw) != 16 {
+		return netip.Addr{}, fmt.Errorf("invalid route address %q", field)
+	}
+	return netip.AddrFrom16([16]byte(raw)), nil
+}
+
+// parseRouteLine parses an entry of /proc/net/route. Reject routes, which
+// discard the traffic, are left out.
+func parseRouteLine(line string) (domain.Route, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 8 || fields[0] == "Iface" {
+		return domain.Route{}, false
+	}
+
+	flags, err := strconv.ParseUint(fields[3], 16, 32)
+	if err != nil || flags&routeReject != 0 {
+		return domain.Route{}, false
+	}
+	destination, err := decodeRouteIPv4(fields[1])
+	if err != nil {
+		return domain.Route{}, false
+	}
+	gateway, err := decodeRouteIPv4(fields[2])
+	if err != nil {
+		return domain.Route{}, false
+	}
+	mask, err := strconv.ParseUint(fields[7], 16, 32)
+	if err != nil {
+		return domain.Route{}, false
+	}
+	metric, _ := strconv.ParseUint(fields[6], 10, 32)
+
+	prefix := netip.PrefixFrom(destination, bits.OnesCount32(uint32(mask)))
+	route := domain.Route{
+		Family:      "inet",
+		Destination: prefix.String(),
+		Interface:   fields[0],
+		Metric:      uint32(metric),
+		Default:     prefix.Bits() == 0,
+	}
+	if flags&routeGateway != 0 {
+		route.Gateway = gateway.String()
+	}
+	return route, true
+}
+
+// parseIPv6RouteLine parses an entry of /proc/net/ipv6_route. It lists
+// every table, so the routes of the addresses of the host, the multicast
+// ones every interface gets and the ones of the loopback interface are left
+// out, as well as reject routes.
+func parseIPv6RouteLine(line string) (domain.Route, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 10 || fields[9] == "lo" {
+		return domain.Route{}, false
+	}
+
+	flags, err := strconv.ParseUint(fields[8], 16, 32)
+	if err != nil || flags&(routeReject|routeLocal) != 0 {
+		return domain.Route{}, false
+	}
+	destination, err := decodeRouteIPv6(fields[0])
+	if err != nil || destination.IsMulticast() {
+		return domain.Route{}, false
+	}
+	prefixLength, err := strconv.ParseUint(fields[1], 16, 8)
+	if err != nil {
+		return domain.Route{}, false
+	}
+	nextHop, err := decodeRouteIPv6(fields[4])
+	if err != nil {
+		return domain.Route{}, false
+	}
+	metric, _ := strconv.ParseUint(fields[5], 16, 32)
+
+	route := domain.Route{
+		Family:      "inet6",
+		Destination: netip.PrefixFrom(destination, int(prefixLength)).String(),
+		Interface:   fields[9],
+		Metric:      uint32(metric),
+		Default:     prefixLength == 0,
+	}
+	if !nextHop.IsUnspecified() {
+		route.Gateway = nextHop.String()
+	}
+	return route, true
+}
+
+// parseResolvConf parses the nameserver, search, domain and options lines of
+// /etc/resolv.conf. As in the resolver, the last of search and domain wins.
+func parseResolvConf(content string) domain.Resolver {
+	resolver := domain.Resolver{Nameservers: []string{}, Search: []string{}, Options: []string{}}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+
+		switch fields[0] {
+		case "nameserver":
+			resolver.Nameservers = append(resolver.Nameservers, fields[1])
+		case "search", "domain":
+			resolver.Search = fields[1:]
+		case "options":
+			resolver.Options = append(resolver.Options, fields[1:]...)
+		}
+	}
+	return resolver
+}
+
+// defaultGateway returns the default route of lowest metric of the family,
+// the first one on a tie as the kernel does. Point-to-point links, like
+// wwan0, wg0 or ppp0, route without a gateway, through their interface.
+func defaultGateway(routes []domain.Route, family string) *domain.Route {
+	var selected *domain.Route
+	for i := range routes {
+		route := routes[i]
+		if route.Family != family || !route.Default {
+			continue
+		}
+		if selected == nil || route.Metric < selected.Metric {
+			selected = &route
+		}
+	}
+	return selected
+}
+
+/* ******************************************** ROUTES ******************************************** */
+
+type RouteRepository struct {
+	fileReader FileReader
+}
+
+func NewRouteRepository(fr FileReader) *RouteRepository {
+	return &RouteRepository{fileReader: fr}
+}
+
+func (r *RouteRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/net/route", true, "IPv4 routes"),
+		fileSource(r.fileReader, "/proc/net/ipv6_route", false, "IPv6 routes"),
+		fileSource(r.fileReader, "/etc/resolv.conf", false, "DNS resolver"),
+	}
+}
+
+func (r *RouteRepository) GetRouting() (domain.Routing, error) {
+	content, err := readFileString(r.fileReader, "/proc/net/route")
+	if err != nil {
+		return domain.Routing{}, err
+	}
+
+	routes := []domain.Route{}
+	for _, line := range strings.Split(content, "\n") {
+		if route, ok := parseRouteLine(line); ok {
+			routes = append(routes, route)
+		}
+	}
+
+	// Missing without IPv6
+	content, err = readFileString(r.fileReader, "/proc/net/ipv6_route")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return domain.Routing{}, err
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if route, ok := parseIPv6RouteLine(line); ok {
+			routes = append(routes, route)
+		}
+	}
+
+	resolver := parseResolvConf("")
+	if content, err := readFileString(r.fileReader, "/etc/resolv.conf"); err == nil {
+		resolver = parseResolvConf(content)
+	}
+
+	return domain.Routing{
+		Routes:          routes,
+		DefaultGateway:  defaultGateway(routes, "inet"),
+		DefaultGateway6: defaultGateway(routes, "inet6"),
+		Resolver:        resolver,
+	}, nil
+}
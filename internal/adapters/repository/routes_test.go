@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseRouteLine(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Default": {
+			"line":     "eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0",
+			"expected": domain.Route{Family: "inet", Destination: "0.0.0.0/0", Gateway: "192.168.1.1", Interface: "eth0", Metric: 100, Default: true},
+			"valid":    true,
+		},
+		"Case 2 - Directly connected": {
+			"line":     "docker0\t000011AC\t00000000\t0001\t0\t0\t0\t0000FFFF\t0\t0\t0",
+			"expected": domain.Route{Family: "inet", Destination: "172.17.0.0/16", Interface: "docker0"},
+			"valid":    true,
+		},
+		"Case 3 - Host": {
+			"line":     "wg0\t0A00000A\t00000000\t0005\t0\t0\t0\tFFFFFFFF\t0\t0\t0",
+			"expected": domain.Route{Family: "inet", Destination: "10.0.0.10/32", Interface: "wg0"},
+			"valid":    true,
+		},
+		"Case 4 - Reject": {
+			"line":  "eth0\t0000000A\t00000000\t0201\t0\t0\t0\t000000FF\t0\t0\t0",
+			"valid": false,
+		},
+		"Case 5 - Header": {
+			"line":  "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT",
+			"valid": false,
+		},
+		"Case 6 - Bad address": {
+			"line":  "eth0\tXX\t00000000\t0001\t0\t0\t0\t0000FFFF\t0\t0\t0",
+			"valid": false,
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		route, valid := parseRouteLine(caseData["line"].(string))
+		assert.Equal(t, caseData["valid"], valid)
+		if valid {
+			assert.Equal(t, caseData["expected"], route)
+		}
+	}
+}
+
+func TestParseResolvConf(t *testing.T) {
+	assert.Equal(t, domain.Resolver{
+		Nameservers: []string{"192.168.1.1", "fd00::1"},
+		Search:      []string{"home.lan", "lab.home.lan"},
+		Options:     []string{"edns0", "timeout:2"},
+	}, parseResolvConf(readFixture(t, "testdata/routes/pi/etc/resolv.conf")))
+
+	// The last of domain and search wins
+	resolver := parseResolvConf("search a.lan\ndomain b.lan\nnameserver 127.0.0.53\n")
+	assert.Equal(t, []string{"b.lan"}, resolver.Search)
+	assert.Equal(t, []string{"127.0.0.53"}, resolver.Nameservers)
+}
+
+/* ******************************************** ROUTES TEST ******************************************** */
+
+func TestGetRouting(t *testing.T) {
+	repo := NewRouteRepository(&FixtureFileReader{Root: "testdata/routes/pi"})
+
+	routing, err := repo.GetRouting()
+	assert.NoError(t, err)
+
+	ethDefault := domain.Route{Family: "inet", Destination: "0.0.0.0/0", Gateway: "192.168.1.1", Interface: "eth0", Metric: 100, Default: true}
+	ethDefault6 := domain.Route{Family: "inet6", Destination: "::/0", Gateway: "fe80::1", Interface: "eth0", Metric: 1024, Default: true}
+	assert.Equal(t, []domain.Route{
+		ethDefault,
+		{Family: "inet", Destination: "0.0.0.0/0", Gateway: "192.168.1.1", Interface: "wlan0", Metric: 600, Default: true},
+		{Family: "inet", Destination: "192.168.1.0/24", Interface: "eth0", Metric: 100},
+		{Family: "inet", Destination: "192.168.1.0/24", Interface: "wlan0", Metric: 600},
+		{Family: "inet", Destination: "172.17.0.0/16", Interface: "docker0"},
+		{Family: "inet", Destination: "10.0.0.10/32", Interface: "wg0"},
+		ethDefault6,
+		{Family: "inet6", Destination: "2001:db8:1::/64", Interface: "eth0", Metric: 256},
+		{Family: "inet6", Destination: "fe80::/64", Interface: "eth0", Metric: 256},
+	}, routing.Routes)
+	assert.Equal(t, &ethDefault, routing.DefaultGateway)
+	assert.Equal(t, &ethDefault6, routing.DefaultGateway6)
+	assert.Equal(t, []string{"192.168.1.1", "fd00::1"}, routing.Resolver.Nameservers)
+}
+
+func TestGetRouting_PointToPoint(t *testing.T) {
+	// An LTE modem, and IPv6 through a WireGuard tunnel
+	repo := NewRouteRepository(&FixtureFileReader{Root: "testdata/routes/ptp"})
+
+	routing, err := repo.GetRouting()
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Route{Family: "inet", Destination: "0.0.0.0/0", Interface: "wwan0", Metric: 700, Default: true}, routing.DefaultGateway)
+	assert.Equal(t, &domain.Route{Family: "inet6", Destination: "::/0", Interface: "wg0", Metric: 1024, Default: true}, routing.DefaultGateway6)
+}
+
+func TestGetRouting_Isolated(t *testing.T) {
+	// Without default route, IPv6 nor resolv.conf
+	repo := NewRouteRepository(&FixtureFileReader{Root: "testdata/routes/isolated"})
+
+	routing, err := repo.GetRouting()
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Routing{
+		Routes:   []domain.Route{{Family: "inet", Destination: "192.168.1.0/24", Interface: "eth0", Metric: 100}},
+		Resolver: domain.Resolver{Nameservers: []string{}, Search: []string{}, Options: []string{}},
+	}, routing)
+
+	repo = NewRouteRepository(&MissingFileReader{})
+	_, err = repo.GetRouting()
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+const hwmonRoot = "/sys/class/hwmon"
+
+// hwmonInput matches the input attributes of a hwmon chip, like temp1_input
+var hwmonInput = regexp.MustCompile(`^(temp|fan|in|curr|power)(\d+)_input$`)
+
+// hwmonTypes describes each kind of input: what it measures, its unit and the
+// divisor from the unit of sysfs, as documented in the kernel hwmon sysfs ABI
+var hwmonTypes = map[string]struct {
+	kind    string
+	unit    string
+	divisor float64
+	order   int
+}{
+	"temp":  {"temperature", "°C", 1e3, 0},
+	"fan":   {"fan", "RPM", 1, 1},
+	"in":    {"voltage", "V", 1e3, 2},
+	"curr":  {"current", "A", 1e3, 3},
+	"power": {"power", "W", 1e6, 4},
+}
+
+// hwmonInputFile is an input attribute found in the directory of a chip
+type hwmonInputFile struct {
+	prefix string
+	index  int
+}
+
+// parseHwmonInputs picks the input attributes out of the files of a chip,
+// ordered by type and then by number, so that temp2 goes before temp10
+func parseHwmonInputs(files []string) []hwmonInputFile {
+	inputs := []hwmonInputFile{}
+	for _, file := range files {
+		match := hwmonInput.FindStringSubmatch(file)
+		if match == nil {
+			continue
+		}
+		index, _ := strconv.Atoi(match[2])
+		inputs = append(inputs, hwmonInputFile{prefix: match[1], index: index})
+	}
+
+	sort.Slice(inputs, func(i, j int) bool {
+		if inputs[i].prefix != inputs[j].prefix {
+			return hwmonTypes[inputs[i].prefix].order < hwmonTypes[inputs[j].prefix].order
+		}
+		return inputs[i].index < inputs[j].index
+	})
+	return inputs
+}
+
+// hwmonIndex returns the number of a hwmon device, to sort hwmon10 after
+// hwmon2
+func hwmonIndex(device string) int {
+	index, err := strconv.Atoi(strings.TrimPrefix(device, "hwmon"))
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+/* ******************************************** SENSOR ******************************************** */
+
+type SensorRepository struct {
+	fileReader FileReader
+}
+
+func NewSensorRepository(fr FileReader) *SensorRepository {
+	return &SensorRepository{fileReader: fr}
+}
+
+func (r *SensorRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, hwmonRoot, false, "hwmon sensors"),
+	}
+}
+
+// GetSensors returns every input of every hwmon chip. Inputs that can't be
+// read at the moment, like the ones of a disconnected fan, are skipped.
+func (r *SensorRepository) GetSensors() ([]domain.Sensor, error) {
+	devices, err := listDir(r.fileReader, hwmonRoot)
+	if err != nil {
+		return nil, errors.New("hwmon class not found")
+	}
+	sort.SliceStable(devices, func(i, j int) bool {
+		return hwmonIndex(devices[i]) < hwmonIndex(devices[j])
+	})
+
+	sensors := []domain.Sensor{}
+	for _, device := range devices {
+		sensors = append(sensors, r.readChip(device)...)
+	}
+	return sensors, nil
+}
+
+// readChip reads the inputs of a hwmon device. Older drivers keep their
+// attributes in the device directory instead.
+func (r *SensorRepository) readChip(device string) []domain.Sensor {
+	dir := path.Join(hwmonRoot, device)
+	chip, err := readFileString(r.fileReader, dir+"/name")
+	if err != nil {
+		dir = path.Join(dir, "device")
+		if chip, err = readFileString(r.fileReader, dir+"/name"); err != nil {
+			return nil
+		}
+	}
+
+	files, err := listDir(r.fileReader, dir)
+	if err != nil {
+		return nil
+	}
+
+	sensors := []domain.Sensor{}
+	for _, input := range parseHwmonInputs(files) {
+		name := input.prefix + strconv.Itoa(input.index)
+		content, err := readFileString(r.fileReader, path.Join(dir, name+"_input"))
+		if err != nil {
+			continue
+		}
+		raw, err := strconv.ParseFloat(strings.TrimSpace(content), 64)
+		if err != nil {
+			continue
+		}
+		label, _ := readFileString(r.fileReader, path.Join(dir, name+"_label"))
+
+		sensorType := hwmonTypes[input.prefix]
+		sensors = append(sensors, domain.Sensor{
+			Chip:   chip,
+			Device: device,
+			Name:   name,
+			Label:  strings.TrimSpace(label),
+			Type:   sensorType.kind,
+			Value:  raw / sensorType.divisor,
+			Unit:   sensorType.unit,
+		})
+	}
+	return sensors
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseHwmonInputs(t *testing.T) {
+	inputs := parseHwmonInputs([]string{
+		"fan1_input", "name", "power1_input", "temp10_input", "temp10_label",
+		"temp2_input", "in0_input", "temp2_crit", "curr1_input", "uevent",
+	})
+	assert.Equal(t, []hwmonInputFile{
+		{"temp", 2}, {"temp", 10}, {"fan", 1}, {"in", 0}, {"curr", 1}, {"power", 1},
+	}, inputs)
+}
+
+/* ******************************************** SENSOR TEST ******************************************** */
+
+func TestGetSensors(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Pi 5 with fan, RP1 ADC, I2C power monitor and legacy chip": {
+			"root": "testdata/sensor/pi5",
+			"expected": []domain.Sensor{
+				{Chip: "cpu_thermal", Device: "hwmon0", Name: "temp1", Type: "temperature", Value: 52.35, Unit: "°C"},
+				{Chip: "rp1_adc", Device: "hwmon1", Name: "temp1", Type: "temperature", Value: 56.512, Unit: "°C"},
+				{Chip: "rp1_adc", Device: "hwmon1", Name: "in1", Type: "voltage", Value: 1.466, Unit: "V"},
+				{Chip: "rp1_adc", Device: "hwmon1", Name: "in2", Type: "voltage", Value: 1.463, Unit: "V"},
+				{Chip: "rp1_adc", Device: "hwmon1", Name: "in3", Type: "voltage", Value: 1.46, Unit: "V"},
+				{Chip: "rp1_adc", Device: "hwmon1", Name: "in4", Type: "voltage", Value: 1.464, Unit: "V"},
+				{Chip: "pwmfan", Device: "hwmon3", Name: "fan1", Type: "fan", Value: 2945, Unit: "RPM"},
+				{Chip: "ina219", Device: "hwmon10", Name: "in0", Type: "voltage", Value: 0.012, Unit: "V"},
+				{Chip: "ina219", Device: "hwmon10", Name: "in1", Type: "voltage", Value: 5.104, Unit: "V"},
+				{Chip: "ina219", Device: "hwmon10", Name: "curr1", Type: "current", Value: 1.203, Unit: "A"},
+				{Chip: "ina219", Device: "hwmon10", Name: "power1", Type: "power", Value: 6.14, Unit: "W"},
+				{Chip: "lm75", Device: "hwmon11", Name: "temp1", Type: "temperature", Value: 31.5, Unit: "°C"},
+			},
+		},
+		"Case 2 - x86 with labelled core temperatures": {
+			"root": "testdata/sensor/x86",
+			"expected": []domain.Sensor{
+				{Chip: "acpitz", Device: "hwmon0", Name: "temp1", Type: "temperature", Value: 27.8, Unit: "°C"},
+				{Chip: "coretemp", Device: "hwmon1", Name: "temp1", Label: "Package id 0", Type: "temperature", Value: 45, Unit: "°C"},
+				{Chip: "coretemp", Device: "hwmon1", Name: "temp2", Label: "Core 0", Type: "temperature", Value: 43, Unit: "°C"},
+				{Chip: "coretemp", Device: "hwmon1", Name: "temp10", Label: "Core 1", Type: "temperature", Value: 44, Unit: "°C"},
+			},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		repo := NewSensorRepository(&FixtureFileReader{Root: caseData["root"].(string)})
+
+		sensors, err := repo.GetSensors()
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"].([]domain.Sensor), sensors)
+	}
+}
+
+func TestGetSensorsMissingHwmon(t *testing.T) {
+	repo := NewSensorRepository(&FixtureFileReader{Root: "testdata/sensor/none"})
+
+	_, err := repo.GetSensors()
+	assert.Error(t, err)
+	assert.Equal(t, "hwmon class not found", err.Error())
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ************************************* MOCKING SCAFFOLDING ************************************* */
+
+type LinkReader interface {
+	Readlink(name string) (string, error)
+}
+
+type RealLinkReader struct{}
+
+func (r *RealLinkReader) Readlink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+/* ******************************************** AUX ******************************************** */
+
+// socketTables are the files of /proc/net listing sockets, by protocol
+var socketTables = []string{"tcp", "tcp6", "udp", "udp6"}
+
+// tcpStates are the states of include/net/tcp_states.h
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+	"0C": "NEW_SYN_RECV",
+}
+
+func socketState(protocol string, code string) string {
+	// UDP reuses the TCP states, unconnected sockets being closed
+	if strings.HasPrefix(protocol, "udp") && code == "07" {
+		return "UNCONN"
+	}
+	if state, exists := tcpStates[code]; exists {
+		return state
+	}
+	return code
+}
+
+// decodeSocketAddress decodes addresses like 0100007F:0016. The address is
+// printed as 32 bits words in host order, little endian on every board this
+// API runs on, and the port in network order.
+func decodeSocketAddress(field string) (string, int, error) {
+	addressHex, portHex, found := strings.Cut(field, ":")
+	if !found {
+		return "", 0, fmt.Errorf("invalid socket address %q", field)
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid socket port %q", field)
+	}
+	raw, err := hex.DecodeString(addressHex)
+	if err != nil || (len(raw) != 4 && len(raw) != 16) {
+		return "", 0, fmt.Errorf("invalid socket address %q", field)
+	}
+
+	for word := 0; word < len(raw); word += 4 {
+		slices.Reverse(raw[word : word+4])
+	}
+	address, _ := netip.AddrFromSlice(raw)
+	return address.String(), int(port), nil
+}
+
+// parseSocketLine parses an entry of /proc/net/tcp, udp and their IPv6
+// versions, which share the same columns
+func parseSocketLine(protocol string, line string) (domain.Socket, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 10 || !strings.HasSuffix(fields[0], ":") {
+		return domain.Socket{}, fmt.Errorf("invalid socket line %q", line)
+	}
+
+	localAddress, localPort, err := decodeSocketAddress(fields[1])
+	if err != nil {
+		return domain.Socket{}, err
+	}
+	remoteAddress, remotePort, err := decodeSocketAddress(fields[2])
+	if err != nil {
+		return domain.Socket{}, err
+	}
+	txQueue, rxQueue, _ := strings.Cut(fields[4], ":")
+	tx, _ := strconv.ParseUint(txQueue, 16, 64)
+	rx, _ := strconv.ParseUint(rxQueue, 16, 64)
+	uid, _ := strconv.Atoi(fields[7])
+
+	return domain.Socket{
+		Protocol:      protocol,
+		LocalAddress:  localAddress,
+		LocalPort:     localPort,
+		RemoteAddress: remoteAddress,
+		RemotePort:    remotePort,
+		State:         socketState(protocol, fields[3]),
+		UID:           uid,
+		Inode:         parseUint(fields[9]),
+		TxQueue:       tx,
+		RxQueue:       rx,
+		Processes:     []domain.SocketProcess{},
+	}, nil
+}
+
+// socketInode returns the inode of fd links like socket:[12345]
+func socketInode(link string) (uint64, bool) {
+	inode, found := strings.CutPrefix(link, "socket:[")
+	if !found || !strings.HasSuffix(inode, "]") {
+		return 0, false
+	}
+	value, err := strconv.ParseUint(strings.TrimSuffix(inode, "]"), 10, 64)
+	return value, err == nil
+}
+
+/* ******************************************** SOCKETS ******************************************** */
+
+type SocketRepository struct {
+	fileReader FileReader
+	linkReader LinkReader
+}
+
+func NewSocketRepository(fr FileReader, lr LinkReader) *SocketRepository {
+	return &SocketRepository{fileReader: fr, linkReader: lr}
+}
+
+func (r *SocketRepository) DataSources() []domain.DataSource {
+	sources := make([]domain.DataSource, 0, len(socketTables)+1)
+	for _, protocol := range socketTables {
+		// Without IPv6, only tcp and udp exist
+		sources = append(sources, fileSource(r.fileReader, "/proc/net/"+protocol, !strings.HasSuffix(protocol, "6"), protocol+" sockets"))
+	}
+	return append(sources, fileSource(r.fileReader, "/proc", false, "processes holding the sockets"))
+}
+
+// GetSockets lists the sockets of every table, along with the processes
+// holding them among the ones the API can see
+func (r *SocketRepository) GetSockets() ([]domain.Socket, error) {
+	sockets := []domain.Socket{}
+	for _, protocol := range socketTables {
+		tableSockets, err := r.readSocketTable(protocol)
+		if err != nil {
+			if strings.HasSuffix(protocol, "6") && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		sockets = append(sockets, tableSockets...)
+	}
+
+	owners := r.readSocketOwners()
+	for i := range sockets {
+		// Sockets in TIME_WAIT have no inode left
+		if sockets[i].Inode != 0 {
+			sockets[i].Processes = append(sockets[i].Processes, owners[sockets[i].Inode]...)
+		}
+	}
+	return sockets, nil
+}
+
+func (r *SocketRepository) readSocketTable(protocol string) ([]domain.Socket, error) {
+	file, err := r.fileReader.Open("/proc/net/" + protocol)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var sockets []domain.Socket
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // Header
+	for scanner.Scan() {
+		socket, err := parseSocketLine(protocol, scanner.Text())
+		if err != nil {
+			continue
+		}
+		sockets = append(sockets, socket)
+	}
+	return sockets, scanner.Err()
+}
+
+// readSocketOwners maps the inode of every socket to the processes holding
+// it, from the links of /proc/[pid]/fd. The fds of processes of other users
+// can only be read as root.
+func (r *SocketRepository) readSocketOwners() map[uint64][]domain.SocketProcess {
+	owners := map[uint64][]domain.SocketProcess{}
+
+	entries, err := listDir(r.fileReader, "/proc")
+	if err != nil {
+		return owners
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry)
+		if err != nil {
+			continue
+		}
+		dir := fmt.Sprintf("/proc/%d", pid)
+		fds, err := listDir(r.fileReader, dir+"/fd")
+		if err != nil {
+			continue
+		}
+
+		name, _ := readFileString(r.fileReader, dir+"/comm")
+		held := map[uint64]bool{}
+		for _, fd := range fds {
+			link, err := r.linkReader.Readlink(dir + "/fd/" + fd)
+			if err != nil {
+				continue
+			}
+			// Duplicated fds hold the same socket
+			if inode, ok := socketInode(link); ok && !held[inode] {
+				held[inode] = true
+				owners[inode] = append(owners[inode], domain.SocketProcess{PID: pid, Name: name})
+			}
+		}
+	}
+
+	// /proc is listed by name, not by PID
+	for _, processes := range owners {
+		sort.Slice(processes, func(i, j int) bool { return processes[i].PID < processes[j].PID })
+	}
+	return owners
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ************************************* MOCKING SCAFFOLDING ************************************* */
+
+// FixtureLinkReader reads the symbolic links of a fixture tree under
+// testdata, like the fds of /proc/[pid]/fd
+type FixtureLinkReader struct {
+	Root string
+}
+
+func (f *FixtureLinkReader) Readlink(name string) (string, error) {
+	return os.Readlink(filepath.Join(f.Root, name))
+}
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestDecodeSocketAddress(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - IPv4 any":        {"field": "00000000:0016", "address": "0.0.0.0", "port": 22},
+		"Case 2 - IPv4 loopback":   {"field": "0100007F:0050", "address": "127.0.0.1", "port": 80},
+		"Case 3 - IPv4 LAN":        {"field": "0F01A8C0:D431", "address": "192.168.1.15", "port": 54321},
+		"Case 4 - IPv6 any":        {"field": "00000000000000000000000000000000:0016", "address": "::", "port": 22},
+		"Case 5 - IPv6 loopback":   {"field": "00000000000000000000000001000000:1F90", "address": "::1", "port": 8080},
+		"Case 6 - IPv4 mapped":     {"field": "0000000000000000FFFF00000100007F:C350", "address": "::ffff:127.0.0.1", "port": 50000},
+		"Case 7 - IPv6 link-local": {"field": "000080FE00000000FF0E1A02FE2B3C4D:0222", "address": "fe80::21a:eff:4d3c:2bfe", "port": 546},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		address, port, err := decodeSocketAddress(caseData["field"].(string))
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["address"], address)
+		assert.Equal(t, caseData["port"], port)
+	}
+
+	for _, field := range []string{"0100007F", "0100007F:XYZ", "01007F:0016", "zz00007F:0016"} {
+		_, _, err := decodeSocketAddress(field)
+		assert.Error(t, err)
+	}
+}
+
+func TestSocketState(t *testing.T) {
+	assert.Equal(t, "LISTEN", socketState("tcp6", "0A"))
+	assert.Equal(t, "CLOSE", socketState("tcp", "07"))
+	assert.Equal(t, "UNCONN", socketState("udp", "07"))
+	assert.Equal(t, "ESTABLISHED", socketState("udp6", "01"))
+	assert.Equal(t, "FF", socketState("tcp", "FF"))
+}
+
+func TestSocketInode(t *testing.T) {
+	inode, ok := socketInode("socket:[12345]")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(12345), inode)
+
+	for _, link := range []string{"/dev/null", "pipe:[999]", "socket:[abc]", "socket:[1"} {
+		_, ok := socketInode(link)
+		assert.False(t, ok)
+	}
+}
+
+/* ******************************************** SOCKETS TEST ******************************************** */
+
+func TestGetSockets(t *testing.T) {
+	root := "testdata/sockets"
+	repo := NewSocketRepository(&FixtureFileReader{Root: root}, &FixtureLinkReader{Root: root})
+
+	sockets, err := repo.GetSockets()
+	assert.NoError(t, err)
+
+	sshd := []domain.SocketProcess{{PID: 101, Name: "sshd"}}
+	assert.Equal(t, []domain.Socket{
+		{
+			Protocol: "tcp", LocalAddress: "0.0.0.0", LocalPort: 22, RemoteAddress: "0.0.0.0", RemotePort: 0,
+			State: "LISTEN", UID: 0, Inode: 1001, Processes: sshd,
+		},
+		{
+			Protocol: "tcp", LocalAddress: "127.0.0.1", LocalPort: 80, RemoteAddress: "0.0.0.0", RemotePort: 0,
+			State: "LISTEN", UID: 33, Inode: 2001,
+			Processes: []domain.SocketProcess{{PID: 202, Name: "nginx"}, {PID: 404, Name: "nginx"}},
+		},
+		{
+			Protocol: "tcp", LocalAddress: "192.168.1.15", LocalPort: 22, RemoteAddress: "192.168.1.100", RemotePort: 54321,
+			State: "ESTABLISHED", UID: 0, Inode: 1003, TxQueue: 36, Processes: sshd,
+		},
+		{
+			Protocol: "tcp", LocalAddress: "192.168.1.15", LocalPort: 40000, RemoteAddress: "8.8.8.8", RemotePort: 443,
+			State: "TIME_WAIT", UID: 0, Inode: 0, Processes: []domain.SocketProcess{},
+		},
+		{
+			Protocol: "tcp6", LocalAddress: "::", LocalPort: 22, RemoteAddress: "::", RemotePort: 0,
+			State: "LISTEN", UID: 0, Inode: 1002, Processes: sshd,
+		},
+		{
+			// Held by a process of another user
+			Protocol: "tcp6", LocalAddress: "::ffff:127.0.0.1", LocalPort: 8080, RemoteAddress: "::ffff:127.0.0.1", RemotePort: 50000,
+			State: "ESTABLISHED", UID: 1000, Inode: 3001, Processes: []domain.SocketProcess{},
+		},
+		{
+			Protocol: "udp", LocalAddress: "0.0.0.0", LocalPort: 68, RemoteAddress: "0.0.0.0", RemotePort: 0,
+			State: "UNCONN", UID: 0, Inode: 4001, Processes: []domain.SocketProcess{{PID: 303, Name: "dnsmasq"}},
+		},
+		{
+			Protocol: "udp", LocalAddress: "127.0.0.1", LocalPort: 53, RemoteAddress: "0.0.0.0", RemotePort: 0,
+			State: "UNCONN", UID: 101, Inode: 4002, RxQueue: 512, Processes: []domain.SocketProcess{{PID: 303, Name: "dnsmasq"}},
+		},
+	}, sockets)
+}
+
+func TestGetSockets_Errors(t *testing.T) {
+	repo := NewSocketRepository(&MissingFileReader{}, &RealLinkReader{})
+	_, err := repo.GetSockets()
+	assert.Error(t, err)
+
+	// Without access to the fds of the processes
+	repo = NewSocketRepository(&FixtureFileReader{Root: "testdata/sockets"}, &FixtureLinkReader{Root: "testdata/missing"})
+	sockets, err := repo.GetSockets()
+	assert.NoError(t, err)
+	assert.Len(t, sockets, 8)
+	assert.Empty(t, sockets[0].Processes)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+/* ******************************************** AUX ******************************************** */
+
+// fileSource checks a file can be opened, without reading it
+func fileSource(fr FileReader, path string, required bool, provides string) domain.DataSource {
+	available := false
+	if file, err := fr.Open(path); err == nil {
+		file.Close()
+		available = true
+	}
+
+	return domain.DataSource{
+		Name:      path,
+		Kind:      "file",
+		Required:  required,
+		Available: available,
+		Provides:  provides,
+	}
+}
+
+func toolSource(ti ToolInstalled, tool string, required bool, provides string) domain.DataSource {
+	return domain.DataSource{
+		Name:      tool,
+		Kind:      "tool",
+		Required:  required,
+		Available: ti.isToolInstalled(tool),
+		Provides:  provides,
+	}
+}
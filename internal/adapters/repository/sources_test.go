@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+type MissingFileReader struct{}
+
+func (m *MissingFileReader) Open(name string) (*os.File, error) {
+	return nil, errors.New("file not found")
+}
+
+/* ******************************************** SOURCES TEST ******************************************** */
+
+func TestDataSources(t *testing.T) {
+
+	ti := &MockToolInstalled{Installed: map[string]bool{"df": true}}
+
+	// Every file present
+	repo := NewStorageRepository(&MockFileReader{}, ti, &MockCmdExecutor{})
+	for _, source := range repo.DataSources() {
+		assert.True(t, source.Available, source.Name)
+		assert.True(t, source.Required, source.Name)
+	}
+
+	// No file present, no iwconfig installed
+	netRepo := NewNetworkRepository(&MissingFileReader{}, ti, &MockCmdExecutor{})
+	sources := netRepo.DataSources()
+	assert.Len(t, sources, 2)
+	assert.Equal(t, "/proc/net/dev", sources[0].Name)
+	assert.Equal(t, "file", sources[0].Kind)
+	assert.False(t, sources[0].Available)
+	assert.Equal(t, "iwconfig", sources[1].Name)
+	assert.Equal(t, "tool", sources[1].Kind)
+	assert.False(t, sources[1].Required)
+	assert.False(t, sources[1].Available)
+
+	assert.False(t, NewCPURepository(&MissingFileReader{}).DataSources()[0].Available)
+	assert.True(t, NewRAMRepository(&MockFileReader{}).DataSources()[0].Available)
+}
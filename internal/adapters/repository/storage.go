@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -13,20 +16,43 @@ import (
 
 /* ******************************************** AUX ******************************************** */
 
+// mmcPartition matches SD cards and eMMC, leaving out the boot areas of eMMC
+var mmcPartition = regexp.MustCompile(`^(mmcblk\d+)(p\d+)?$`)
+
+// mdPartition matches md arrays and their partitions
+var mdPartition = regexp.MustCompile(`^(md\d+)(p\d+)?$`)
+
+// nvmePartition matches NVMe namespaces and their partitions, capturing the
+// controller, e.g. nvme10 out of nvme10n1p2
+var nvmePartition = regexp.MustCompile(`^(nvme\d+)(n\d+(p\d+)?)?$`)
+
+// Device-mapper devices are dm-N in /proc/partitions, but usually
+// /dev/mapper/<name> in /proc/mounts and df, until resolved by name
 func isPartition(name string) bool {
 	return strings.HasPrefix(name, "sd") ||
 		strings.HasPrefix(name, "nvme") ||
-		strings.HasPrefix(name, "hd")
+		strings.HasPrefix(name, "hd") ||
+		strings.HasPrefix(name, "dm-") ||
+		strings.HasPrefix(name, "mapper/") ||
+		mmcPartition.MatchString(name) ||
+		mdPartition.MatchString(name)
 }
 
 func getDeviceName(partitionName string) string {
 	// For NVME, namespaces are considered part of the partition name
-	if strings.HasPrefix(partitionName, "nvme") {
-		return partitionName[:5]
+	if match := nvmePartition.FindStringSubmatch(partitionName); match != nil {
+		return match[1]
 	} else if strings.HasPrefix(partitionName, "sd") {
 		return partitionName[:3]
 	} else if strings.HasPrefix(partitionName, "hd") {
 		return partitionName[:3]
+	} else if match := mmcPartition.FindStringSubmatch(partitionName); match != nil {
+		return match[1]
+	} else if match := mdPartition.FindStringSubmatch(partitionName); match != nil {
+		return match[1]
+	} else if strings.HasPrefix(partitionName, "dm-") || strings.HasPrefix(partitionName, "mapper/") {
+		// Partitions of device-mapper devices are devices of their own
+		return partitionName
 	} else {
 		fmt.Printf("Unsupported device type for partition: %s\n", partitionName)
 		return ""
@@ -86,13 +112,41 @@ func pathProximityToRoot(path string) int {
 	return len(strings.Split(path, "/"))
 }
 
-func newPathIsCloserToRoot(base string, newPath string) bool {
-	oldProximity := pathProximityToRoot(base)
-	newProximity := pathProximityToRoot(newPath)
-	if newProximity == oldProximity {
-		fmt.Println("Two paths are at the same level!")
+// unescapeMountField decodes the octal escapes of /proc/mounts, such as \040
+// for the spaces of a mount point
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, "\\") {
+		return field
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if value, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				builder.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		builder.WriteByte(field[i])
 	}
-	return pathProximityToRoot(newPath) <= pathProximityToRoot(base)
+	return builder.String()
+}
+
+// parseMountLine parses a line of /proc/mounts into its source and mount
+func parseMountLine(line string) (string, domain.Mount, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return "", domain.Mount{}, false
+	}
+
+	options := strings.Split(fields[3], ",")
+	return unescapeMountField(fields[0]), domain.Mount{
+		MountPoint: unescapeMountField(fields[1]),
+		Filesystem: fields[2],
+		Options:    options,
+		ReadOnly:   slices.Contains(options, "ro"),
+	}, true
 }
 
 /* ************************************* MOCKING SCAFFOLDING ************************************* */
@@ -113,13 +167,23 @@ func NewStorageRepository(fr FileReader, ti ToolInstalled, cmd CmdExecutor) *Sto
 	}
 }
 
+func (r *StorageRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/partitions", true, "partitions"),
+		fileSource(r.fileReader, "/proc/mounts", true, "mount points"),
+		toolSource(r.toolChecker, "df", true, "filesystem usage"),
+	}
+}
+
 func (r *StorageRepository) GetDevices() ([]domain.Device, error) {
 	partitions, err := r.readPartitions()
 	if err != nil {
 		return []domain.Device{}, err
 	}
 
-	return groupDevices(partitions), nil
+	devices := groupDevices(partitions)
+	r.readTopology(devices)
+	return devices, nil
 }
 
 func (r *StorageRepository) readPartitions() ([]domain.Partition, error) {
@@ -128,7 +192,7 @@ func (r *StorageRepository) readPartitions() ([]domain.Partition, error) {
 		return []domain.Partition{}, err
 	}
 
-	mountPoints, err := r.readMounts()
+	mounts, err := r.readMounts()
 	if err != nil {
 		return []domain.Partition{}, err
 	}
@@ -139,10 +203,16 @@ func (r *StorageRepository) readPartitions() ([]domain.Partition, error) {
 		return []domain.Partition{}, err
 	}
 
+	dmDevices := r.readDMDevices(partitions)
+	mounts = resolveMapperNames(mounts, dmDevices)
+	fsInfo = resolveMapperNames(fsInfo, dmDevices)
+
 	for i := range partitions {
 		partition := &partitions[i]
-		if mountPoint, exists := mountPoints[partition.Name]; exists {
-			partition.MountPoint = mountPoint
+		if partitionMounts, exists := mounts[partition.Name]; exists {
+			partition.Mounts = partitionMounts
+			partition.MountPoint = partitionMounts[0].MountPoint
+			partition.ReadOnly = partitionMounts[0].ReadOnly
 		}
 		if info, exists := fsInfo[partition.Name]; exists {
 			partition.Filesystem = info.Filesystem
@@ -190,44 +260,36 @@ func (r *StorageRepository) readBasicPartitions() ([]domain.Partition, error) {
 	return partitions, nil
 }
 
-func (r *StorageRepository) readMounts() (map[string]string, error) {
+// readMounts returns every mount of each partition, the shallowest first.
+// Mounts at the same depth, like bind mounts, keep the order of the file.
+func (r *StorageRepository) readMounts() (map[string][]domain.Mount, error) {
 	file, err := r.fileReader.Open("/proc/mounts")
 	if err != nil {
-		return map[string]string{}, err
+		return map[string][]domain.Mount{}, err
 	}
 	defer file.Close()
 
-	mountPoints := make(map[string]string)
+	mounts := make(map[string][]domain.Mount)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		line := scanner.Text()
-		fields := strings.Fields(line)
-		if len(fields) < 4 {
+		source, mount, valid := parseMountLine(scanner.Text())
+		if !valid {
 			continue
 		}
-		device := strings.TrimPrefix(fields[0], "/dev/")
+		device := strings.TrimPrefix(source, "/dev/")
 
 		if isPartition(device) {
-			newMountPoint := fields[1]
-			alreadyRegistered := false
-			for registeredDevice, oldMountPoint := range mountPoints {
-				if registeredDevice == device {
-					alreadyRegistered = true
-					if newPathIsCloserToRoot(oldMountPoint, newMountPoint) {
-						mountPoints[device] = newMountPoint
-						break
-					}
-				}
-			}
-
-			if !alreadyRegistered {
-				mountPoints[device] = newMountPoint
-			}
-
+			mounts[device] = append(mounts[device], mount)
 		}
 	}
 
-	return mountPoints, nil
+	for _, deviceMounts := range mounts {
+		sort.SliceStable(deviceMounts, func(i, j int) bool {
+			return pathProximityToRoot(deviceMounts[i].MountPoint) < pathProximityToRoot(deviceMounts[j].MountPoint)
+		})
+	}
+
+	return mounts, nil
 }
 
 func (r *StorageRepository) getFilesystemInfo() (map[string]domain.Partition, error) {
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+const sysBlockRoot = "/sys/block"
+
+// smartDisk matches the block devices smartctl is asked about
+var smartDisk = regexp.MustCompile(`^(sd[a-z]+|hd[a-z]+|nvme\d+n\d+)$`)
+
+// eMMC life time estimates come in steps of 10% of the life used, where
+// 0x0B means the estimated life was exceeded and 0x00 that it's unknown
+func parseMMCLifeTime(content string) *int {
+	worst := 0
+	for _, field := range strings.Fields(content) {
+		value, err := strconv.ParseUint(field, 0, 8)
+		if err == nil && int(value) > worst {
+			worst = int(value)
+		}
+	}
+	if worst == 0 {
+		return nil
+	}
+	wear := worst * 10
+	return &wear
+}
+
+// parseMMCPreEOL decodes the consumption of the reserved blocks of eMMC
+func parseMMCPreEOL(content string) string {
+	value, err := strconv.ParseUint(strings.TrimSpace(content), 0, 8)
+	if err != nil {
+		return ""
+	}
+	switch value {
+	case 1:
+		return "normal"
+	case 2:
+		return "warning" // 80% of the reserved blocks consumed
+	case 3:
+		return "urgent" // 90% of the reserved blocks consumed
+	}
+	return ""
+}
+
+// Subset of the smartctl --json output that is used
+type smartctlOutput struct {
+	Device struct {
+		Protocol string `json:"protocol"`
+	} `json:"device"`
+	ModelName    string `json:"model_name"`
+	SerialNumber string `json:"serial_number"`
+	SmartStatus  *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature *struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime *struct {
+		Hours uint64 `json:"hours"`
+	} `json:"power_on_time"`
+	ATASmartAttributes *struct {
+		Table []struct {
+			ID    int `json:"id"`
+			Value int `json:"value"`
+			Raw   struct {
+				Value uint64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeHealth *struct {
+		PercentageUsed int `json:"percentage_used"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+// ATA attributes whose normalized value is the life left of an SSD, starting
+// at 100, depending on the vendor
+var smartLifeLeftAttributes = []int{231, 233, 177, 202}
+
+const smartReallocatedSectors = 5
+
+// parseSmartctl reads the output of smartctl --json, which exits with a
+// non-zero bitmask on many conditions while still reporting what it could
+func parseSmartctl(device string, output []byte) (domain.DeviceHealth, error) {
+	var smart smartctlOutput
+	if err := json.Unmarshal(output, &smart); err != nil {
+		return domain.DeviceHealth{}, fmt.Errorf("unexpected smartctl output: %w", err)
+	}
+	if smart.Device.Protocol == "" {
+		return domain.DeviceHealth{}, errors.New("smartctl couldn't open the device")
+	}
+
+	health := domain.DeviceHealth{
+		Device: device,
+		Type:   smart.Device.Protocol,
+		Source: "smartctl",
+		Model:  smart.ModelName,
+		Serial: smart.SerialNumber,
+	}
+	if smart.SmartStatus != nil {
+		passed := smart.SmartStatus.Passed
+		health.SmartPassed = &passed
+	}
+	if smart.Temperature != nil {
+		temperature := smart.Temperature.Current
+		health.Temperature = &temperature
+	}
+	if smart.PowerOnTime != nil {
+		hours := smart.PowerOnTime.Hours
+		health.PowerOnHours = &hours
+	}
+	if smart.NVMeHealth != nil {
+		wear := smart.NVMeHealth.PercentageUsed
+		health.WearPercent = &wear
+	}
+
+	if smart.ATASmartAttributes != nil {
+		attributes := map[int]int{}
+		for _, attribute := range smart.ATASmartAttributes.Table {
+			attributes[attribute.ID] = attribute.Value
+			if attribute.ID == smartReallocatedSectors {
+				reallocated := attribute.Raw.Value
+				health.ReallocatedSectors = &reallocated
+			}
+		}
+		for _, id := range smartLifeLeftAttributes {
+			if value, exists := attributes[id]; exists {
+				wear := 100 - value
+				health.WearPercent = &wear
+				break
+			}
+		}
+	}
+
+	return health, nil
+}
+
+/* ******************************************** STORAGE HEALTH ******************************************** */
+
+type StorageHealthRepository struct {
+	fileReader  FileReader
+	toolChecker ToolInstalled
+	cmdExec     CmdExecutor
+}
+
+func NewStorageHealthRepository(fr FileReader, ti ToolInstalled, cmd CmdExecutor) *StorageHealthRepository {
+	return &StorageHealthRepository{
+		fileReader:  fr,
+		toolChecker: ti,
+		cmdExec:     cmd,
+	}
+}
+
+func (r *StorageHealthRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, sysBlockRoot, true, "SD card and eMMC health"),
+		toolSource(r.toolChecker, "smartctl", false, "SATA and NVMe health"),
+	}
+}
+
+// GetDevicesHealth returns the health of every SD card and eMMC, and of the
+// SATA and NVMe disks when smartctl is installed and can read them. Devices
+// are named as in the storage collector, so NVMe namespaces share their
+// controller.
+func (r *StorageHealthRepository) GetDevicesHealth() ([]domain.DeviceHealth, error) {
+	blocks, err := listDir(r.fileReader, sysBlockRoot)
+	if err != nil {
+		return nil, errors.New("block devices not found")
+	}
+
+	smartctl := r.toolChecker.isToolInstalled("smartctl")
+	devices := []domain.DeviceHealth{}
+	seen := map[string]bool{}
+	for _, block := range blocks {
+		var health domain.DeviceHealth
+		var err error
+		switch {
+		case mmcPartition.MatchString(block):
+			health, err = r.readMMC(block)
+		case smartctl && smartDisk.MatchString(block):
+			health, err = r.readSmart(block)
+		default:
+			continue
+		}
+		if err != nil || seen[health.Device] {
+			continue
+		}
+		seen[health.Device] = true
+		devices = append(devices, health)
+	}
+	return devices, nil
+}
+
+// readMMC reads the identification and, for eMMC, the wear estimates that
+// the kernel exposes in sysfs
+func (r *StorageHealthRepository) readMMC(block string) (domain.DeviceHealth, error) {
+	dir := path.Join(sysBlockRoot, block, "device")
+	read := func(name string) string {
+		content, _ := readFileString(r.fileReader, path.Join(dir, name))
+		return strings.TrimSpace(content)
+	}
+
+	mmcType := read("type")
+	if mmcType == "" {
+		return domain.DeviceHealth{}, fmt.Errorf("%s is not an MMC device", block)
+	}
+
+	return domain.DeviceHealth{
+		Device:         block,
+		Type:           mmcType,
+		Source:         "sysfs",
+		Model:          read("name"),
+		Serial:         read("serial"),
+		ManufacturerID: read("manfid"),
+		OEMID:          read("oemid"),
+		Date:           read("date"),
+		WearPercent:    parseMMCLifeTime(read("life_time")),
+		PreEOL:         parseMMCPreEOL(read("pre_eol_info")),
+	}, nil
+}
+
+func (r *StorageHealthRepository) readSmart(block string) (domain.DeviceHealth, error) {
+	// The output is still valid on most non-zero exit statuses
+	output, err := r.cmdExec.Command("smartctl", "--json", "-a", "/dev/"+block).Output()
+	if len(output) == 0 {
+		if err == nil {
+			err = errors.New("empty smartctl output")
+		}
+		return domain.DeviceHealth{}, err
+	}
+	return parseSmartctl(getDeviceName(block), output)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+func uint64Pointer(value uint64) *uint64 { return &value }
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseMMCLifeTime(t *testing.T) {
+	testBattery := map[string]*int{
+		"0x01 0x02": intPointer(20),
+		"0x0a 0x01": intPointer(100),
+		"0x0b 0x0b": intPointer(110),
+		"0x00 0x00": nil,
+		"":          nil,
+	}
+
+	for input, expected := range testBattery {
+		assert.Equal(t, expected, parseMMCLifeTime(input), input)
+	}
+}
+
+func TestParseMMCPreEOL(t *testing.T) {
+	testBattery := map[string]string{
+		"0x01": "normal",
+		"0x02": "warning",
+		"0x03": "urgent",
+		"0x00": "",
+		"":     "",
+	}
+
+	for input, expected := range testBattery {
+		assert.Equal(t, expected, parseMMCPreEOL(input), input)
+	}
+}
+
+func TestParseSmartctl(t *testing.T) {
+	_, err := parseSmartctl("sdb", []byte(readFixture(t, "testdata/storage_health/smartctl/sdb.json")))
+	assert.Error(t, err)
+
+	_, err = parseSmartctl("sda", []byte("smartctl 6.6 2016-05-31"))
+	assert.Error(t, err)
+}
+
+/* ******************************************** STORAGE HEALTH TEST ******************************************** */
+
+func TestGetDevicesHealth(t *testing.T) {
+
+	mmcDevices := []domain.DeviceHealth{
+		{
+			Device: "mmcblk0", Type: "MMC", Source: "sysfs", Model: "DG4016", Serial: "0x9a4f51c2",
+			ManufacturerID: "0x000045", OEMID: "0x0100", Date: "11/2020",
+			WearPercent: intPointer(20), PreEOL: "normal",
+		},
+		{
+			Device: "mmcblk1", Type: "SD", Source: "sysfs", Model: "SD32G", Serial: "0x1a2b3c4d",
+			ManufacturerID: "0x000003", OEMID: "0x5344", Date: "04/2021",
+		},
+	}
+	smartDevices := []domain.DeviceHealth{
+		{
+			Device: "nvme0", Type: "NVMe", Source: "smartctl", Model: "WD Blue SN570 1TB", Serial: "22123A800123",
+			WearPercent: intPointer(3), SmartPassed: boolPointer(true), Temperature: floatPointer(41),
+			PowerOnHours: uint64Pointer(2210),
+		},
+		{
+			Device: "sda", Type: "ATA", Source: "smartctl", Model: "Samsung SSD 870 EVO 500GB", Serial: "S6PWNX0T123456A",
+			WearPercent: intPointer(4), SmartPassed: boolPointer(true), Temperature: floatPointer(33),
+			ReallocatedSectors: uint64Pointer(2), PowerOnHours: uint64Pointer(10512),
+		},
+	}
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - SD card and eMMC without smartctl": {
+			"installed": false,
+			"expected":  mmcDevices,
+		},
+		"Case 2 - With smartctl, failing on the USB bridge of sdb": {
+			"installed": true,
+			"expected":  append(append([]domain.DeviceHealth{}, mmcDevices...), smartDevices...),
+		},
+	}
+
+	cmd := &ScriptedCmdExecutor{Outputs: map[string]string{
+		"smartctl --json -a /dev/sda":     readFixture(t, "testdata/storage_health/smartctl/sda.json"),
+		"smartctl --json -a /dev/sdb":     readFixture(t, "testdata/storage_health/smartctl/sdb.json"),
+		"smartctl --json -a /dev/nvme0n1": readFixture(t, "testdata/storage_health/smartctl/nvme0n1.json"),
+	}}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		fr := &FixtureFileReader{Root: "testdata/storage_health/cm4"}
+		ti := &MockToolInstalled{Installed: map[string]bool{"smartctl": caseData["installed"].(bool)}}
+		repo := NewStorageHealthRepository(fr, ti, cmd)
+
+		devices, err := repo.GetDevicesHealth()
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"].([]domain.DeviceHealth), devices)
+	}
+}
+
+func TestGetDevicesHealthManyNVMe(t *testing.T) {
+	fr := &FixtureFileReader{Root: "testdata/storage_health/nvme"}
+	ti := &MockToolInstalled{Installed: map[string]bool{"smartctl": true}}
+	cmd := &ScriptedCmdExecutor{Outputs: map[string]string{
+		"smartctl --json -a /dev/nvme1n1":  readFixture(t, "testdata/storage_health/smartctl/nvme0n1.json"),
+		"smartctl --json -a /dev/nvme10n1": readFixture(t, "testdata/storage_health/smartctl/nvme0n1.json"),
+	}}
+	repo := NewStorageHealthRepository(fr, ti, cmd)
+
+	// nvme10 isn't taken for nvme1 and left out as a duplicate
+	devices, err := repo.GetDevicesHealth()
+	assert.NoError(t, err)
+	assert.Len(t, devices, 2)
+	names := []string{}
+	for _, device := range devices {
+		names = append(names, device.Device)
+	}
+	assert.ElementsMatch(t, []string{"nvme1", "nvme10"}, names)
+}
+
+func TestGetDevicesHealthMissingSysfs(t *testing.T) {
+	repo := NewStorageHealthRepository(&FixtureFileReader{Root: "testdata/storage_health/none"},
+		&MockToolInstalled{Installed: map[string]bool{}}, &MockCmdExecutor{})
+
+	_, err := repo.GetDevicesHealth()
+	assert.Error(t, err)
+}
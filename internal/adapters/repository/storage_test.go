@@ -21,6 +21,13 @@ func TestIsPartition(t *testing.T) {
 	assert.True(t, isPartition("sdc1"))
 	assert.True(t, isPartition("nvme0n1"))
 	assert.True(t, isPartition("nvme0n1p1"))
+	assert.True(t, isPartition("mmcblk0"))
+	assert.True(t, isPartition("mmcblk0p2"))
+	assert.False(t, isPartition("mmcblk0boot0"))
+	assert.True(t, isPartition("md0"))
+	assert.True(t, isPartition("md127p1"))
+	assert.True(t, isPartition("dm-0"))
+	assert.True(t, isPartition("mapper/vg0-root"))
 	assert.False(t, isPartition("some random string"))
 	assert.False(t, isPartition("lvm1"))
 }
@@ -30,6 +37,13 @@ func TestGetDeviceName(t *testing.T) {
 	assert.Equal(t, "sdb", getDeviceName("sdb2"))
 	assert.Equal(t, "sdc", getDeviceName("sdc3"))
 	assert.Equal(t, "nvme0", getDeviceName("nvme0n1p1"))
+	assert.Equal(t, "nvme1", getDeviceName("nvme1n1"))
+	assert.Equal(t, "nvme10", getDeviceName("nvme10n1"))
+	assert.Equal(t, "nvme10", getDeviceName("nvme10n1p2"))
+	assert.Equal(t, "mmcblk0", getDeviceName("mmcblk0p2"))
+	assert.Equal(t, "mmcblk1", getDeviceName("mmcblk1"))
+	assert.Equal(t, "md0", getDeviceName("md0p1"))
+	assert.Equal(t, "dm-1", getDeviceName("dm-1"))
 	assert.Equal(t, "", getDeviceName("mmc"))
 }
 
@@ -250,11 +264,11 @@ func TestReadMounts(t *testing.T) {
 	testBattery := map[string]map[string]any{
 		"Case 1 - Empty file": {
 			"input":    []byte(""),
-			"expected": map[string]string{},
+			"expected": map[string][]domain.Mount{},
 		},
 		"Case 2 - Incorrect file": {
 			"input":    []byte("some incorrect data"),
-			"expected": map[string]string{},
+			"expected": map[string][]domain.Mount{},
 		},
 		"Case 3 - Correct file": {
 			"input": []byte(`none /mnt/wsl tmpfs rw,relatime 0 0
@@ -297,8 +311,22 @@ none /mnt/wslg/doc overlay rw,relatime,lowerdir=/systemvhd,upperdir=/system/rw/u
 none /mnt/wslg/.X11-unix tmpfs ro,relatime 0 0
 C:\134 /mnt/c 9p rw,dirsync,noatime,aname=drvfs;path=C:\;uid=1000;gid=1000;symlinkroot=/mnt/,mmap,access=client,msize=65536,trans=fd,rfd=4,wfd=4 0 0
 /dev/sdc /var/lib/docker ext4 rw,relatime,discard,errors=remount-ro,data=ordered 0 0`),
-			"expected": map[string]string{
-				"sdc": "/",
+			"expected": map[string][]domain.Mount{
+				"sdc": {
+					{MountPoint: "/", Filesystem: "ext4", Options: []string{"rw", "relatime", "discard", "errors=remount-ro", "data=ordered"}},
+					{MountPoint: "/mnt/wslg/distro", Filesystem: "ext4", Options: []string{"ro", "relatime", "discard", "errors=remount-ro", "data=ordered"}, ReadOnly: true},
+					{MountPoint: "/var/lib/docker", Filesystem: "ext4", Options: []string{"rw", "relatime", "discard", "errors=remount-ro", "data=ordered"}},
+				},
+			},
+		},
+		"Case 4 - SD card remounted read-only": {
+			"input": []byte(`/dev/mmcblk0p2 / ext4 ro,noatime 0 0
+/dev/mmcblk0p1 /boot/firmware vfat rw,relatime,fmask=0022,dmask=0022,codepage=437,iocharset=ascii,shortname=mixed,errors=remount-ro 0 0
+/dev/sda1 /media/pi/My\040Passport ext4 rw,nosuid,nodev,relatime 0 0`),
+			"expected": map[string][]domain.Mount{
+				"mmcblk0p2": {{MountPoint: "/", Filesystem: "ext4", Options: []string{"ro", "noatime"}, ReadOnly: true}},
+				"mmcblk0p1": {{MountPoint: "/boot/firmware", Filesystem: "vfat", Options: []string{"rw", "relatime", "fmask=0022", "dmask=0022", "codepage=437", "iocharset=ascii", "shortname=mixed", "errors=remount-ro"}}},
+				"sda1":      {{MountPoint: "/media/pi/My Passport", Filesystem: "ext4", Options: []string{"rw", "nosuid", "nodev", "relatime"}}},
 			},
 		},
 	}
@@ -320,21 +348,7 @@ C:\134 /mnt/c 9p rw,dirsync,noatime,aname=drvfs;path=C:\;uid=1000;gid=1000;symli
 
 		// Validation
 		assert.NoError(t, err)
-		assert.Len(t, resultMounts, len(caseData["expected"].(map[string]string)))
-
-		for expectedDevice, expectedMount := range caseData["expected"].(map[string]string) {
-
-			found := false
-			for resultDevice, resultMount := range resultMounts {
-				if resultDevice == expectedDevice {
-					found = true
-					assert.Equal(t, expectedMount, resultMount)
-					break
-				}
-			}
-
-			assert.True(t, found, "Device %s not found in the list", expectedDevice)
-		}
+		assert.Equal(t, caseData["expected"].(map[string][]domain.Mount), resultMounts)
 
 	}
 
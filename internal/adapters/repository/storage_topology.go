@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// sysClassBlock lists every block device and partition, which /sys/block
+// nests under their disks
+const sysClassBlock = "/sys/class/block"
+
+// mdstatMember matches members like sda1[0], sdc1[2](F) or sdg1[3](S)
+var mdstatMember = regexp.MustCompile(`^(\S+)\[(\d+)\]((?:\([A-Z]\))*)$`)
+
+// mdstatCounts matches the [2/1] and [U_] of the status line
+var mdstatCounts = regexp.MustCompile(`\[(\d+)/(\d+)\]\s+\[([U_]+)\]`)
+
+// mdstatSync matches lines like "[==>....]  recovery = 12.6% (1/2)
+// finish=81.2min speed=175000K/sec", or "resync=DELAYED"
+var mdstatSync = regexp.MustCompile(`(resync|recovery|check|repair|reshape)\s*=\s*(?:([\d.]+)%)?`)
+
+// parseMdstat parses /proc/mdstat into the status of every array
+func parseMdstat(content string) map[string]domain.RAIDStatus {
+	arrays := map[string]domain.RAIDStatus{}
+
+	var name string
+	var array domain.RAIDStatus
+	flush := func() {
+		if name != "" {
+			// An array that failed to assemble is worse than a degraded one
+			array.Degraded = array.Degraded || array.State != "active" ||
+				slices.ContainsFunc(array.Members, func(member domain.RAIDMember) bool { return member.Faulty })
+			arrays[name] = array
+		}
+		name = ""
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// md0 : active raid1 sdb1[1] sda1[0]
+		if len(fields) >= 3 && fields[1] == ":" && strings.HasPrefix(fields[0], "md") {
+			flush()
+			name = fields[0]
+			array = domain.RAIDStatus{State: fields[2], Members: []domain.RAIDMember{}}
+			for _, field := range fields[3:] {
+				match := mdstatMember.FindStringSubmatch(field)
+				if match == nil {
+					// Personality, or flags like (auto-read-only)
+					if array.Level == "" && !strings.HasPrefix(field, "(") {
+						array.Level = field
+					}
+					continue
+				}
+				role, _ := strconv.Atoi(match[2])
+				array.Members = append(array.Members, domain.RAIDMember{
+					Name:   match[1],
+					Role:   role,
+					Faulty: strings.Contains(match[3], "(F)"),
+					Spare:  strings.Contains(match[3], "(S)"),
+				})
+			}
+			continue
+		}
+		if name == "" {
+			continue
+		}
+
+		if match := mdstatCounts.FindStringSubmatch(line); match != nil {
+			array.Devices, _ = strconv.Atoi(match[1])
+			array.ActiveDevices, _ = strconv.Atoi(match[2])
+			array.Degraded = array.ActiveDevices < array.Devices || strings.Contains(match[3], "_")
+			continue
+		}
+
+		if match := mdstatSync.FindStringSubmatch(line); match != nil {
+			array.SyncAction = match[1]
+			if progress, err := strconv.ParseFloat(match[2], 64); err == nil {
+				array.SyncProgress = &progress
+			}
+			for _, field := range fields {
+				if value, found := strings.CutPrefix(field, "finish="); found {
+					array.SyncFinish = value
+				}
+				if value, found := strings.CutPrefix(field, "speed="); found {
+					array.SyncSpeed = value
+				}
+			}
+		}
+	}
+	flush()
+
+	return arrays
+}
+
+// resolveMapperNames renames the mapper/<name> keys after their dm-N device
+func resolveMapperNames[V any](values map[string]V, dmDevices map[string]string) map[string]V {
+	resolved := make(map[string]V, len(values))
+	for key, value := range values {
+		if mapperName, found := strings.CutPrefix(key, "mapper/"); found {
+			device, exists := dmDevices[mapperName]
+			if !exists {
+				continue
+			}
+			key = device
+		}
+		resolved[key] = value
+	}
+	return resolved
+}
+
+/* ******************************************** STORAGE TOPOLOGY ******************************************** */
+
+// readDMDevices maps the device-mapper names, like vg0-root, to their dm-N
+// devices
+func (r *StorageRepository) readDMDevices(partitions []domain.Partition) map[string]string {
+	dmDevices := map[string]string{}
+	for _, partition := range partitions {
+		if !strings.HasPrefix(partition.Name, "dm-") {
+			continue
+		}
+		if name, err := readFileString(r.fileReader, path.Join(sysClassBlock, partition.Name, "dm/name")); err == nil {
+			dmDevices[name] = partition.Name
+		}
+	}
+	return dmDevices
+}
+
+// readTopology tells how devices are stacked. Devices without device-mapper
+// or md support simply have nothing stacked.
+func (r *StorageRepository) readTopology(devices []domain.Device) {
+	arrays := map[string]domain.RAIDStatus{}
+	if content, err := readFileString(r.fileReader, "/proc/mdstat"); err == nil {
+		arrays = parseMdstat(content)
+	}
+
+	for i := range devices {
+		device := &devices[i]
+		dir := path.Join(sysClassBlock, device.Name)
+
+		device.DMName, _ = readFileString(r.fileReader, path.Join(dir, "dm/name"))
+
+		device.Slaves, _ = listDir(r.fileReader, path.Join(dir, "slaves"))
+		if device.Slaves == nil {
+			device.Slaves = []string{}
+		}
+
+		// The holders of the disk and of each of its partitions
+		device.Holders = []string{}
+		seen := map[string]bool{}
+		names := []string{device.Name}
+		for name := range device.Partitions {
+			names = append(names, name)
+		}
+		for _, name := range names {
+			holders, _ := listDir(r.fileReader, path.Join(sysClassBlock, name, "holders"))
+			for _, holder := range holders {
+				if !seen[holder] {
+					seen[holder] = true
+					device.Holders = append(device.Holders, holder)
+				}
+			}
+		}
+		sort.Strings(device.Holders)
+
+		if array, exists := arrays[device.Name]; exists {
+			device.RAID = &array
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseMdstat(t *testing.T) {
+	arrays := parseMdstat(readFixture(t, "testdata/storage/mdstat"))
+
+	assert.Equal(t, map[string]domain.RAIDStatus{
+		"md0": {
+			Level: "raid1", State: "active", Devices: 2, ActiveDevices: 2,
+			Members: []domain.RAIDMember{{Name: "sdb1", Role: 1}, {Name: "sda1", Role: 0}},
+		},
+		"md1": {
+			Level: "raid1", State: "active", Devices: 2, ActiveDevices: 1, Degraded: true,
+			Members: []domain.RAIDMember{{Name: "sdd1", Role: 2, Faulty: true}, {Name: "sdc1", Role: 0}},
+		},
+		"md2": {
+			Level: "raid5", State: "active", Devices: 3, ActiveDevices: 3,
+			Members: []domain.RAIDMember{
+				{Name: "sdh1", Role: 3}, {Name: "sdg1", Role: 1}, {Name: "sdf1", Role: 0}, {Name: "sde1", Role: 4, Spare: true},
+			},
+			SyncAction: "check", SyncProgress: floatPointer(36.4), SyncFinish: "52.1min", SyncSpeed: "198564K/sec",
+		},
+		"md127": {
+			Level: "raid1", State: "active", Devices: 2, ActiveDevices: 2,
+			Members:    []domain.RAIDMember{{Name: "sdj1", Role: 1}, {Name: "sdi1", Role: 0}},
+			SyncAction: "resync",
+		},
+		"md3": {
+			// Failed to assemble
+			State: "inactive", Degraded: true,
+			Members: []domain.RAIDMember{{Name: "sdk1", Role: 0, Spare: true}},
+		},
+	}, arrays)
+
+	assert.Empty(t, parseMdstat(""))
+}
+
+func TestResolveMapperNames(t *testing.T) {
+	resolved := resolveMapperNames(map[string]string{
+		"sda1":            "/",
+		"mapper/vg0-root": "/srv",
+		"mapper/unknown":  "/mnt",
+	}, map[string]string{"vg0-root": "dm-0"})
+
+	assert.Equal(t, map[string]string{"sda1": "/", "dm-0": "/srv"}, resolved)
+}
+
+/* ******************************************** STORAGE TOPOLOGY TEST ******************************************** */
+
+func TestGetDevicesTopology(t *testing.T) {
+	fr := &FixtureFileReader{Root: "testdata/storage/nas"}
+	ti := &MockToolInstalled{Installed: map[string]bool{"df": true}}
+	cmd := &ScriptedCmdExecutor{Outputs: map[string]string{
+		"df -l --block-size=1": readFixture(t, "testdata/storage/nas/df.txt"),
+	}}
+	repo := NewStorageRepository(fr, ti, cmd)
+
+	devices, err := repo.GetDevices()
+	assert.NoError(t, err)
+
+	byName := map[string]domain.Device{}
+	for _, device := range devices {
+		byName[device.Name] = device
+	}
+	assert.Len(t, byName, 6)
+
+	// The disks of the array
+	assert.Equal(t, []string{"md0"}, byName["sda"].Holders)
+	assert.Equal(t, []string{"md0"}, byName["sdb"].Holders)
+	assert.Empty(t, byName["sda"].Slaves)
+	assert.Nil(t, byName["sda"].RAID)
+	assert.Empty(t, byName["mmcblk0"].Holders)
+
+	// The degraded array, recovering
+	md0 := byName["md0"]
+	assert.Equal(t, []string{"sda1", "sdb1"}, md0.Slaves)
+	assert.Equal(t, []string{"dm-0", "dm-1"}, md0.Holders)
+	assert.Equal(t, &domain.RAIDStatus{
+		Level: "raid1", State: "active", Devices: 2, ActiveDevices: 1, Degraded: true,
+		Members:    []domain.RAIDMember{{Name: "sdb1", Role: 2}, {Name: "sda1", Role: 0}},
+		SyncAction: "recovery", SyncProgress: floatPointer(12.6), SyncFinish: "81.2min", SyncSpeed: "175000K/sec",
+	}, md0.RAID)
+
+	// The LVM volumes on top, mounted and measured through their mapper name
+	media := byName["dm-0"]
+	assert.Equal(t, "nas-media", media.DMName)
+	assert.Equal(t, []string{"md0"}, media.Slaves)
+	assert.Equal(t, "/srv/media", media.Partitions["dm-0"].MountPoint)
+	assert.Equal(t, uint64(105555197952), media.Partitions["dm-0"].Total)
+
+	backup := byName["dm-1"]
+	assert.Equal(t, "nas-backup", backup.DMName)
+	assert.Equal(t, "/srv/backup", backup.Partitions["dm-1"].MountPoint)
+	assert.True(t, backup.Partitions["dm-1"].ReadOnly)
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"errors"
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+func parseUptime(content string) (float64, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 1 {
+		return 0, errors.New("unexpected file format")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+func parseBootTime(content string) (time.Time, error) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "btime" {
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(seconds, 0).UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("btime not found")
+}
+
+func parseOSRelease(content string) domain.OSRelease {
+	fields := parseKeyValues(content)
+	return domain.OSRelease{
+		ID:              fields["ID"],
+		Name:            fields["NAME"],
+		PrettyName:      fields["PRETTY_NAME"],
+		Version:         fields["VERSION"],
+		VersionID:       fields["VERSION_ID"],
+		VersionCodename: fields["VERSION_CODENAME"],
+		Fields:          fields,
+	}
+}
+
+// goArchToMachine translates GOARCH into uname -m terms, for when uname is
+// missing. It is the architecture of the binary, which usually matches.
+func goArchToMachine(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "x86_64"
+	case "386":
+		return "i686"
+	case "arm64":
+		return "aarch64"
+	case "arm":
+		return "armv7l"
+	default:
+		return goarch
+	}
+}
+
+/* ******************************************** SYSTEM ******************************************** */
+
+type SystemRepository struct {
+	fileReader  FileReader
+	toolChecker ToolInstalled
+	cmdExec     CmdExecutor
+}
+
+func NewSystemRepository(fr FileReader, ti ToolInstalled, cmd CmdExecutor) *SystemRepository {
+	return &SystemRepository{
+		fileReader:  fr,
+		toolChecker: ti,
+		cmdExec:     cmd,
+	}
+}
+
+func (r *SystemRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		fileSource(r.fileReader, "/proc/uptime", true, "uptime"),
+		fileSource(r.fileReader, "/proc/stat", false, "boot time"),
+		fileSource(r.fileReader, "/proc/sys/kernel/osrelease", false, "kernel version"),
+		fileSource(r.fileReader, "/etc/os-release", false, "OS release"),
+		fileSource(r.fileReader, "/etc/machine-id", false, "machine id"),
+		toolSource(r.toolChecker, "uname", false, "machine architecture"),
+	}
+}
+
+// GetSystemInfo only fails when the uptime can't be read, the rest of the
+// fields are left empty when their source is missing
+func (r *SystemRepository) GetSystemInfo() (domain.System, error) {
+	uptimeContent, err := readFileString(r.fileReader, "/proc/uptime")
+	if err != nil {
+		return domain.System{}, err
+	}
+	uptime, err := parseUptime(uptimeContent)
+	if err != nil {
+		return domain.System{}, err
+	}
+
+	system := domain.System{
+		Hostname:      r.readOptional("/proc/sys/kernel/hostname"),
+		Architecture:  r.getArchitecture(),
+		UptimeSeconds: uptime,
+		Kernel: domain.Kernel{
+			Name:    r.readOptional("/proc/sys/kernel/ostype"),
+			Release: r.readOptional("/proc/sys/kernel/osrelease"),
+			Version: r.readOptional("/proc/sys/kernel/version"),
+		},
+	}
+
+	if stat, err := readFileString(r.fileReader, "/proc/stat"); err == nil {
+		if bootTime, err := parseBootTime(stat); err == nil {
+			system.BootTime = bootTime
+		} else {
+			log.Printf("Unable to parse boot time: %v", err)
+		}
+	}
+
+	// os-release may live in either place, /etc has precedence
+	for _, path := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		if content, err := readFileString(r.fileReader, path); err == nil {
+			system.OS = parseOSRelease(content)
+			break
+		}
+	}
+
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if machineID := r.readOptional(path); machineID != "" {
+			system.MachineID = machineID
+			break
+		}
+	}
+
+	return system, nil
+}
+
+func (r *SystemRepository) readOptional(path string) string {
+	content, err := readFileString(r.fileReader, path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(content)
+}
+
+func (r *SystemRepository) getArchitecture() string {
+	if r.toolChecker.isToolInstalled("uname") {
+		output, err := r.cmdExec.Command("uname", "-m").Output()
+		if err == nil && strings.TrimSpace(string(output)) != "" {
+			return strings.TrimSpace(string(output))
+		}
+	}
+	return goArchToMachine(runtime.GOARCH)
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+// FixtureFileReader reads from a fixture tree under testdata, mirroring the
+// paths of the real filesystem, which allows listing directories
+type FixtureFileReader struct {
+	Root string
+}
+
+func (f *FixtureFileReader) Open(name string) (*os.File, error) {
+	return os.Open(filepath.Join(f.Root, name))
+}
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseKeyValues(t *testing.T) {
+	values := parseKeyValues(`# comment
+NAME="Debian GNU/Linux"
+ID=debian
+QUOTED='single'
+BROKEN
+EMPTY=
+`)
+	assert.Equal(t, map[string]string{
+		"NAME":   "Debian GNU/Linux",
+		"ID":     "debian",
+		"QUOTED": "single",
+		"EMPTY":  "",
+	}, values)
+}
+
+func TestParseUptime(t *testing.T) {
+	uptime, err := parseUptime("350735.47 1385216.15")
+	assert.NoError(t, err)
+	assert.Equal(t, 350735.47, uptime)
+
+	_, err = parseUptime("")
+	assert.Error(t, err)
+}
+
+func TestGoArchToMachine(t *testing.T) {
+	testBattery := map[string]string{
+		"amd64":   "x86_64",
+		"arm64":   "aarch64",
+		"arm":     "armv7l",
+		"riscv64": "riscv64",
+	}
+
+	for input, expected := range testBattery {
+		assert.Equal(t, expected, goArchToMachine(input))
+	}
+}
+
+/* ******************************************** SYSTEM TEST ******************************************** */
+
+func TestGetSystemInfo(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Raspberry Pi OS": {
+			"root":  "testdata/system/raspbian",
+			"uname": "aarch64",
+			"expected": domain.System{
+				Hostname: "raspberrypi",
+				Kernel: domain.Kernel{
+					Name:    "Linux",
+					Release: "6.6.31+rpt-rpi-v8",
+					Version: "#1 SMP PREEMPT Debian 1:6.6.31-1+rpt1 (2024-05-29)",
+				},
+				OS: domain.OSRelease{
+					ID:              "debian",
+					Name:            "Debian GNU/Linux",
+					PrettyName:      "Debian GNU/Linux 12 (bookworm)",
+					Version:         "12 (bookworm)",
+					VersionID:       "12",
+					VersionCodename: "bookworm",
+				},
+				Architecture:  "aarch64",
+				UptimeSeconds: 350735.47,
+				BootTime:      time.Unix(1718000000, 0).UTC(),
+				MachineID:     "4c4c4544003957108052b4c04f384b32",
+			},
+		},
+		"Case 2 - Minimal container": {
+			"root":  "testdata/system/minimal",
+			"uname": "armv7l",
+			"expected": domain.System{
+				OS: domain.OSRelease{
+					ID:         "alpine",
+					Name:       "Alpine Linux",
+					PrettyName: "Alpine Linux v3.20",
+					VersionID:  "3.20.2",
+				},
+				Architecture:  "armv7l",
+				UptimeSeconds: 12.5,
+				MachineID:     "abcdef0123456789abcdef0123456789",
+			},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		fr := &FixtureFileReader{Root: caseData["root"].(string)}
+		ti := &MockToolInstalled{Installed: map[string]bool{"uname": true}}
+		cmd := &MockCmdExecutor{output: caseData["uname"].(string) + "\n"}
+
+		repo := NewSystemRepository(fr, ti, cmd)
+
+		system, err := repo.GetSystemInfo()
+		assert.NoError(t, err)
+
+		expected := caseData["expected"].(domain.System)
+		assert.NotEmpty(t, system.OS.Fields)
+		system.OS.Fields = nil
+		assert.Equal(t, expected, system)
+	}
+
+}
+
+func TestGetSystemInfo_MissingUptime(t *testing.T) {
+	repo := NewSystemRepository(&FixtureFileReader{Root: "testdata/missing"}, &MockToolInstalled{}, &MockCmdExecutor{})
+
+	_, err := repo.GetSystemInfo()
+	assert.Error(t, err)
+}
+
+func TestGetSystemInfo_NoUname(t *testing.T) {
+	repo := NewSystemRepository(&FixtureFileReader{Root: "testdata/system/minimal"}, &MockToolInstalled{}, &MockCmdExecutor{})
+
+	system, err := repo.GetSystemInfo()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, system.Architecture)
+}
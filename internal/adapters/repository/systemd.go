@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// systemdProperties are the properties asked to systemctl show
+var systemdProperties = []string{
+	"Id", "Description", "LoadState", "ActiveState", "SubState",
+	"UnitFileState", "MainPID", "NRestarts", "ActiveEnterTimestamp",
+}
+
+// parseSystemdTimestamp parses timestamps printed with --timestamp=unix, like
+// "@1718000040". Anything else gives the zero time: empty or "n/a", but also
+// the local time printed by default, whose zone abbreviation can't be told
+// apart reliably.
+func parseSystemdTimestamp(value string) time.Time {
+	seconds, found := strings.CutPrefix(value, "@")
+	if !found {
+		return time.Time{}
+	}
+	unix, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0).UTC()
+}
+
+// parseSystemctlShow parses the output of systemctl show for several units,
+// where each unit is a block of Key=Value lines separated by a blank line
+func parseSystemctlShow(output string) []domain.SystemdUnit {
+	units := []domain.SystemdUnit{}
+	for _, block := range strings.Split(strings.TrimSpace(output), "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+
+		// Values aren't quoted, so they are taken as they are
+		properties := map[string]string{}
+		for _, line := range strings.Split(block, "\n") {
+			key, value, found := strings.Cut(line, "=")
+			if found {
+				properties[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+
+		mainPID, _ := strconv.Atoi(properties["MainPID"])
+		restarts, _ := strconv.Atoi(properties["NRestarts"])
+		units = append(units, domain.SystemdUnit{
+			Name:          properties["Id"],
+			Description:   properties["Description"],
+			LoadState:     properties["LoadState"],
+			ActiveState:   properties["ActiveState"],
+			SubState:      properties["SubState"],
+			UnitFileState: properties["UnitFileState"],
+			MainPID:       mainPID,
+			Restarts:      restarts,
+			ActiveSince:   parseSystemdTimestamp(properties["ActiveEnterTimestamp"]),
+		})
+	}
+	return units
+}
+
+// systemctlListedUnit is an entry of systemctl list-units --output=json
+type systemctlListedUnit struct {
+	Unit        string `json:"unit"`
+	Load        string `json:"load"`
+	Active      string `json:"active"`
+	Sub         string `json:"sub"`
+	Description string `json:"description"`
+}
+
+func parseSystemctlList(output string) ([]domain.SystemdUnit, error) {
+	var listed []systemctlListedUnit
+	if err := json.Unmarshal([]byte(output), &listed); err != nil {
+		return nil, fmt.Errorf("unexpected systemctl output: %w", err)
+	}
+
+	units := make([]domain.SystemdUnit, 0, len(listed))
+	for _, unit := range listed {
+		units = append(units, domain.SystemdUnit{
+			Name:        unit.Unit,
+			Description: unit.Description,
+			LoadState:   unit.Load,
+			ActiveState: unit.Active,
+			SubState:    unit.Sub,
+		})
+	}
+	return units, nil
+}
+
+/* ******************************************** SYSTEMD ******************************************** */
+
+type SystemdRepository struct {
+	toolChecker ToolInstalled
+	cmdExec     CmdExecutor
+}
+
+func NewSystemdRepository(ti ToolInstalled, cmd CmdExecutor) *SystemdRepository {
+	return &SystemdRepository{
+		toolChecker: ti,
+		cmdExec:     cmd,
+	}
+}
+
+func (r *SystemdRepository) DataSources() []domain.DataSource {
+	return []domain.DataSource{
+		toolSource(r.toolChecker, "systemctl", false, "systemd units"),
+	}
+}
+
+// GetUnits returns the state of the units in the same order, even of the
+// ones that don't exist, whose LoadState is not-found. The systemctl of
+// systemd before 247 has no --timestamp, they are then left without
+// ActiveSince.
+func (r *SystemdRepository) GetUnits(names []string) ([]domain.SystemdUnit, error) {
+	if len(names) == 0 {
+		return []domain.SystemdUnit{}, nil
+	}
+	if !r.toolChecker.isToolInstalled("systemctl") {
+		return nil, errors.New("systemctl not found")
+	}
+
+	args := append([]string{"show", "--timestamp=unix", "--property=" + strings.Join(systemdProperties, ",")}, names...)
+	output, err := r.cmdExec.Command("systemctl", args...).Output()
+	if err != nil {
+		args = append(args[:1], args[2:]...)
+		output, err = r.cmdExec.Command("systemctl", args...).Output()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	units := parseSystemctlShow(string(output))
+	if len(units) != len(names) {
+		return nil, fmt.Errorf("systemctl show returned %d units, expected %d", len(units), len(names))
+	}
+	return units, nil
+}
+
+// GetFailedUnits returns the units in the failed state, none on hosts without
+// systemd, like most containers
+func (r *SystemdRepository) GetFailedUnits() ([]domain.SystemdUnit, error) {
+	if !r.toolChecker.isToolInstalled("systemctl") {
+		return []domain.SystemdUnit{}, nil
+	}
+
+	output, err := r.cmdExec.Command("systemctl", "list-units", "--failed", "--all", "--output=json").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseSystemctlList(string(output))
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/* ******************************************** MOCKING ******************************************** */
+
+// ScriptedCmdExecutor replies to each command with its own output, keyed by
+// the first arguments of the command line. Unknown commands fail.
+type ScriptedCmdExecutor struct {
+	Outputs map[string]string
+	line    string
+}
+
+func (c *ScriptedCmdExecutor) Command(name string, arg ...string) CmdExecutor {
+	return &ScriptedCmdExecutor{Outputs: c.Outputs, line: strings.Join(append([]string{name}, arg...), " ")}
+}
+
+func (c *ScriptedCmdExecutor) Output() ([]byte, error) {
+	for prefix, output := range c.Outputs {
+		if strings.HasPrefix(c.line, prefix) {
+			return []byte(output), nil
+		}
+	}
+	return nil, errors.New("exit status 1")
+}
+
+func readFixture(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+/* ******************************************** AUX TEST ******************************************** */
+
+func TestParseSystemdTimestamp(t *testing.T) {
+	testBattery := map[string]time.Time{
+		"@1718000040": time.Unix(1718000040, 0).UTC(),
+		// Without --timestamp=unix, the zone can't be trusted
+		"Mon 2024-06-10 08:14:00 CEST": {},
+		"":                             {},
+		"n/a":                          {},
+		"@soon":                        {},
+	}
+
+	for input, expected := range testBattery {
+		assert.Equal(t, expected, parseSystemdTimestamp(input), input)
+	}
+}
+
+/* ******************************************** SYSTEMD TEST ******************************************** */
+
+func TestGetUnits(t *testing.T) {
+
+	cmd := &ScriptedCmdExecutor{Outputs: map[string]string{
+		"systemctl show --timestamp=unix --property=Id,Description,LoadState,ActiveState,SubState,UnitFileState,MainPID,NRestarts,ActiveEnterTimestamp ssh myapp nope": readFixture(t, "testdata/systemd/show.txt"),
+	}}
+	ti := &MockToolInstalled{Installed: map[string]bool{"systemctl": true}}
+	repo := NewSystemdRepository(ti, cmd)
+
+	units, err := repo.GetUnits([]string{"ssh", "myapp", "nope"})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.SystemdUnit{
+		{
+			Name: "ssh.service", Description: "OpenBSD Secure Shell server",
+			LoadState: "loaded", ActiveState: "active", SubState: "running", UnitFileState: "enabled",
+			MainPID: 512, ActiveSince: time.Date(2024, 6, 10, 6, 14, 0, 0, time.UTC),
+		},
+		{
+			Name: "myapp.service", Description: "My application",
+			LoadState: "loaded", ActiveState: "activating", SubState: "auto-restart", UnitFileState: "enabled",
+			Restarts: 7, ActiveSince: time.Date(2024, 6, 10, 6, 17, 20, 0, time.UTC),
+		},
+		{
+			Name: "nope.service", Description: "nope.service",
+			LoadState: "not-found", ActiveState: "inactive", SubState: "dead",
+		},
+	}, units)
+
+	// Before systemd 247, without --timestamp
+	cmd = &ScriptedCmdExecutor{Outputs: map[string]string{
+		"systemctl show --property=": readFixture(t, "testdata/systemd/show_legacy.txt"),
+	}}
+	units, err = NewSystemdRepository(ti, cmd).GetUnits([]string{"ssh", "myapp", "nope"})
+	assert.NoError(t, err)
+	assert.Len(t, units, 3)
+	assert.Equal(t, 7, units[1].Restarts)
+	assert.True(t, units[0].ActiveSince.IsZero())
+
+	// Nothing is run without units
+	units, err = NewSystemdRepository(ti, &ScriptedCmdExecutor{}).GetUnits(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, units)
+}
+
+func TestGetFailedUnits(t *testing.T) {
+
+	cmd := &ScriptedCmdExecutor{Outputs: map[string]string{
+		"systemctl list-units --failed": readFixture(t, "testdata/systemd/failed.json"),
+	}}
+	ti := &MockToolInstalled{Installed: map[string]bool{"systemctl": true}}
+	repo := NewSystemdRepository(ti, cmd)
+
+	units, err := repo.GetFailedUnits()
+	assert.NoError(t, err)
+	assert.Len(t, units, 2)
+	assert.Equal(t, domain.SystemdUnit{
+		Name: "hciuart.service", Description: "Configure Bluetooth Modems connected by UART",
+		LoadState: "loaded", ActiveState: "failed", SubState: "failed",
+	}, units[1])
+
+	// No failed units
+	repo = NewSystemdRepository(ti, &ScriptedCmdExecutor{Outputs: map[string]string{"systemctl": "[]"}})
+	units, err = repo.GetFailedUnits()
+	assert.NoError(t, err)
+	assert.Empty(t, units)
+
+	// Nor without systemd
+	repo = NewSystemdRepository(&MockToolInstalled{Installed: map[string]bool{}}, &ScriptedCmdExecutor{})
+	units, err = repo.GetFailedUnits()
+	assert.NoError(t, err)
+	assert.Empty(t, units)
+	assert.False(t, repo.DataSources()[0].Required)
+}
+
+func TestGetUnits_Errors(t *testing.T) {
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - systemctl not installed": {
+			"installed": false,
+			"outputs":   map[string]string{"systemctl": "[]"},
+		},
+		"Case 2 - systemctl fails": {
+			"installed": true,
+			"outputs":   map[string]string{},
+		},
+		"Case 3 - Unexpected output": {
+			"installed": true,
+			"outputs":   map[string]string{"systemctl": "some incorrect output"},
+		},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		ti := &MockToolInstalled{Installed: map[string]bool{"systemctl": caseData["installed"].(bool)}}
+		repo := NewSystemdRepository(ti, &ScriptedCmdExecutor{Outputs: caseData["outputs"].(map[string]string)})
+
+		_, err := repo.GetUnits([]string{"ssh", "myapp"})
+		assert.Error(t, err)
+		if caseData["installed"].(bool) {
+			_, err = repo.GetFailedUnits()
+			assert.Error(t, err)
+		}
+	}
+}
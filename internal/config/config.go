@@ -0,0 +1,470 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"time"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+// Duration wraps time.Duration so it can be written as "10s" in the file
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string like \"10s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+/* ******************************************** CONFIG ******************************************** */
+
+// Config holds every setting of the API. All of them are optional, the zero
+// file "{}" behaves exactly as running without configuration.
+type Config struct {
+	Listen        string            `json:"listen"`
+	Stream        StreamConfig      `json:"stream"`
+	Influx        *InfluxConfig     `json:"influx,omitempty"`
+	Watch         []WatchConfig     `json:"watch,omitempty"`
+	WatchInterval Duration          `json:"watch_interval"` // How often watched processes are checked
+	Services      ServicesConfig    `json:"services"`
+	Docker        DockerConfig      `json:"docker"`
+	Cgroups       CgroupsConfig     `json:"cgroups"`
+	Storage       StorageConfig     `json:"storage"`
+	Mounts        MountsConfig      `json:"mounts"`
+	Directories   DirectoriesConfig `json:"directories"`
+	Neighbors     NeighborsConfig   `json:"neighbors"`
+	Probes        ProbesConfig      `json:"probes"`
+}
+
+// StreamConfig bounds how often live streams can push samples
+type StreamConfig struct {
+	MinInterval     Duration `json:"min_interval"`
+	DefaultInterval Duration `json:"default_interval"`
+}
+
+// InfluxConfig describes where and how to push samples in line protocol
+type InfluxConfig struct {
+	Enabled  bool     `json:"enabled"`
+	Interval Duration `json:"interval"`
+
+	// URL is http(s)://host:port for the HTTP APIs or udp://host:port
+	URL        string   `json:"url"`
+	APIVersion int      `json:"api_version"`
+	Timeout    Duration `json:"timeout"`
+
+	// InfluxDB v1
+	Database        string `json:"database"`
+	RetentionPolicy string `json:"retention_policy"`
+	Username        string `json:"username"`
+	Password        string `json:"password"`
+
+	// InfluxDB v2
+	Org    string `json:"org"`
+	Bucket string `json:"bucket"`
+	Token  string `json:"token"`
+
+	BatchSize     int      `json:"batch_size"`
+	FlushInterval Duration `json:"flush_interval"`
+	Gzip          bool     `json:"gzip"`
+
+	// Lines that couldn't be delivered are kept here, up to BufferMaxBytes
+	BufferPath     string `json:"buffer_path"`
+	BufferMaxBytes int64  `json:"buffer_max_bytes"`
+}
+
+// ServicesConfig lists the systemd units always reported, besides the failed
+// ones. Names without suffix are taken as .service units.
+type ServicesConfig struct {
+	Units []string `json:"units"`
+}
+
+// DockerConfig enables the containers collector, which talks to the Docker
+// Engine API over its Unix socket
+type DockerConfig struct {
+	Enabled bool     `json:"enabled"`
+	Socket  string   `json:"socket"`
+	Timeout Duration `json:"timeout"`
+}
+
+// CgroupsConfig lists the cgroups reported, relative to /sys/fs/cgroup.
+// Paths ending in /* stand for every child cgroup.
+type CgroupsConfig struct {
+	Paths []string `json:"paths"`
+}
+
+// StorageConfig lists the mount points expected to be read-write, whose
+// partitions are flagged when mounted read-only
+type StorageConfig struct {
+	ExpectedRW []string       `json:"expected_rw"`
+	Forecast   ForecastConfig `json:"forecast"`
+}
+
+// ForecastConfig sets how often the usage of partitions is sampled, and over
+// how long their fill trend is fitted
+type ForecastConfig struct {
+	Interval Duration `json:"interval"`
+	Window   Duration `json:"window"`
+}
+
+// MountsConfig selects the entries of the mount table reported, by
+// filesystem type and mount point. Empty include lists select everything.
+// Paths are globs that also select what's below them.
+type MountsConfig struct {
+	IncludeTypes []string `json:"include_types"`
+	ExcludeTypes []string `json:"exclude_types"`
+	IncludePaths []string `json:"include_paths"`
+	ExcludePaths []string `json:"exclude_paths"`
+	// How long to wait for the capacity of each filesystem
+	Timeout Duration `json:"timeout"`
+}
+
+// DirectoriesConfig lists the directories whose size is watched. They are
+// walked in the background every interval, at most Rate entries per second,
+// or without limit when set to 0, and MaxEntries entries per directory.
+type DirectoriesConfig struct {
+	Paths      []DirectoryConfig `json:"paths"`
+	Interval   Duration          `json:"interval"`
+	Top        int               `json:"top"` // Largest files reported
+	Rate       int               `json:"rate"`
+	MaxEntries int               `json:"max_entries"`
+}
+
+// DirectoryConfig is a watched directory, flagged over the soft limit when
+// larger, unless 0
+type DirectoryConfig struct {
+	Path           string `json:"path"`
+	SoftLimitBytes uint64 `json:"soft_limit_bytes"`
+}
+
+// NeighborsConfig points to an IEEE OUI file, such as the oui.txt of the
+// ieee-data package, used instead of the embedded one to name MAC vendors
+type NeighborsConfig struct {
+	OUIFile string `json:"oui_file"`
+}
+
+// ProbesConfig lists the connectivity checks run on a schedule. Loss and
+// latency are computed over the latest Window attempts of each.
+type ProbesConfig struct {
+	Targets []ProbeConfig `json:"targets"`
+	Window  int           `json:"window"`
+}
+
+// ProbeConfig is a connectivity check. Target is a host:port for tcp, a URL
+// for http, the name resolved for dns and a host name or address for icmp.
+type ProbeConfig struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Target         string   `json:"target"`
+	Server         string   `json:"server"`          // DNS server as host:port, of dns probes
+	ExpectedStatus int      `json:"expected_status"` // Of http probes, 200 by default
+	Interval       Duration `json:"interval"`
+	Timeout        Duration `json:"timeout"`
+}
+
+// WatchConfig describes a process whose liveness is checked. Only one way of
+// matching it is used, in order: Pidfile, Process and Cmdline.
+type WatchConfig struct {
+	Name    string `json:"name"`
+	Process string `json:"process"` // Exact process name
+	Cmdline string `json:"cmdline"` // Regular expression on the command line
+	Pidfile string `json:"pidfile"`
+}
+
+// Default returns the configuration used when no file is provided
+func Default() Config {
+	return Config{
+		Listen:        ":8080",
+		WatchInterval: Duration(10 * time.Second),
+		Stream: StreamConfig{
+			MinInterval:     Duration(time.Second),
+			DefaultInterval: Duration(5 * time.Second),
+		},
+		Docker: DockerConfig{
+			Socket:  "/var/run/docker.sock",
+			Timeout: Duration(5 * time.Second),
+		},
+		Cgroups: CgroupsConfig{
+			Paths: []string{"system.slice/*", "user.slice"},
+		},
+		Storage: StorageConfig{
+			ExpectedRW: []string{"/"},
+			Forecast: ForecastConfig{
+				Interval: Duration(10 * time.Minute),
+				Window:   Duration(7 * 24 * time.Hour),
+			},
+		},
+		Mounts: MountsConfig{
+			// Pseudo filesystems of the kernel, without capacity
+			ExcludeTypes: []string{
+				"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs",
+				"devpts", "efivarfs", "fusectl", "hugetlbfs", "mqueue", "nsfs", "proc",
+				"pstore", "rpc_pipefs", "securityfs", "selinuxfs", "sysfs", "tracefs",
+			},
+			Timeout: Duration(2 * time.Second),
+		},
+		Directories: DirectoriesConfig{
+			Interval:   Duration(15 * time.Minute),
+			Top:        10,
+			Rate:       1000,
+			MaxEntries: 100000,
+		},
+		Probes: ProbesConfig{
+			Window: 20,
+		},
+	}
+}
+
+// Load reads the JSON file at path on top of the defaults
+func Load(path string) (Config, error) {
+	cfg := Default()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if err := cfg.applyDefaults(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) applyDefaults() error {
+	defaults := Default()
+	if c.Listen == "" {
+		c.Listen = defaults.Listen
+	}
+	if c.WatchInterval <= 0 {
+		c.WatchInterval = defaults.WatchInterval
+	}
+	if c.Stream.MinInterval <= 0 {
+		c.Stream.MinInterval = defaults.Stream.MinInterval
+	}
+	if c.Stream.DefaultInterval < c.Stream.MinInterval {
+		c.Stream.DefaultInterval = max(defaults.Stream.DefaultInterval, c.Stream.MinInterval)
+	}
+
+	if len(c.Cgroups.Paths) == 0 {
+		c.Cgroups.Paths = defaults.Cgroups.Paths
+	}
+	if c.Docker.Socket == "" {
+		c.Docker.Socket = defaults.Docker.Socket
+	}
+	if c.Docker.Timeout <= 0 {
+		c.Docker.Timeout = defaults.Docker.Timeout
+	}
+	if c.Storage.Forecast.Interval <= 0 {
+		c.Storage.Forecast.Interval = defaults.Storage.Forecast.Interval
+	}
+	if c.Storage.Forecast.Window <= 0 {
+		c.Storage.Forecast.Window = defaults.Storage.Forecast.Window
+	}
+	// A trend needs a few samples
+	if c.Storage.Forecast.Window < 3*c.Storage.Forecast.Interval {
+		return errors.New("storage: forecast window must span at least 3 intervals")
+	}
+	if c.Mounts.Timeout <= 0 {
+		c.Mounts.Timeout = defaults.Mounts.Timeout
+	}
+	if err := c.Mounts.validate(); err != nil {
+		return err
+	}
+	if err := c.Directories.applyDefaults(); err != nil {
+		return err
+	}
+	if err := c.Probes.applyDefaults(); err != nil {
+		return err
+	}
+
+	if c.Influx != nil {
+		if err := c.Influx.applyDefaults(); err != nil {
+			return err
+		}
+	}
+
+	names := map[string]bool{}
+	for _, watch := range c.Watch {
+		if err := watch.validate(); err != nil {
+			return err
+		}
+		if names[watch.Name] {
+			return fmt.Errorf("watch: duplicated name %q", watch.Name)
+		}
+		names[watch.Name] = true
+	}
+	return nil
+}
+
+func (c *InfluxConfig) applyDefaults() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.URL == "" {
+		return errors.New("influx: url is required")
+	}
+	if c.APIVersion == 0 {
+		c.APIVersion = 2
+	}
+	if c.APIVersion != 1 && c.APIVersion != 2 {
+		return fmt.Errorf("influx: unsupported api_version %d", c.APIVersion)
+	}
+	if c.Interval <= 0 {
+		c.Interval = Duration(10 * time.Second)
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = Duration(5 * time.Second)
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 5000
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = c.Interval
+	}
+	if c.BufferPath != "" && c.BufferMaxBytes <= 0 {
+		c.BufferMaxBytes = 10 * 1024 * 1024
+	}
+	return nil
+}
+
+func (c *MountsConfig) validate() error {
+	for _, pattern := range append(slices.Clone(c.IncludePaths), c.ExcludePaths...) {
+		if _, err := path.Match(pattern, "/"); err != nil {
+			return fmt.Errorf("mounts: invalid path %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (c *DirectoriesConfig) applyDefaults() error {
+	defaults := Default().Directories
+	if c.Interval <= 0 {
+		c.Interval = defaults.Interval
+	}
+	if c.Top <= 0 {
+		c.Top = defaults.Top
+	}
+	// Omitted, the rate keeps its default, so an explicit 0 lifts the limit
+	if c.Rate < 0 {
+		return fmt.Errorf("directories: rate %d must be 0, for no limit, or positive", c.Rate)
+	}
+	if c.MaxEntries <= 0 {
+		c.MaxEntries = defaults.MaxEntries
+	}
+
+	paths := map[string]bool{}
+	for _, directory := range c.Paths {
+		if !path.IsAbs(directory.Path) {
+			return fmt.Errorf("directories: path %q must be absolute", directory.Path)
+		}
+		if paths[path.Clean(directory.Path)] {
+			return fmt.Errorf("directories: duplicated path %q", directory.Path)
+		}
+		paths[path.Clean(directory.Path)] = true
+	}
+	return nil
+}
+
+func (c *ProbesConfig) applyDefaults() error {
+	if c.Window <= 0 {
+		c.Window = Default().Probes.Window
+	}
+
+	names := map[string]bool{}
+	for i := range c.Targets {
+		probe := &c.Targets[i]
+		if probe.Interval <= 0 {
+			probe.Interval = Duration(time.Minute)
+		}
+		if probe.Timeout <= 0 {
+			probe.Timeout = min(Duration(5*time.Second), probe.Interval)
+		}
+		if probe.Type == "http" && probe.ExpectedStatus == 0 {
+			probe.ExpectedStatus = 200
+		}
+		if err := probe.validate(); err != nil {
+			return err
+		}
+		if names[probe.Name] {
+			return fmt.Errorf("probes: duplicated name %q", probe.Name)
+		}
+		names[probe.Name] = true
+	}
+	return nil
+}
+
+func (c *ProbeConfig) validate() error {
+	if c.Name == "" {
+		return errors.New("probes: name is required")
+	}
+	if c.Target == "" {
+		return fmt.Errorf("probe %s: target is required", c.Name)
+	}
+	if c.Timeout > c.Interval {
+		return fmt.Errorf("probe %s: timeout must not exceed the interval", c.Name)
+	}
+
+	switch c.Type {
+	case "tcp":
+		if _, _, err := net.SplitHostPort(c.Target); err != nil {
+			return fmt.Errorf("probe %s: target must be host:port: %w", c.Name, err)
+		}
+	case "http":
+		parsed, err := url.Parse(c.Target)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("probe %s: target must be an http(s) URL", c.Name)
+		}
+		if c.ExpectedStatus < 100 || c.ExpectedStatus > 599 {
+			return fmt.Errorf("probe %s: invalid expected_status %d", c.Name, c.ExpectedStatus)
+		}
+	case "dns":
+		if c.Server != "" {
+			if _, _, err := net.SplitHostPort(c.Server); err != nil {
+				return fmt.Errorf("probe %s: server must be host:port: %w", c.Name, err)
+			}
+		}
+	case "icmp":
+	default:
+		return fmt.Errorf("probe %s: type must be tcp, http, dns or icmp", c.Name)
+	}
+	return nil
+}
+
+func (c *WatchConfig) validate() error {
+	if c.Name == "" {
+		return errors.New("watch: name is required")
+	}
+	if c.Pidfile == "" && c.Process == "" && c.Cmdline == "" {
+		return fmt.Errorf("watch %s: one of pidfile, process or cmdline is required", c.Name)
+	}
+	if c.Cmdline != "" {
+		if _, err := regexp.Compile(c.Cmdline); err != nil {
+			return fmt.Errorf("watch %s: invalid cmdline: %w", c.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_NoFile(t *testing.T) {
+	cfg, err := Load("")
+	assert.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_InfluxDefaults(t *testing.T) {
+	path := writeConfig(t, `{
+		"influx": {"enabled": true, "url": "http://influx:8086", "interval": "30s", "buffer_path": "/tmp/buf"}
+	}`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.Listen)
+	assert.Equal(t, 2, cfg.Influx.APIVersion)
+	assert.Equal(t, Duration(30*time.Second), cfg.Influx.Interval)
+	assert.Equal(t, Duration(30*time.Second), cfg.Influx.FlushInterval)
+	assert.Equal(t, 5000, cfg.Influx.BatchSize)
+	assert.Equal(t, int64(10*1024*1024), cfg.Influx.BufferMaxBytes)
+}
+
+func TestLoad_DockerDefaults(t *testing.T) {
+	path := writeConfig(t, `{"docker": {"enabled": true}}`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.True(t, cfg.Docker.Enabled)
+	assert.Equal(t, "/var/run/docker.sock", cfg.Docker.Socket)
+	assert.Equal(t, Duration(5*time.Second), cfg.Docker.Timeout)
+}
+
+func TestLoad_Storage(t *testing.T) {
+	path := writeConfig(t, `{"storage": {"expected_rw": ["/", "/var/log"]}}`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/", "/var/log"}, cfg.Storage.ExpectedRW)
+
+	// An empty list disables the check
+	path = writeConfig(t, `{"storage": {"expected_rw": []}}`)
+
+	cfg, err = Load(path)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.Storage.ExpectedRW)
+	assert.Equal(t, Duration(10*time.Minute), cfg.Storage.Forecast.Interval)
+	assert.Equal(t, Duration(7*24*time.Hour), cfg.Storage.Forecast.Window)
+
+	path = writeConfig(t, `{"storage": {"forecast": {"interval": "1h", "window": "720h"}}}`)
+
+	cfg, err = Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Duration(time.Hour), cfg.Storage.Forecast.Interval)
+	assert.Equal(t, Duration(30*24*time.Hour), cfg.Storage.Forecast.Window)
+}
+
+func TestLoad_Mounts(t *testing.T) {
+	path := writeConfig(t, `{"mounts": {"include_types": ["ext4", "nfs4"], "exclude_paths": ["/var/lib/docker"]}}`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ext4", "nfs4"}, cfg.Mounts.IncludeTypes)
+	assert.Equal(t, []string{"/var/lib/docker"}, cfg.Mounts.ExcludePaths)
+	assert.Contains(t, cfg.Mounts.ExcludeTypes, "proc")
+	assert.Equal(t, Duration(2*time.Second), cfg.Mounts.Timeout)
+}
+
+func TestLoad_Directories(t *testing.T) {
+	path := writeConfig(t, `{
+		"directories": {
+			"paths": [
+				{"path": "/var/log", "soft_limit_bytes": 524288000},
+				{"path": "/home/pi/recordings"}
+			],
+			"top": 5
+		}
+	}`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []DirectoryConfig{
+		{Path: "/var/log", SoftLimitBytes: 500 << 20},
+		{Path: "/home/pi/recordings"},
+	}, cfg.Directories.Paths)
+	assert.Equal(t, 5, cfg.Directories.Top)
+	assert.Equal(t, Duration(15*time.Minute), cfg.Directories.Interval)
+	assert.Equal(t, 1000, cfg.Directories.Rate)
+	assert.Equal(t, 100000, cfg.Directories.MaxEntries)
+
+	// An explicit 0 lifts the rate limit
+	cfg, err = Load(writeConfig(t, `{"directories": {"rate": 0}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cfg.Directories.Rate)
+}
+
+func TestLoad_Probes(t *testing.T) {
+	path := writeConfig(t, `{
+		"probes": {
+			"targets": [
+				{"name": "gateway", "type": "icmp", "target": "192.168.1.1", "interval": "30s", "timeout": "1s"},
+				{"name": "backend", "type": "http", "target": "https://backend.example.com/health"},
+				{"name": "dns", "type": "dns", "target": "example.com", "server": "1.1.1.1:53", "interval": "2s"}
+			]
+		}
+	}`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, cfg.Probes.Window)
+	assert.Equal(t, []ProbeConfig{
+		{Name: "gateway", Type: "icmp", Target: "192.168.1.1", Interval: Duration(30 * time.Second), Timeout: Duration(time.Second)},
+		{Name: "backend", Type: "http", Target: "https://backend.example.com/health", ExpectedStatus: 200,
+			Interval: Duration(time.Minute), Timeout: Duration(5 * time.Second)},
+		// The timeout is bounded by the interval
+		{Name: "dns", Type: "dns", Target: "example.com", Server: "1.1.1.1:53", Interval: Duration(2 * time.Second), Timeout: Duration(2 * time.Second)},
+	}, cfg.Probes.Targets)
+}
+
+func TestLoad_Watch(t *testing.T) {
+	path := writeConfig(t, `{
+		"watch": [
+			{"name": "ssh", "process": "sshd"},
+			{"name": "app", "cmdline": "python3 .*app\\.py"},
+			{"name": "nginx", "pidfile": "/run/nginx.pid"}
+		]
+	}`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []WatchConfig{
+		{Name: "ssh", Process: "sshd"},
+		{Name: "app", Cmdline: `python3 .*app\.py`},
+		{Name: "nginx", Pidfile: "/run/nginx.pid"},
+	}, cfg.Watch)
+	assert.Equal(t, Duration(10*time.Second), cfg.WatchInterval)
+
+	path = writeConfig(t, `{"watch_interval": "2s"}`)
+
+	cfg, err = Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Duration(2*time.Second), cfg.WatchInterval)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	testBattery := map[string]string{
+		"Case 1 - Not JSON":         `listen: 8080`,
+		"Case 2 - Bad duration":     `{"influx": {"enabled": true, "url": "http://x", "interval": "soon"}}`,
+		"Case 3 - Missing url":      `{"influx": {"enabled": true}}`,
+		"Case 4 - Unknown version":  `{"influx": {"enabled": true, "url": "http://x", "api_version": 3}}`,
+		"Case 5 - Watch no name":    `{"watch": [{"process": "sshd"}]}`,
+		"Case 6 - Watch no match":   `{"watch": [{"name": "sshd"}]}`,
+		"Case 7 - Watch bad regex":  `{"watch": [{"name": "app", "cmdline": "app.py("}]}`,
+		"Case 8 - Watch duplicated": `{"watch": [{"name": "app", "process": "a"}, {"name": "app", "process": "b"}]}`,
+		"Case 9 - Mounts bad path":  `{"mounts": {"exclude_paths": ["/mnt/[a"]}}`,
+		"Case 10 - Forecast window": `{"storage": {"forecast": {"interval": "1h", "window": "2h"}}}`,
+		"Case 11 - Relative dir":    `{"directories": {"paths": [{"path": "var/log"}]}}`,
+		"Case 12 - Duplicated dir":  `{"directories": {"paths": [{"path": "/var/log"}, {"path": "/var/log/"}]}}`,
+		"Case 13 - Probe no name":   `{"probes": {"targets": [{"type": "icmp", "target": "1.1.1.1"}]}}`,
+		"Case 14 - Probe type":      `{"probes": {"targets": [{"name": "dns", "type": "udp", "target": "1.1.1.1:53"}]}}`,
+		"Case 15 - Probe no port":   `{"probes": {"targets": [{"name": "ssh", "type": "tcp", "target": "10.0.0.5"}]}}`,
+		"Case 16 - Probe bad URL":   `{"probes": {"targets": [{"name": "web", "type": "http", "target": "backend/health"}]}}`,
+		"Case 17 - Probe status":    `{"probes": {"targets": [{"name": "web", "type": "http", "target": "http://x", "expected_status": 42}]}}`,
+		"Case 18 - Probe timeout":   `{"probes": {"targets": [{"name": "ssh", "type": "tcp", "target": "x:22", "interval": "1s", "timeout": "2s"}]}}`,
+		"Case 19 - Probe duplicate": `{"probes": {"targets": [{"name": "a", "type": "icmp", "target": "x"}, {"name": "a", "type": "icmp", "target": "y"}]}}`,
+		"Case 20 - Directory rate":  `{"directories": {"rate": -1}}`,
+	}
+
+	for caseName, content := range testBattery {
+		t.Log(caseName)
+		_, err := Load(writeConfig(t, content))
+		assert.Error(t, err)
+	}
+
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package domain
+
+// BoardRevision is the decoded Raspberry Pi revision code of /proc/cpuinfo
+type BoardRevision struct {
+	Code           string
+	NewStyle       bool
+	Model          string
+	Revision       string
+	MemoryMB       uint64
+	Manufacturer   string
+	Processor      string
+	WarrantyVoided bool
+}
+
+// CPUInfo is the generic processor description of /proc/cpuinfo
+type CPUInfo struct {
+	Model    string
+	Hardware string
+	Cores    int
+	Features []string
+}
+
+type Board struct {
+	IsRaspberryPi bool
+	Model         string
+	SerialNumber  string
+	Revision      *BoardRevision // Only for Raspberry Pi boards
+	CPU           CPUInfo
+}
@@ -0,0 +1,41 @@
+package domain
+
+type CgroupCPU struct {
+	UsageUsec     uint64
+	UserUsec      uint64
+	SystemUsec    uint64
+	Periods       uint64 // Only with a cpu.max limit
+	Throttled     uint64
+	ThrottledUsec uint64
+}
+
+type CgroupMemory struct {
+	Current  uint64  // Bytes
+	Max      *uint64 // Null when unlimited
+	OOM      uint64  // Times the limit was hit and reclaim failed
+	OOMKills uint64
+}
+
+type CgroupIO struct {
+	Device         string // Name of the block device, or major:minor if unknown
+	ReadBytes      uint64
+	WriteBytes     uint64
+	ReadIOs        uint64
+	WriteIOs       uint64
+	DiscardedBytes uint64
+}
+
+type CgroupPids struct {
+	Current uint64
+	Max     *uint64 // Null when unlimited
+}
+
+// Cgroup is the resource accounting of a cgroup v2, from the files of its
+// controllers. Those of disabled controllers are left empty.
+type Cgroup struct {
+	Path   string // Relative to /sys/fs/cgroup
+	CPU    CgroupCPU
+	Memory CgroupMemory
+	IO     []CgroupIO
+	Pids   CgroupPids
+}
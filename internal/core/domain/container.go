@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type Container struct {
+	ID           string
+	Name         string
+	Image        string
+	State        string // running, exited, restarting...
+	Status       string // As shown by docker ps
+	Health       string // Empty without healthcheck
+	RestartCount int
+	Created      time.Time
+	StartedAt    time.Time
+
+	// Usage, only for running containers. CPUPercent of 100 is one whole core.
+	CPUPercent    float64
+	MemoryUsage   uint64 // Bytes, without the page cache
+	MemoryLimit   uint64
+	MemoryPercent float64
+	NetworkRx     uint64
+	NetworkTx     uint64
+	BlockRead     uint64
+	BlockWrite    uint64
+}
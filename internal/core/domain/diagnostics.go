@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// DataSource is a file or tool a collector reads its data from. Optional
+// sources only enable extra capabilities, described by Provides.
+type DataSource struct {
+	Name      string
+	Kind      string // "file" or "tool"
+	Required  bool
+	Available bool
+	Provides  string
+}
+
+// CollectorStats summarizes the runs of a collector since the API started
+type CollectorStats struct {
+	Runs         uint64
+	Failures     uint64
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    string     // Kept until another error replaces it
+	LastErrorAt  *time.Time // Start of the run which failed with LastError, null without any
+}
+
+type CollectorDiagnostics struct {
+	Name         string
+	Ready        bool
+	Sources      []DataSource
+	Capabilities []string
+	Stats        CollectorStats
+}
+
+type Readiness struct {
+	Ready      bool
+	Collectors map[string]bool
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// DirectoryRule is a directory whose size is watched, with an optional soft
+// limit in bytes
+type DirectoryRule struct {
+	Path      string
+	SoftLimit uint64 // 0 for none
+}
+
+// DirectoryFile is one of the largest files of a watched directory
+type DirectoryFile struct {
+	Path string
+	Size uint64
+}
+
+// DirectoryScan is the outcome of walking a directory, without crossing into
+// other filesystems or following symbolic links
+type DirectoryScan struct {
+	Size        uint64 // Apparent size of the files, in bytes
+	Files       int
+	Directories int
+	Largest     []DirectoryFile // The largest first
+	Unreadable  int             // Entries that couldn't be read, usually for lack of permissions
+	Truncated   bool            // The walk stopped at the entries limit, the totals fall short
+	Duration    float64         // Seconds
+}
+
+// DirectoryUsage is the latest scan of a watched directory
+type DirectoryUsage struct {
+	Path      string
+	SoftLimit uint64
+	OverLimit bool
+	Scan      *DirectoryScan // Null until the first walk completes
+	ScannedAt *time.Time
+	Error     string // Why the latest walk failed, like a missing directory
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "errors"
+
+// ErrStatfsTimeout is returned when a filesystem doesn't answer statfs in
+// time, like a network share whose server is unreachable
+var ErrStatfsTimeout = errors.New("statfs timed out")
+
+// FilesystemUsage is the capacity of a mounted filesystem, in bytes
+type FilesystemUsage struct {
+	Total      uint64
+	Used       uint64
+	Free       uint64 // Including the blocks reserved to root
+	Available  uint64 // To unprivileged users
+	Inodes     uint64
+	InodesFree uint64
+}
+
+// MountedFilesystem is an entry of the mount table, whatever its source:
+// block devices, tmpfs, overlay, network shares...
+type MountedFilesystem struct {
+	Source     string
+	MountPoint string
+	Filesystem string
+	Options    []string
+	ReadOnly   bool
+	Usage      *FilesystemUsage // Null when it couldn't be read
+	Error      string
+	TimedOut   bool // statfs didn't answer in time, unlike failing at once
+}
+
+// MountFilter selects the entries of the mount table. Empty include lists
+// select everything. Paths are globs that also select what's below them.
+type MountFilter struct {
+	IncludeTypes []string
+	ExcludeTypes []string
+	IncludePaths []string
+	ExcludePaths []string
+}
@@ -13,3 +13,134 @@ type NetworkInterface struct {
 	Rx            NetworkStats
 	Tx            NetworkStats
 }
+
+// TCPStats are the TCP counters of the kernel since boot, but for the
+// connections currently established
+type TCPStats struct {
+	ActiveOpens        uint64 // Connections initiated
+	PassiveOpens       uint64 // Connections accepted
+	AttemptFails       uint64
+	EstablishedResets  uint64
+	CurrentEstablished uint64
+	InSegments         uint64
+	OutSegments        uint64
+	RetransSegments    uint64
+	InErrors           uint64
+	OutResets          uint64
+
+	// From /proc/net/netstat, null when missing
+	ListenOverflows *uint64 // Connections dropped because the accept queue was full
+	ListenDrops     *uint64
+	Timeouts        *uint64 // Retransmission timeouts
+}
+
+// UDPStats are the UDP counters of the kernel since boot
+type UDPStats struct {
+	InDatagrams         uint64
+	OutDatagrams        uint64
+	NoPorts             uint64 // Received for a port nobody listens on
+	InErrors            uint64
+	ReceiveBufferErrors uint64
+	SendBufferErrors    uint64
+	ChecksumErrors      uint64
+}
+
+// SocketStats are the sockets currently in use, from /proc/net/sockstat
+type SocketStats struct {
+	Used        uint64 // Of every family
+	TCPInUse    uint64
+	TCPOrphan   uint64 // No longer attached to a process
+	TCPTimeWait uint64
+	TCPAlloc    uint64
+	TCPMemory   uint64 // Bytes
+	UDPInUse    uint64
+	UDPMemory   uint64 // Bytes
+	RawInUse    uint64
+
+	// From /proc/net/sockstat6, null without IPv6
+	TCP6InUse *uint64
+	UDP6InUse *uint64
+	Raw6InUse *uint64
+}
+
+type NetworkProtocols struct {
+	TCP     TCPStats
+	UDP     UDPStats
+	Sockets SocketStats
+}
+
+// SocketStates are the states a socket can be reported in, as named in the
+// kernel's include/net/tcp_states.h, plus UNCONN for UDP sockets not
+// connected to a peer, the ones listening.
+var SocketStates = []string{
+	"ESTABLISHED", "SYN_SENT", "SYN_RECV", "FIN_WAIT1", "FIN_WAIT2", "TIME_WAIT",
+	"CLOSE", "CLOSE_WAIT", "LAST_ACK", "LISTEN", "CLOSING", "NEW_SYN_RECV", "UNCONN",
+}
+
+// SocketProcess is a process holding a socket open
+type SocketProcess struct {
+	PID  int
+	Name string
+}
+
+// Socket is an entry of /proc/net/tcp, tcp6, udp or udp6
+type Socket struct {
+	Protocol      string // tcp, tcp6, udp or udp6
+	LocalAddress  string
+	LocalPort     int
+	RemoteAddress string
+	RemotePort    int
+	State         string // One of the SocketStates
+	UID           int
+	Inode         uint64
+	TxQueue       uint64 // Bytes
+	RxQueue       uint64 // Bytes
+	// Empty when no process holds it, as in TIME_WAIT, or when the API
+	// can't see the processes of other users
+	Processes []SocketProcess
+}
+
+// SocketFilter selects sockets by state and port. Empty fields select
+// everything.
+type SocketFilter struct {
+	States []string
+	Port   int // Local or remote
+}
+
+// Route is an entry of the routing table of the kernel
+type Route struct {
+	Family      string // inet or inet6
+	Destination string // CIDR, 0.0.0.0/0 or ::/0 for default routes
+	Gateway     string // Empty when the destination is directly connected
+	Interface   string
+	Metric      uint32
+	Default     bool
+}
+
+// Resolver is the DNS configuration of /etc/resolv.conf
+type Resolver struct {
+	Nameservers []string
+	Search      []string // Domains appended to short names
+	Options     []string
+}
+
+type Routing struct {
+	Routes []Route
+	// The default routes in use, the ones of lowest metric. Null without any.
+	DefaultGateway  *Route
+	DefaultGateway6 *Route
+	Resolver        Resolver
+}
+
+// Neighbor is an entry of the ARP table, for IPv4, or of the IPv6 neighbor
+// table
+type Neighbor struct {
+	Family    string // inet or inet6
+	Address   string
+	MAC       string // Empty until resolved
+	Interface string
+	// INCOMPLETE, COMPLETE or PERMANENT for IPv4, the state reported by ip
+	// neigh for IPv6: REACHABLE, STALE, DELAY, FAILED...
+	State  string
+	Vendor string // Owner of the MAC address prefix, when known
+}
@@ -0,0 +1,41 @@
+package domain
+
+// PowerSupply is a device of /sys/class/power_supply, like a battery or UPS
+// HAT. Measures the driver doesn't provide are null.
+type PowerSupply struct {
+	Name     string
+	Type     string // Battery, Mains, USB, UPS...
+	Status   string // Charging, Discharging, Full...
+	Health   string
+	Present  *bool
+	Online   *bool
+	Capacity *int     // Percent
+	Voltage  *float64 // Volts
+	Current  *float64 // Amperes
+	Power    *float64 // Watts
+}
+
+// ThrottledStatus is the decoded get_throttled bitmask of the Raspberry Pi
+// firmware
+type ThrottledStatus struct {
+	Raw    string // Hexadecimal, as reported by vcgencmd
+	Source string
+
+	UnderVoltage  bool
+	FreqCapped    bool
+	Throttled     bool
+	SoftTempLimit bool
+
+	// Since boot
+	UnderVoltageOccurred  bool
+	FreqCappedOccurred    bool
+	ThrottledOccurred     bool
+	SoftTempLimitOccurred bool
+
+	Flags []string // Names of the bits set
+}
+
+type Power struct {
+	Supplies  []PowerSupply
+	Throttled *ThrottledStatus // Only on Raspberry Pi
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Types of probe
+const (
+	ProbeTCP  = "tcp"
+	ProbeHTTP = "http"
+	ProbeDNS  = "dns"
+	ProbeICMP = "icmp"
+)
+
+// ErrProbeNotPermitted is returned by the probes the host doesn't allow to
+// run, like ICMP ones when the group of the API isn't granted ping sockets
+var ErrProbeNotPermitted = errors.New("probe not permitted")
+
+// ProbeTarget is a connectivity check run on a schedule
+type ProbeTarget struct {
+	Name string
+	Type string // tcp, http, dns or icmp
+	// host:port for tcp, URL for http and host name, or address for icmp
+	Target         string
+	Server         string // DNS server as host:port, the system resolver when empty
+	ExpectedStatus int    // Of http probes
+	Interval       time.Duration
+	Timeout        time.Duration
+}
+
+// ProbeAttempt is the outcome of a successful run of a probe
+type ProbeAttempt struct {
+	Latency time.Duration
+	Detail  string // Like the HTTP status or the resolved addresses
+}
+
+// Probe is the latest state of a probe, over its recent attempts
+type Probe struct {
+	Name     string
+	Type     string
+	Target   string
+	Interval float64 // Seconds
+	Up       bool    // The latest attempt succeeded
+	Attempts int     // Within the window
+	Failures int
+	Loss     float64 // Percentage of failed attempts within the window
+	// Seconds, of the latest successful attempt and on average within the
+	// window. Null without any.
+	Latency        *float64
+	AverageLatency *float64
+	Detail         string
+	LastAttempt    *time.Time
+	LastSuccess    *time.Time
+	Error          string // Why the latest attempt failed
+}
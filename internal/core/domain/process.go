@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type Process struct {
+	PID        int
+	PPID       int
+	Name       string
+	User       string
+	UID        int
+	State      string // Single letter, as in ps
+	StateName  string
+	RSS        uint64 // Bytes
+	Threads    int
+	CPUPercent float64 // 100 is one whole core
+	StartTime  time.Time
+	Command    string // Empty for kernel threads
+}
+
+// ProcessSortKeys are the orders processes can be listed in, heaviest first
+var ProcessSortKeys = []string{"cpu", "memory", "threads", "pid", "name"}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Sample is a single measurement point, independent of the output format.
+// Fields values are expected to be float64, int64, uint64, bool or string.
+type Sample struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]any
+	Timestamp   time.Time
+}
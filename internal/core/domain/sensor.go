@@ -0,0 +1,13 @@
+package domain
+
+// Sensor is a reading of a hwmon chip, like the temperature of the SoC, the
+// speed of a fan or the voltage of a PMIC rail
+type Sensor struct {
+	Chip   string // Name of the driver, e.g. cpu_thermal or pwmfan
+	Device string // hwmon0, hwmon1...
+	Name   string // temp1, fan1, in0...
+	Label  string // When the driver names the reading
+	Type   string // temperature, fan, voltage, current or power
+	Value  float64
+	Unit   string // °C, RPM, V, A or W
+}
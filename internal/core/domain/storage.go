@@ -1,15 +1,100 @@
 package domain
 
+import "time"
+
+// Mount is an entry of /proc/mounts
+type Mount struct {
+	MountPoint string
+	Filesystem string
+	Options    []string
+	ReadOnly   bool
+}
+
 type Partition struct {
 	Name       string
-	MountPoint string
+	MountPoint string // The shallowest of its mounts
 	Filesystem string
 	Total      uint64
 	Used       uint64
 	Free       uint64
+
+	Mounts   []Mount // Every mount of the partition, the shallowest first
+	ReadOnly bool    // The mount point is mounted read-only
+	// Mounted read-only at a mount point expected to be read-write, as when
+	// the kernel remounts a failing SD card
+	UnexpectedReadOnly bool
 }
 
 type Device struct {
 	Name       string
 	Partitions map[string]Partition // Keyed by mount point
+
+	// Stacked devices, like LVM volumes or md arrays
+	DMName  string      // Device-mapper name, e.g. vg0-root
+	Slaves  []string    // Devices it is built on
+	Holders []string    // Devices built on it or on its partitions
+	RAID    *RAIDStatus // md arrays only
+
+	Health *DeviceHealth // Null when the wear and SMART state are unknown
+}
+
+// RAIDMember is a disk or partition of an md array
+type RAIDMember struct {
+	Name   string
+	Role   int // Slot in the array
+	Faulty bool
+	Spare  bool
+}
+
+// RAIDStatus is the state of an md array, as reported by /proc/mdstat
+type RAIDStatus struct {
+	Level         string // raid1, raid5...
+	State         string // active, inactive...
+	Devices       int    // Disks the array is made of
+	ActiveDevices int    // Disks in sync
+	Members       []RAIDMember
+	Degraded      bool // Missing or faulty members, or not active, like an array that failed to assemble
+
+	// While resyncing, recovering or checking the array
+	SyncAction   string   // resync, recovery, check, reshape...
+	SyncProgress *float64 // Percent
+	SyncFinish   string   // Estimated time left, e.g. 81.2min
+	SyncSpeed    string   // e.g. 175000K/sec
+}
+
+// DeviceHealth is the wear and health of the storage device with the same
+// name. Measures the device doesn't provide are null.
+type DeviceHealth struct {
+	Device         string
+	Type           string // SD or MMC, from sysfs, or the protocol of smartctl: ATA, NVMe...
+	Source         string // sysfs or smartctl
+	Model          string
+	Serial         string
+	ManufacturerID string // SD and eMMC only
+	OEMID          string // SD and eMMC only
+	Date           string // Manufacturing date of SD and eMMC, as MM/YYYY
+
+	WearPercent        *int     // Life used. Over 100 when the estimated life is exceeded.
+	PreEOL             string   // Reserved blocks of eMMC: normal, warning or urgent
+	SmartPassed        *bool    // Overall SMART self-assessment
+	Temperature        *float64 // Celsius
+	ReallocatedSectors *uint64
+	PowerOnHours       *uint64
+}
+
+// UsageForecast is the trend of the used space of the partition mounted at a
+// mount point, fitted over the samples of the forecast window
+type UsageForecast struct {
+	MountPoint string
+	Partition  string
+	Total      uint64
+	Used       uint64
+	Free       uint64
+
+	Samples       int      // Usage samples in the window
+	WindowSeconds float64  // Time between the oldest and the latest sample
+	FillRate      *float64 // Bytes per day, negative while space is freed. Null without enough samples.
+	// Null when not filling up, or not within a century
+	SecondsUntilFull *float64
+	EstimatedFull    *time.Time
 }
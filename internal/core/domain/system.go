@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type Kernel struct {
+	Name    string // uname -s
+	Release string // uname -r
+	Version string // uname -v
+}
+
+// OSRelease holds the main fields of /etc/os-release, all of them in Fields
+type OSRelease struct {
+	ID              string
+	Name            string
+	PrettyName      string
+	Version         string
+	VersionID       string
+	VersionCodename string
+	Fields          map[string]string
+}
+
+type System struct {
+	Hostname      string
+	Kernel        Kernel
+	OS            OSRelease
+	Architecture  string
+	UptimeSeconds float64
+	BootTime      time.Time
+	MachineID     string
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type SystemdUnit struct {
+	Name          string
+	Description   string
+	LoadState     string // not-found for units that don't exist
+	ActiveState   string
+	SubState      string
+	UnitFileState string
+	MainPID       int
+	Restarts      int // Automatic restarts by systemd since the unit was loaded
+	ActiveSince   time.Time
+}
+
+// Services holds the configured units and every failed unit of the system
+type Services struct {
+	Units  []SystemdUnit
+	Failed []SystemdUnit
+}
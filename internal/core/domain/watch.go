@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// WatchRule tells how to find a watched process. Only one way of matching it
+// is used, in order: Pidfile, Process and Cmdline.
+type WatchRule struct {
+	Name    string
+	Process string // Exact process name
+	Cmdline string // Regular expression on the command line
+	Pidfile string
+}
+
+type WatchedProcess struct {
+	Name          string
+	Up            bool
+	PID           int // Last known one while down
+	Matches       int // Processes matching the rule, the oldest is reported
+	StartTime     time.Time
+	UptimeSeconds float64
+	Restarts      int // PID changes detected since the API started
+	CPUPercent    float64
+	RSS           uint64 // Bytes
+}
@@ -0,0 +1,10 @@
+package ports
+
+// BoardPort defines the interface for retrieving the hardware board model,
+// and its revision when it is a Raspberry Pi.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type BoardPort interface {
+	GetBoardInfo() (domain.Board, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+// CgroupPort defines the interface for retrieving the resource accounting of
+// cgroups, and CgroupsPort the one for the configured ones.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type CgroupPort interface {
+	// paths ending in /* stand for every child cgroup of the path
+	GetCgroups(paths []string) ([]domain.Cgroup, error)
+}
+
+type CgroupsPort interface {
+	GetCgroups() ([]domain.Cgroup, error)
+}
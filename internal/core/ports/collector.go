@@ -0,0 +1,9 @@
+package ports
+
+// CollectorPort gives access, by name, to every collector of the API.
+// Collect returns the same value its dedicated endpoint would.
+
+type CollectorPort interface {
+	Names() []string
+	Collect(name string) (any, error)
+}
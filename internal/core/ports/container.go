@@ -0,0 +1,10 @@
+package ports
+
+// ContainerPort defines the interface for retrieving the containers of the
+// Docker Engine along with their resource usage.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type ContainerPort interface {
+	GetContainers() ([]domain.Container, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+// DataSourcePort is implemented by repositories to report what they read
+// from, RunRecorderPort by what keeps the stats of the collectors runs, and
+// DiagnosticsPort by what aggregates both for every collector.
+
+import (
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+type DataSourcePort interface {
+	DataSources() []domain.DataSource
+}
+
+type RunRecorderPort interface {
+	Record(name string, start time.Time, err error)
+}
+
+type DiagnosticsPort interface {
+	Readiness() domain.Readiness
+	Diagnostics() []domain.CollectorDiagnostics
+}
@@ -0,0 +1,21 @@
+package ports
+
+// DirectoryPort defines the interface for walking a directory to measure its
+// size and find its largest files.
+
+import (
+	"context"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+type DirectoryPort interface {
+	ScanDirectory(ctx context.Context, path string, top int) (domain.DirectoryScan, error)
+}
+
+// DirectoriesPort defines the interface for retrieving the latest scan of
+// the watched directories.
+
+type DirectoriesPort interface {
+	GetDirectories() ([]domain.DirectoryUsage, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+// SamplePort is implemented by anything able to express its current state
+// as a list of samples, and MetricsSinkPort by any destination for them.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type SamplePort interface {
+	Samples() ([]domain.Sample, error)
+}
+
+type MetricsSinkPort interface {
+	Write(samples []domain.Sample) error
+	Close() error
+}
@@ -0,0 +1,17 @@
+package ports
+
+// MountPort defines the interface for retrieving the mount table along with
+// the capacity of every filesystem.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type MountPort interface {
+	GetMounts(filter domain.MountFilter) ([]domain.MountedFilesystem, error)
+}
+
+// MountsPort defines the interface for retrieving the configured selection
+// of the mount table.
+
+type MountsPort interface {
+	GetMounts() ([]domain.MountedFilesystem, error)
+}
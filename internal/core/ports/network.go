@@ -8,3 +8,36 @@ import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
 type NetworkPort interface {
 	GetNetworkInterfaces() ([]domain.NetworkInterface, error)
 }
+
+// NetworkProtocolPort defines the interface for retrieving the TCP and UDP
+// counters and the sockets in use.
+
+type NetworkProtocolPort interface {
+	GetProtocols() (domain.NetworkProtocols, error)
+}
+
+// SocketPort defines the interface for listing the TCP and UDP sockets along
+// with the processes holding them, and SocketsPort the one for selecting
+// some of them.
+
+type SocketPort interface {
+	GetSockets() ([]domain.Socket, error)
+}
+
+type SocketsPort interface {
+	GetSockets(filter domain.SocketFilter) ([]domain.Socket, error)
+}
+
+// RoutePort defines the interface for retrieving the routing tables and the
+// DNS resolver configuration.
+
+type RoutePort interface {
+	GetRouting() (domain.Routing, error)
+}
+
+// NeighborPort defines the interface for retrieving the hosts seen on the
+// local networks.
+
+type NeighborPort interface {
+	GetNeighbors() ([]domain.Neighbor, error)
+}
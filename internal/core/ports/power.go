@@ -0,0 +1,10 @@
+package ports
+
+// PowerPort defines the interface for retrieving the power supplies and the
+// under-voltage and throttling status.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type PowerPort interface {
+	GetPower() (domain.Power, error)
+}
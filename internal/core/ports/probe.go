@@ -0,0 +1,16 @@
+package ports
+
+// ProbePort defines the interface for running a connectivity check once.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type ProbePort interface {
+	Probe(target domain.ProbeTarget) (domain.ProbeAttempt, error)
+}
+
+// ProbesPort defines the interface for retrieving the latest state of the
+// scheduled probes.
+
+type ProbesPort interface {
+	GetProbes() ([]domain.Probe, error)
+}
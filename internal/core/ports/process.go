@@ -0,0 +1,15 @@
+package ports
+
+// ProcessPort defines the interface for listing the running processes, and
+// TopProcessesPort the one for picking the heaviest of them.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type ProcessPort interface {
+	GetProcesses() ([]domain.Process, error)
+}
+
+type TopProcessesPort interface {
+	// sortBy is one of the domain.ProcessSortKeys, a limit of 0 means all
+	GetTopProcesses(sortBy string, limit int) ([]domain.Process, error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+// SensorPort defines the interface for retrieving the readings of the hwmon
+// sensors.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type SensorPort interface {
+	GetSensors() ([]domain.Sensor, error)
+}
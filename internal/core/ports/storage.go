@@ -8,3 +8,17 @@ import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
 type StoragePort interface {
 	GetDevices() ([]domain.Device, error)
 }
+
+// StorageHealthPort defines the interface for retrieving the wear and health
+// of the storage devices.
+
+type StorageHealthPort interface {
+	GetDevicesHealth() ([]domain.DeviceHealth, error)
+}
+
+// StorageForecastPort defines the interface for retrieving the fill trend of
+// every mounted partition.
+
+type StorageForecastPort interface {
+	GetForecasts() ([]domain.UsageForecast, error)
+}
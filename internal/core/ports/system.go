@@ -0,0 +1,10 @@
+package ports
+
+// SystemPort defines the interface for retrieving the identity of the
+// system: host, kernel, OS and uptime.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type SystemPort interface {
+	GetSystemInfo() (domain.System, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+// SystemdPort defines the interface for retrieving the state of systemd units,
+// and ServicesPort the one for the configured units along with failed ones.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type SystemdPort interface {
+	GetUnits(names []string) ([]domain.SystemdUnit, error)
+	GetFailedUnits() ([]domain.SystemdUnit, error)
+}
+
+type ServicesPort interface {
+	GetServices() (domain.Services, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+// PidfilePort defines the interface for reading the PID written in a pidfile,
+// and WatchPort the one for checking the liveness of the watched processes.
+
+import "github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+type PidfilePort interface {
+	ReadPidfile(path string) (int, error)
+}
+
+type WatchPort interface {
+	GetWatchedProcesses() ([]domain.WatchedProcess, error)
+}
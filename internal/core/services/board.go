@@ -0,0 +1,29 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// BoardService provides business logic related to the hardware board.
+// Acts as a middleman between the core domain model (Board) and the outside
+type BoardService struct {
+	boardPort ports.BoardPort
+	recorder  ports.RunRecorderPort
+}
+
+// Service constructor
+func NewBoardService(boardPort ports.BoardPort) *BoardService {
+	return &BoardService{boardPort: boardPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *BoardService) WithRecorder(recorder ports.RunRecorderPort) *BoardService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the board model and revision
+func (s *BoardService) GetBoardInfo() (domain.Board, error) {
+	return track(s.recorder, "board", s.boardPort.GetBoardInfo)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBoardPort struct {
+	mockResult domain.Board
+	mockError  error
+}
+
+func (m *mockBoardPort) GetBoardInfo() (domain.Board, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetBoardInfoValues(t *testing.T) {
+
+	mockPort := &mockBoardPort{
+		mockResult: domain.Board{
+			Model:         "Raspberry Pi 4 Model B Rev 1.4",
+			IsRaspberryPi: true,
+			Revision:      &domain.BoardRevision{Code: "c03114", Model: "4B", MemoryMB: 4096},
+		},
+	}
+
+	registry := NewCollectorRegistry()
+	svc := NewBoardService(mockPort).WithRecorder(registry)
+
+	result, err := svc.GetBoardInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "Raspberry Pi 4 Model B Rev 1.4", result.Model)
+	assert.Equal(t, uint64(4096), result.Revision.MemoryMB)
+	assert.Equal(t, uint64(1), registry.Stats("board").Runs)
+}
+
+func TestGetBoardInfoSimulateError(t *testing.T) {
+
+	mockPort := &mockBoardPort{
+		mockResult: domain.Board{},
+		mockError:  errors.New("unable to read cpuinfo"),
+	}
+
+	svc := NewBoardService(mockPort)
+
+	_, err := svc.GetBoardInfo()
+	assert.Error(t, err)
+	assert.Equal(t, "unable to read cpuinfo", err.Error())
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// CgroupService provides business logic related to the cgroups accounting.
+// Acts as a middleman between the core domain model (Cgroup) and the outside
+type CgroupService struct {
+	cgroupPort ports.CgroupPort
+	paths      []string
+	recorder   ports.RunRecorderPort
+}
+
+// Service constructor. paths are the cgroups reported, see ports.CgroupPort.
+func NewCgroupService(cgroupPort ports.CgroupPort, paths []string) *CgroupService {
+	return &CgroupService{cgroupPort: cgroupPort, paths: paths}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *CgroupService) WithRecorder(recorder ports.RunRecorderPort) *CgroupService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the accounting of the configured cgroups
+func (s *CgroupService) GetCgroups() ([]domain.Cgroup, error) {
+	return track(s.recorder, "cgroups", func() ([]domain.Cgroup, error) {
+		return s.cgroupPort.GetCgroups(s.paths)
+	})
+}
+
+// Samples expresses the accounting of every cgroup as a sample
+func (s *CgroupService) Samples() ([]domain.Sample, error) {
+	cgroups, err := s.GetCgroups()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(cgroups))
+	for _, cgroup := range cgroups {
+		fields := map[string]any{
+			"cpu_usage_usec":     cgroup.CPU.UsageUsec,
+			"cpu_throttled_usec": cgroup.CPU.ThrottledUsec,
+			"memory_current":     cgroup.Memory.Current,
+			"oom_kills":          cgroup.Memory.OOMKills,
+			"pids_current":       cgroup.Pids.Current,
+		}
+		if cgroup.Memory.Max != nil {
+			fields["memory_max"] = *cgroup.Memory.Max
+		}
+
+		var readBytes, writeBytes uint64
+		for _, device := range cgroup.IO {
+			readBytes += device.ReadBytes
+			writeBytes += device.WriteBytes
+		}
+		fields["io_read_bytes"] = readBytes
+		fields["io_write_bytes"] = writeBytes
+
+		samples = append(samples, domain.Sample{
+			Measurement: "cgroup",
+			Tags:        map[string]string{"path": cgroup.Path},
+			Fields:      fields,
+		})
+	}
+	return samples, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCgroupPort struct {
+	mockResult []domain.Cgroup
+	mockError  error
+	askedPaths []string
+}
+
+func (m *mockCgroupPort) GetCgroups(paths []string) ([]domain.Cgroup, error) {
+	m.askedPaths = paths
+	return m.mockResult, m.mockError
+}
+
+func TestGetCgroupsValues(t *testing.T) {
+
+	memoryMax := uint64(256 << 20)
+	mockPort := &mockCgroupPort{
+		mockResult: []domain.Cgroup{
+			{
+				Path:   "/system.slice/myapp.service",
+				CPU:    domain.CgroupCPU{UsageUsec: 98765432, ThrottledUsec: 12000000},
+				Memory: domain.CgroupMemory{Current: 200 << 20, Max: &memoryMax, OOMKills: 2},
+				IO:     []domain.CgroupIO{{Device: "mmcblk0", ReadBytes: 100, WriteBytes: 200}, {Device: "sda", ReadBytes: 1, WriteBytes: 2}},
+				Pids:   domain.CgroupPids{Current: 17},
+			},
+			{Path: "/user.slice", Memory: domain.CgroupMemory{Current: 100 << 20}},
+		},
+	}
+
+	svc := NewCgroupService(mockPort, []string{"system.slice/*", "user.slice"})
+
+	result, err := svc.GetCgroups()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"system.slice/*", "user.slice"}, mockPort.askedPaths)
+	assert.Len(t, result, 2)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, "cgroup", samples[0].Measurement)
+	assert.Equal(t, "/system.slice/myapp.service", samples[0].Tags["path"])
+	assert.Equal(t, uint64(256<<20), samples[0].Fields["memory_max"])
+	assert.Equal(t, uint64(2), samples[0].Fields["oom_kills"])
+	assert.Equal(t, uint64(101), samples[0].Fields["io_read_bytes"])
+	assert.Equal(t, uint64(202), samples[0].Fields["io_write_bytes"])
+
+	// Unlimited memory has no field
+	_, exists := samples[1].Fields["memory_max"]
+	assert.False(t, exists)
+}
+
+func TestGetCgroupsSimulateError(t *testing.T) {
+
+	mockPort := &mockCgroupPort{
+		mockError: errors.New("cgroup v2 unified hierarchy not found"),
+	}
+
+	svc := NewCgroupService(mockPort, []string{"system.slice/*"})
+
+	_, err := svc.GetCgroups()
+	assert.Error(t, err)
+	assert.Equal(t, "cgroup v2 unified hierarchy not found", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
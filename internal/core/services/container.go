@@ -0,0 +1,61 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// ContainerService provides business logic related to the Docker containers.
+// Acts as a middleman between the core domain model (Container) and the outside
+type ContainerService struct {
+	containerPort ports.ContainerPort
+	recorder      ports.RunRecorderPort
+}
+
+// Service constructor
+func NewContainerService(containerPort ports.ContainerPort) *ContainerService {
+	return &ContainerService{containerPort: containerPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *ContainerService) WithRecorder(recorder ports.RunRecorderPort) *ContainerService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the containers and their usage
+func (s *ContainerService) GetContainers() ([]domain.Container, error) {
+	return track(s.recorder, "containers", s.containerPort.GetContainers)
+}
+
+// Samples expresses the state and usage of every container as a sample
+func (s *ContainerService) Samples() ([]domain.Sample, error) {
+	containers, err := s.GetContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(containers))
+	for _, container := range containers {
+		samples = append(samples, domain.Sample{
+			Measurement: "container",
+			Tags: map[string]string{
+				"container": container.Name,
+				"image":     container.Image,
+			},
+			Fields: map[string]any{
+				"running":       container.State == "running",
+				"state":         container.State,
+				"restart_count": int64(container.RestartCount),
+				"cpu_percent":   container.CPUPercent,
+				"memory_usage":  container.MemoryUsage,
+				"memory_limit":  container.MemoryLimit,
+				"network_rx":    container.NetworkRx,
+				"network_tx":    container.NetworkTx,
+				"block_read":    container.BlockRead,
+				"block_write":   container.BlockWrite,
+			},
+		})
+	}
+	return samples, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockContainerPort struct {
+	mockResult []domain.Container
+	mockError  error
+}
+
+func (m *mockContainerPort) GetContainers() ([]domain.Container, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetContainersValues(t *testing.T) {
+
+	mockPort := &mockContainerPort{
+		mockResult: []domain.Container{
+			{Name: "pihole", Image: "pihole/pihole:latest", State: "running", CPUPercent: 12.5, MemoryUsage: 100 << 20},
+			{Name: "backup", Image: "restic/restic", State: "exited", RestartCount: 2},
+		},
+	}
+
+	svc := NewContainerService(mockPort)
+
+	result, err := svc.GetContainers()
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, "container", samples[0].Measurement)
+	assert.Equal(t, "pihole", samples[0].Tags["container"])
+	assert.Equal(t, true, samples[0].Fields["running"])
+	assert.Equal(t, 12.5, samples[0].Fields["cpu_percent"])
+	assert.Equal(t, uint64(100<<20), samples[0].Fields["memory_usage"])
+	assert.Equal(t, false, samples[1].Fields["running"])
+	assert.Equal(t, int64(2), samples[1].Fields["restart_count"])
+}
+
+func TestGetContainersSimulateError(t *testing.T) {
+
+	mockPort := &mockContainerPort{
+		mockError: errors.New("dial unix /var/run/docker.sock: connect: no such file or directory"),
+	}
+
+	svc := NewContainerService(mockPort)
+
+	_, err := svc.GetContainers()
+	assert.Error(t, err)
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
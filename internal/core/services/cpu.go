@@ -8,7 +8,8 @@ import (
 // CPUService provides business logic related to CPU operations.
 // Acts as a middleman between the core domain model (CPU) and the outside
 type CPUService struct {
-	cpuPort ports.CPUPort
+	cpuPort  ports.CPUPort
+	recorder ports.RunRecorderPort
 }
 
 // Service constructor
@@ -16,7 +17,31 @@ func NewCPUService(cpuPort ports.CPUPort) *CPUService {
 	return &CPUService{cpuPort: cpuPort}
 }
 
+// WithRecorder reports every run of the service to the recorder
+func (s *CPUService) WithRecorder(recorder ports.RunRecorderPort) *CPUService {
+	s.recorder = recorder
+	return s
+}
+
 // Business logic to get the CPU load, N functions from here
 func (s *CPUService) GetCPULoad() (domain.CPU, error) {
-	return s.cpuPort.GetCPULoad()
+	return track(s.recorder, "cpu", s.cpuPort.GetCPULoad)
+}
+
+// Samples expresses the CPU load as metrics samples
+func (s *CPUService) Samples() ([]domain.Sample, error) {
+	cpu, err := s.GetCPULoad()
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.Sample{{
+		Measurement: "cpu",
+		Tags:        map[string]string{},
+		Fields: map[string]any{
+			"load1":  cpu.LoadAvg1Min,
+			"load5":  cpu.LoadAvg5Min,
+			"load15": cpu.LoadAvg15Min,
+		},
+	}}, nil
 }
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// DiagnosticsService tells, for every collector of the registry, whether its
+// data sources are available and how its last runs went
+type DiagnosticsService struct {
+	registry *CollectorRegistry
+	sources  map[string]ports.DataSourcePort
+	core     map[string]bool // Collectors readiness depends on, all when empty
+}
+
+// Service constructor
+func NewDiagnosticsService(registry *CollectorRegistry) *DiagnosticsService {
+	return &DiagnosticsService{
+		registry: registry,
+		sources:  make(map[string]ports.DataSourcePort),
+	}
+}
+
+// WithCore limits readiness to the collectors the host can't be monitored
+// without, so that optional features don't keep it from being ready
+func (s *DiagnosticsService) WithCore(collectors []string) *DiagnosticsService {
+	s.core = make(map[string]bool, len(collectors))
+	for _, collector := range collectors {
+		s.core[collector] = true
+	}
+	return s
+}
+
+// AddSources sets where the data sources of a collector are checked
+func (s *DiagnosticsService) AddSources(collector string, port ports.DataSourcePort) {
+	s.sources[collector] = port
+}
+
+func (s *DiagnosticsService) checkSources(collector string) ([]domain.DataSource, bool) {
+	port, exists := s.sources[collector]
+	if !exists {
+		return []domain.DataSource{}, true
+	}
+
+	sources := port.DataSources()
+	ready := true
+	for _, source := range sources {
+		if source.Required && !source.Available {
+			ready = false
+		}
+	}
+	return sources, ready
+}
+
+// Readiness checks that every required data source is available, without
+// running any collector. Every collector is reported, but only the core ones
+// make the host not ready.
+func (s *DiagnosticsService) Readiness() domain.Readiness {
+	readiness := domain.Readiness{Ready: true, Collectors: map[string]bool{}}
+	for _, name := range s.registry.Names() {
+		_, ready := s.checkSources(name)
+		readiness.Collectors[name] = ready
+		if len(s.core) == 0 || s.core[name] {
+			readiness.Ready = readiness.Ready && ready
+		}
+	}
+	return readiness
+}
+
+func (s *DiagnosticsService) Diagnostics() []domain.CollectorDiagnostics {
+	names := s.registry.Names()
+	diagnostics := make([]domain.CollectorDiagnostics, 0, len(names))
+	for _, name := range names {
+		sources, ready := s.checkSources(name)
+
+		capabilities := []string{}
+		for _, source := range sources {
+			if source.Available && source.Provides != "" {
+				capabilities = append(capabilities, source.Provides)
+			}
+		}
+
+		diagnostics = append(diagnostics, domain.CollectorDiagnostics{
+			Name:         name,
+			Ready:        ready,
+			Sources:      sources,
+			Capabilities: capabilities,
+			Stats:        s.registry.Stats(name),
+		})
+	}
+	return diagnostics
+}
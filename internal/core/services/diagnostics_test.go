@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDataSourcePort struct {
+	sources []domain.DataSource
+}
+
+func (m *mockDataSourcePort) DataSources() []domain.DataSource {
+	return m.sources
+}
+
+func TestDiagnosticsService(t *testing.T) {
+
+	registry := NewCollectorRegistry()
+	cpuSvc := NewCPUService(&mockCPUPort{mockResult: domain.CPU{LoadAvg1Min: 0.5}}).WithRecorder(registry)
+	networkSvc := NewNetworkService(&mockNetworkPort{mockError: errors.New("unable to read Network Interfaces info")}).WithRecorder(registry)
+	registry.Register("cpu", func() (any, error) { return cpuSvc.GetCPULoad() })
+	registry.Register("network", func() (any, error) { return networkSvc.GetNetworkInterfaces() })
+
+	svc := NewDiagnosticsService(registry)
+	svc.AddSources("cpu", &mockDataSourcePort{sources: []domain.DataSource{
+		{Name: "/proc/loadavg", Kind: "file", Required: true, Available: true, Provides: "load averages"},
+	}})
+	svc.AddSources("network", &mockDataSourcePort{sources: []domain.DataSource{
+		{Name: "/proc/net/dev", Kind: "file", Required: true, Available: true, Provides: "interface counters"},
+		{Name: "iwconfig", Kind: "tool", Required: false, Available: false, Provides: "wireless bit rate"},
+	}})
+
+	// A missing optional source doesn't prevent readiness
+	readiness := svc.Readiness()
+	assert.True(t, readiness.Ready)
+	assert.Equal(t, map[string]bool{"cpu": true, "network": true}, readiness.Collectors)
+
+	// Runs are recorded both directly and through the registry
+	_, err := cpuSvc.GetCPULoad()
+	assert.NoError(t, err)
+	_, err = registry.Collect("network")
+	assert.Error(t, err)
+
+	diagnostics := svc.Diagnostics()
+	assert.Len(t, diagnostics, 2)
+
+	cpu := diagnostics[0]
+	assert.Equal(t, "cpu", cpu.Name)
+	assert.True(t, cpu.Ready)
+	assert.Equal(t, []string{"load averages"}, cpu.Capabilities)
+	assert.Equal(t, uint64(1), cpu.Stats.Runs)
+	assert.Equal(t, uint64(0), cpu.Stats.Failures)
+	assert.Empty(t, cpu.Stats.LastError)
+	assert.False(t, cpu.Stats.LastRun.IsZero())
+
+	network := diagnostics[1]
+	assert.Equal(t, []string{"interface counters"}, network.Capabilities)
+	assert.Equal(t, uint64(1), network.Stats.Failures)
+	assert.Equal(t, "unable to read Network Interfaces info", network.Stats.LastError)
+}
+
+func TestDiagnosticsService_NotReady(t *testing.T) {
+
+	registry := NewCollectorRegistry()
+	registry.Register("storage", func() (any, error) { return nil, nil })
+	registry.Register("ram", func() (any, error) { return nil, nil })
+
+	svc := NewDiagnosticsService(registry)
+	svc.AddSources("storage", &mockDataSourcePort{sources: []domain.DataSource{
+		{Name: "df", Kind: "tool", Required: true, Available: false},
+	}})
+
+	readiness := svc.Readiness()
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, map[string]bool{"storage": false, "ram": true}, readiness.Collectors)
+}
+
+func TestDiagnosticsService_CoreReadiness(t *testing.T) {
+
+	registry := NewCollectorRegistry()
+	registry.Register("ram", func() (any, error) { return nil, nil })
+	registry.Register("sensors", func() (any, error) { return nil, nil })
+
+	svc := NewDiagnosticsService(registry).WithCore([]string{"ram"})
+	svc.AddSources("sensors", &mockDataSourcePort{sources: []domain.DataSource{
+		{Name: "/sys/class/hwmon", Kind: "file", Required: true, Available: false},
+	}})
+
+	// An optional feature is reported, without keeping the host from being ready
+	readiness := svc.Readiness()
+	assert.True(t, readiness.Ready)
+	assert.Equal(t, map[string]bool{"ram": true, "sensors": false}, readiness.Collectors)
+
+	svc.AddSources("ram", &mockDataSourcePort{sources: []domain.DataSource{
+		{Name: "/proc/meminfo", Kind: "file", Required: true, Available: false},
+	}})
+	assert.False(t, svc.Readiness().Ready)
+}
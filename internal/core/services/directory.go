@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// DirectoryService provides business logic related to the watched directories.
+// Acts as a middleman between the core domain model (DirectoryUsage) and the outside
+type DirectoryService struct {
+	directoryPort ports.DirectoryPort
+	rules         []domain.DirectoryRule
+	top           int
+	recorder      ports.RunRecorderPort
+	now           func() time.Time
+
+	mutex   sync.Mutex
+	results map[string]domain.DirectoryUsage // Keyed by path
+}
+
+// Service constructor. Directories are walked by Run or Scan, which report
+// their top largest files, and requests get the latest results.
+func NewDirectoryService(directoryPort ports.DirectoryPort, rules []domain.DirectoryRule, top int) *DirectoryService {
+	return &DirectoryService{
+		directoryPort: directoryPort,
+		rules:         rules,
+		top:           top,
+		now:           time.Now,
+		results:       make(map[string]domain.DirectoryUsage),
+	}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *DirectoryService) WithRecorder(recorder ports.RunRecorderPort) *DirectoryService {
+	s.recorder = recorder
+	return s
+}
+
+// Scan walks every watched directory, one after another. A failed walk keeps
+// the previous results along with the error. Once the context is cancelled,
+// the walk in progress is abandoned and the rest are skipped.
+func (s *DirectoryService) Scan(ctx context.Context) {
+	for _, rule := range s.rules {
+		scan, err := s.directoryPort.ScanDirectory(ctx, rule.Path, s.top)
+		if ctx.Err() != nil {
+			return
+		}
+		scannedAt := s.now()
+
+		s.mutex.Lock()
+		result := s.results[rule.Path]
+		result.Error = ""
+		if err != nil {
+			log.Printf("Error walking %s: %v", rule.Path, err)
+			result.Error = err.Error()
+		} else {
+			result.Scan = &scan
+			result.ScannedAt = &scannedAt
+		}
+		s.results[rule.Path] = result
+		s.mutex.Unlock()
+	}
+}
+
+// Run walks the directories right away and then every interval, until the
+// context is cancelled
+func (s *DirectoryService) Run(ctx context.Context, interval time.Duration) {
+	if len(s.rules) == 0 {
+		return
+	}
+	s.Scan(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Scan(ctx)
+		}
+	}
+}
+
+// Business logic to get the latest results of every watched directory, in
+// the order of the configuration
+func (s *DirectoryService) GetDirectories() ([]domain.DirectoryUsage, error) {
+	return track(s.recorder, "directories", func() ([]domain.DirectoryUsage, error) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		directories := make([]domain.DirectoryUsage, 0, len(s.rules))
+		for _, rule := range s.rules {
+			directory := s.results[rule.Path]
+			directory.Path = rule.Path
+			directory.SoftLimit = rule.SoftLimit
+			directory.OverLimit = rule.SoftLimit > 0 && directory.Scan != nil && directory.Scan.Size > rule.SoftLimit
+			directories = append(directories, directory)
+		}
+		return directories, nil
+	})
+}
+
+// Samples expresses every walked directory as a sample. Those not walked yet
+// are left out.
+func (s *DirectoryService) Samples() ([]domain.Sample, error) {
+	directories, err := s.GetDirectories()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(directories))
+	for _, directory := range directories {
+		if directory.Scan == nil {
+			continue
+		}
+		fields := map[string]any{
+			"size":       directory.Scan.Size,
+			"files":      directory.Scan.Files,
+			"unreadable": directory.Scan.Unreadable,
+			"truncated":  directory.Scan.Truncated,
+			"over_limit": directory.OverLimit,
+		}
+		if directory.SoftLimit > 0 {
+			fields["soft_limit"] = directory.SoftLimit
+		}
+
+		samples = append(samples, domain.Sample{
+			Measurement: "directory",
+			Tags:        map[string]string{"path": directory.Path},
+			Fields:      fields,
+		})
+	}
+	return samples, nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDirectoryPort struct {
+	mockResult map[string]domain.DirectoryScan
+	mockError  error
+	askedTop   int
+}
+
+func (m *mockDirectoryPort) ScanDirectory(ctx context.Context, path string, top int) (domain.DirectoryScan, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.DirectoryScan{}, err
+	}
+	m.askedTop = top
+	if m.mockError != nil {
+		return domain.DirectoryScan{}, m.mockError
+	}
+	scan, exists := m.mockResult[path]
+	if !exists {
+		return domain.DirectoryScan{}, errors.New("open " + path + ": no such file or directory")
+	}
+	return scan, nil
+}
+
+func TestGetDirectoriesValues(t *testing.T) {
+
+	mockPort := &mockDirectoryPort{
+		mockResult: map[string]domain.DirectoryScan{
+			"/var/log": {
+				Size: 600 << 20, Files: 120, Directories: 8,
+				Largest: []domain.DirectoryFile{{Path: "/var/log/syslog", Size: 400 << 20}},
+			},
+			"/home/pi/recordings": {Size: 2 << 30, Files: 40, Directories: 1, Largest: []domain.DirectoryFile{}},
+		},
+	}
+	rules := []domain.DirectoryRule{
+		{Path: "/var/log", SoftLimit: 500 << 20},
+		{Path: "/home/pi/recordings"},
+		{Path: "/srv/missing", SoftLimit: 1},
+	}
+	scannedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	svc := NewDirectoryService(mockPort, rules, 5)
+	svc.now = func() time.Time { return scannedAt }
+
+	// Nothing is reported until the first walk
+	result, err := svc.GetDirectories()
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Nil(t, result[0].Scan)
+	assert.False(t, result[0].OverLimit)
+
+	svc.Scan(context.Background())
+	assert.Equal(t, 5, mockPort.askedTop)
+
+	result, err = svc.GetDirectories()
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/log", result[0].Path)
+	assert.True(t, result[0].OverLimit)
+	assert.Equal(t, scannedAt, *result[0].ScannedAt)
+	assert.Equal(t, 120, result[0].Scan.Files)
+	assert.False(t, result[1].OverLimit)
+	assert.Equal(t, "open /srv/missing: no such file or directory", result[2].Error)
+	assert.Nil(t, result[2].Scan)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, "directory", samples[0].Measurement)
+	assert.Equal(t, map[string]string{"path": "/var/log"}, samples[0].Tags)
+	assert.Equal(t, true, samples[0].Fields["over_limit"])
+	assert.Equal(t, uint64(500<<20), samples[0].Fields["soft_limit"])
+	assert.NotContains(t, samples[1].Fields, "soft_limit")
+}
+
+func TestGetDirectoriesSimulateError(t *testing.T) {
+
+	mockPort := &mockDirectoryPort{
+		mockResult: map[string]domain.DirectoryScan{"/var/log": {Size: 100, Files: 1}},
+	}
+
+	svc := NewDirectoryService(mockPort, []domain.DirectoryRule{{Path: "/var/log", SoftLimit: 50}}, 5)
+	svc.Scan(context.Background())
+
+	// A failed walk keeps the previous results
+	mockPort.mockError = errors.New("mock error")
+	svc.Scan(context.Background())
+
+	result, err := svc.GetDirectories()
+	assert.NoError(t, err)
+	assert.Equal(t, "mock error", result[0].Error)
+	assert.Equal(t, uint64(100), result[0].Scan.Size)
+	assert.True(t, result[0].OverLimit)
+}
+
+func TestGetDirectoriesCancelled(t *testing.T) {
+
+	mockPort := &mockDirectoryPort{
+		mockResult: map[string]domain.DirectoryScan{"/var/log": {Size: 100, Files: 1}},
+	}
+
+	svc := NewDirectoryService(mockPort, []domain.DirectoryRule{{Path: "/var/log"}}, 5)
+	svc.Scan(context.Background())
+
+	// An abandoned walk is not an error
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	svc.Scan(ctx)
+
+	result, err := svc.GetDirectories()
+	assert.NoError(t, err)
+	assert.Empty(t, result[0].Error)
+	assert.Equal(t, uint64(100), result[0].Scan.Size)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// MetricsService periodically gathers the samples of every source and pushes
+// them to a sink, tagging each of them with the host they come from.
+type MetricsService struct {
+	sink    ports.MetricsSinkPort
+	host    string
+	sources []ports.SamplePort
+	now     func() time.Time
+}
+
+// Service constructor
+func NewMetricsService(sink ports.MetricsSinkPort, host string, sources ...ports.SamplePort) *MetricsService {
+	return &MetricsService{
+		sink:    sink,
+		host:    host,
+		sources: sources,
+		now:     time.Now,
+	}
+}
+
+// Collect gathers the samples of every source. A failing source is logged and
+// skipped so that it doesn't prevent the rest from being reported.
+func (s *MetricsService) Collect() []domain.Sample {
+	timestamp := s.now()
+
+	var samples []domain.Sample
+	for _, source := range s.sources {
+		sourceSamples, err := source.Samples()
+		if err != nil {
+			log.Printf("Error collecting samples: %v", err)
+			continue
+		}
+		for _, sample := range sourceSamples {
+			if sample.Tags == nil {
+				sample.Tags = map[string]string{}
+			}
+			if s.host != "" {
+				sample.Tags["host"] = s.host
+			}
+			if sample.Timestamp.IsZero() {
+				sample.Timestamp = timestamp
+			}
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// Push collects the samples once and writes them to the sink
+func (s *MetricsService) Push() error {
+	samples := s.Collect()
+	if len(samples) == 0 {
+		return nil
+	}
+	return s.sink.Write(samples)
+}
+
+// Run pushes samples every interval until the context is cancelled, then
+// closes the sink so that anything pending is flushed.
+func (s *MetricsService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.sink.Close(); err != nil {
+				log.Printf("Error closing metrics sink: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := s.Push(); err != nil {
+				log.Printf("Error pushing metrics: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockMetricsSink struct {
+	written [][]domain.Sample
+	closed  bool
+}
+
+func (m *mockMetricsSink) Write(samples []domain.Sample) error {
+	m.written = append(m.written, samples)
+	return nil
+}
+
+func (m *mockMetricsSink) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestMetricsServicePush(t *testing.T) {
+
+	cpuSvc := NewCPUService(&mockCPUPort{mockResult: domain.CPU{LoadAvg1Min: 1.5}})
+	networkSvc := NewNetworkService(&mockNetworkPort{mockResult: []domain.NetworkInterface{
+		{InterfaceName: "eth0", Rx: domain.NetworkStats{Bytes: 10}},
+	}})
+	storageSvc := NewStorageService(&mockStoragePort{mockResult: []domain.Device{
+		{Name: "sda", Partitions: map[string]domain.Partition{
+			"sda1": {Name: "sda1", MountPoint: "/", Total: 100},
+			"sda2": {Name: "sda2"},
+		}},
+	}}, nil)
+	failingSvc := NewRAMService(&mockRAMPort{mockError: errors.New("unable to read RAM stats")})
+
+	sink := &mockMetricsSink{}
+	svc := NewMetricsService(sink, "pi", cpuSvc, failingSvc, networkSvc, storageSvc)
+	now := time.Unix(1700000000, 0)
+	svc.now = func() time.Time { return now }
+
+	assert.NoError(t, svc.Push())
+	assert.Len(t, sink.written, 1)
+
+	// The failing RAM source is skipped, the unmounted partition too
+	samples := sink.written[0]
+	assert.Len(t, samples, 3)
+	for _, sample := range samples {
+		assert.Equal(t, "pi", sample.Tags["host"])
+		assert.Equal(t, now, sample.Timestamp)
+	}
+
+	assert.Equal(t, "cpu", samples[0].Measurement)
+	assert.Equal(t, 1.5, samples[0].Fields["load1"])
+
+	assert.Equal(t, "network", samples[1].Measurement)
+	assert.Equal(t, "eth0", samples[1].Tags["interface"])
+	assert.Equal(t, uint64(10), samples[1].Fields["rx_bytes"])
+
+	assert.Equal(t, "storage", samples[2].Measurement)
+	assert.Equal(t, "sda", samples[2].Tags["device"])
+	assert.Equal(t, "/", samples[2].Tags["mountpoint"])
+}
+
+func TestMetricsServicePush_NothingToWrite(t *testing.T) {
+	sink := &mockMetricsSink{}
+	svc := NewMetricsService(sink, "pi")
+
+	assert.NoError(t, svc.Push())
+	assert.Empty(t, sink.written)
+}
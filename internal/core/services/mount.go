@@ -0,0 +1,68 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// MountService provides business logic related to the mount table.
+// Acts as a middleman between the core domain model (MountedFilesystem) and the outside
+type MountService struct {
+	mountPort ports.MountPort
+	filter    domain.MountFilter
+	recorder  ports.RunRecorderPort
+}
+
+// Service constructor. Only the mounts selected by the filter are reported.
+func NewMountService(mountPort ports.MountPort, filter domain.MountFilter) *MountService {
+	return &MountService{mountPort: mountPort, filter: filter}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *MountService) WithRecorder(recorder ports.RunRecorderPort) *MountService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the selected mounts
+func (s *MountService) GetMounts() ([]domain.MountedFilesystem, error) {
+	return track(s.recorder, "mounts", func() ([]domain.MountedFilesystem, error) {
+		return s.mountPort.GetMounts(s.filter)
+	})
+}
+
+// Samples expresses every mount as a sample. Mounts whose capacity couldn't
+// be read are still reported, as unresponsive only when statfs timed out.
+func (s *MountService) Samples() ([]domain.Sample, error) {
+	mounts, err := s.GetMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(mounts))
+	for _, mount := range mounts {
+		fields := map[string]any{
+			"read_only":  mount.ReadOnly,
+			"responsive": !mount.TimedOut,
+		}
+		if usage := mount.Usage; usage != nil {
+			fields["total"] = usage.Total
+			fields["used"] = usage.Used
+			fields["free"] = usage.Free
+			fields["available"] = usage.Available
+			fields["inodes"] = usage.Inodes
+			fields["inodes_free"] = usage.InodesFree
+		}
+
+		samples = append(samples, domain.Sample{
+			Measurement: "mount",
+			Tags: map[string]string{
+				"source":     mount.Source,
+				"mountpoint": mount.MountPoint,
+				"fstype":     mount.Filesystem,
+			},
+			Fields: fields,
+		})
+	}
+	return samples, nil
+}
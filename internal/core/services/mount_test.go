@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockMountPort struct {
+	mockResult  []domain.MountedFilesystem
+	mockError   error
+	askedFilter domain.MountFilter
+}
+
+func (m *mockMountPort) GetMounts(filter domain.MountFilter) ([]domain.MountedFilesystem, error) {
+	m.askedFilter = filter
+	return m.mockResult, m.mockError
+}
+
+func TestGetMountsValues(t *testing.T) {
+
+	mockPort := &mockMountPort{
+		mockResult: []domain.MountedFilesystem{
+			{
+				Source: "tmpfs", MountPoint: "/var/log", Filesystem: "tmpfs", Options: []string{"rw"},
+				Usage: &domain.FilesystemUsage{Total: 100, Used: 40, Free: 60, Available: 60, Inodes: 10, InodesFree: 9},
+			},
+			{
+				Source: "nas:/export/media", MountPoint: "/mnt/media", Filesystem: "nfs4", Options: []string{"ro"}, ReadOnly: true,
+				Error: "statfs timed out: /mnt/media didn't answer in 2s", TimedOut: true,
+			},
+			{
+				Source: "//nas/backup", MountPoint: "/mnt/backup", Filesystem: "cifs", Options: []string{"rw"},
+				Error: "permission denied",
+			},
+		},
+	}
+	filter := domain.MountFilter{ExcludeTypes: []string{"proc"}, ExcludePaths: []string{"/run"}}
+
+	svc := NewMountService(mockPort, filter)
+
+	result, err := svc.GetMounts()
+	assert.NoError(t, err)
+	assert.Equal(t, filter, mockPort.askedFilter)
+	assert.Equal(t, mockPort.mockResult, result)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+	assert.Equal(t, "mount", samples[0].Measurement)
+	assert.Equal(t, map[string]string{"source": "tmpfs", "mountpoint": "/var/log", "fstype": "tmpfs"}, samples[0].Tags)
+	assert.Equal(t, uint64(40), samples[0].Fields["used"])
+	assert.Equal(t, true, samples[0].Fields["responsive"])
+
+	// The hung mount is reported without capacity
+	assert.Equal(t, map[string]any{"read_only": true, "responsive": false}, samples[1].Fields)
+
+	// Denied, but answering
+	assert.Equal(t, map[string]any{"read_only": false, "responsive": true}, samples[2].Fields)
+}
+
+func TestGetMountsSimulateError(t *testing.T) {
+
+	mockPort := &mockMountPort{
+		mockError: errors.New("mock error"),
+	}
+
+	svc := NewMountService(mockPort, domain.MountFilter{})
+
+	_, err := svc.GetMounts()
+	assert.Error(t, err)
+	assert.Equal(t, "mock error", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
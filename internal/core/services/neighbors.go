@@ -0,0 +1,71 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// NeighborService provides business logic related to the hosts seen on the
+// local networks.
+// Acts as a middleman between the core domain model (Neighbor) and the outside
+type NeighborService struct {
+	neighborPort ports.NeighborPort
+	recorder     ports.RunRecorderPort
+}
+
+// Service constructor
+func NewNeighborService(neighborPort ports.NeighborPort) *NeighborService {
+	return &NeighborService{neighborPort: neighborPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *NeighborService) WithRecorder(recorder ports.RunRecorderPort) *NeighborService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the ARP and IPv6 neighbor tables
+func (s *NeighborService) GetNeighbors() ([]domain.Neighbor, error) {
+	return track(s.recorder, "neighbors", s.neighborPort.GetNeighbors)
+}
+
+// Samples counts the neighbors of each family by state
+func (s *NeighborService) Samples() ([]domain.Sample, error) {
+	neighbors, err := s.GetNeighbors()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]map[string]any{}
+	var families []string
+	for _, neighbor := range neighbors {
+		if counts[neighbor.Family] == nil {
+			counts[neighbor.Family] = map[string]any{"total": 0}
+			families = append(families, neighbor.Family)
+		}
+		fields := counts[neighbor.Family]
+		fields["total"] = fields["total"].(int) + 1
+		state := strings.ToLower(neighbor.State)
+		// ip neigh leaves out the state of entries in NUD_NONE
+		if state == "" {
+			state = "none"
+		}
+		if count, ok := fields[state].(int); ok {
+			fields[state] = count + 1
+		} else {
+			fields[state] = 1
+		}
+	}
+
+	samples := make([]domain.Sample, 0, len(families))
+	for _, family := range families {
+		samples = append(samples, domain.Sample{
+			Measurement: "neighbors",
+			Tags:        map[string]string{"family": family},
+			Fields:      counts[family],
+		})
+	}
+	return samples, nil
+}
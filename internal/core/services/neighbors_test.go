@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNeighborPort struct {
+	mockResult []domain.Neighbor
+	mockError  error
+}
+
+func (m *mockNeighborPort) GetNeighbors() ([]domain.Neighbor, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetNeighborsValues(t *testing.T) {
+
+	mockPort := &mockNeighborPort{
+		mockResult: []domain.Neighbor{
+			{Family: "inet", Address: "192.168.1.1", MAC: "38:10:d5:aa:bb:cc", Interface: "eth0", State: "COMPLETE", Vendor: "AVM GmbH"},
+			{Family: "inet", Address: "192.168.1.77", Interface: "eth0", State: "INCOMPLETE"},
+			{Family: "inet", Address: "192.168.1.23", MAC: "b8:27:eb:12:34:56", Interface: "eth0", State: "COMPLETE", Vendor: "Raspberry Pi Foundation"},
+			{Family: "inet6", Address: "fe80::3a10:d5ff:feaa:bbcc", MAC: "38:10:d5:aa:bb:cc", Interface: "eth0", State: "REACHABLE", Vendor: "AVM GmbH"},
+			{Family: "inet6", Address: "fe80::1", Interface: "wlan0"},
+		},
+	}
+
+	svc := NewNeighborService(mockPort)
+
+	result, err := svc.GetNeighbors()
+	assert.NoError(t, err)
+	assert.Equal(t, mockPort.mockResult, result)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Sample{
+		{
+			Measurement: "neighbors",
+			Tags:        map[string]string{"family": "inet"},
+			Fields:      map[string]any{"total": 3, "complete": 2, "incomplete": 1},
+		},
+		{
+			Measurement: "neighbors",
+			Tags:        map[string]string{"family": "inet6"},
+			Fields:      map[string]any{"total": 2, "reachable": 1, "none": 1},
+		},
+	}, samples)
+}
+
+func TestGetNeighborsSimulateError(t *testing.T) {
+
+	mockPort := &mockNeighborPort{
+		mockError: errors.New("mock error"),
+	}
+
+	svc := NewNeighborService(mockPort)
+
+	_, err := svc.GetNeighbors()
+	assert.Error(t, err)
+	assert.Equal(t, "mock error", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
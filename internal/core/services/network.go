@@ -9,6 +9,7 @@ import (
 // Acts as a middleman between the core domain model (Network) and the outside
 type NetworkService struct {
 	networkPort ports.NetworkPort
+	recorder    ports.RunRecorderPort
 }
 
 // Service constructor
@@ -16,7 +17,41 @@ func NewNetworkService(networkPort ports.NetworkPort) *NetworkService {
 	return &NetworkService{networkPort: networkPort}
 }
 
+// WithRecorder reports every run of the service to the recorder
+func (s *NetworkService) WithRecorder(recorder ports.RunRecorderPort) *NetworkService {
+	s.recorder = recorder
+	return s
+}
+
 // Business logic to get the network interfaces, N functions from here
 func (s *NetworkService) GetNetworkInterfaces() ([]domain.NetworkInterface, error) {
-	return s.networkPort.GetNetworkInterfaces()
+	return track(s.recorder, "network", s.networkPort.GetNetworkInterfaces)
+}
+
+// Samples expresses the counters of every interface as a metrics sample
+func (s *NetworkService) Samples() ([]domain.Sample, error) {
+	ifaces, err := s.GetNetworkInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(ifaces))
+	for _, iface := range ifaces {
+		samples = append(samples, domain.Sample{
+			Measurement: "network",
+			Tags:        map[string]string{"interface": iface.InterfaceName},
+			Fields: map[string]any{
+				"bit_rate":   iface.BitRate,
+				"rx_bytes":   iface.Rx.Bytes,
+				"rx_packets": iface.Rx.Packets,
+				"rx_errors":  iface.Rx.Errors,
+				"rx_drops":   iface.Rx.Drops,
+				"tx_bytes":   iface.Tx.Bytes,
+				"tx_packets": iface.Tx.Packets,
+				"tx_errors":  iface.Tx.Errors,
+				"tx_drops":   iface.Tx.Drops,
+			},
+		})
+	}
+	return samples, nil
 }
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// NetworkProtocolService provides business logic related to the TCP and UDP stacks.
+// Acts as a middleman between the core domain model (NetworkProtocols) and the outside
+type NetworkProtocolService struct {
+	protocolPort ports.NetworkProtocolPort
+	recorder     ports.RunRecorderPort
+}
+
+// Service constructor
+func NewNetworkProtocolService(protocolPort ports.NetworkProtocolPort) *NetworkProtocolService {
+	return &NetworkProtocolService{protocolPort: protocolPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *NetworkProtocolService) WithRecorder(recorder ports.RunRecorderPort) *NetworkProtocolService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the protocol counters and the sockets in use
+func (s *NetworkProtocolService) GetProtocols() (domain.NetworkProtocols, error) {
+	return track(s.recorder, "network_protocols", s.protocolPort.GetProtocols)
+}
+
+// Samples expresses the TCP and UDP counters and the sockets in use as a
+// sample each. The counters of /proc/net/netstat and sockstat6 are left out
+// when missing.
+func (s *NetworkProtocolService) Samples() ([]domain.Sample, error) {
+	protocols, err := s.GetProtocols()
+	if err != nil {
+		return nil, err
+	}
+
+	tcp := protocols.TCP
+	tcpFields := map[string]any{
+		"active_opens":        tcp.ActiveOpens,
+		"passive_opens":       tcp.PassiveOpens,
+		"attempt_fails":       tcp.AttemptFails,
+		"established_resets":  tcp.EstablishedResets,
+		"current_established": tcp.CurrentEstablished,
+		"in_segments":         tcp.InSegments,
+		"out_segments":        tcp.OutSegments,
+		"retrans_segments":    tcp.RetransSegments,
+		"in_errors":           tcp.InErrors,
+		"out_resets":          tcp.OutResets,
+	}
+	if tcp.ListenOverflows != nil {
+		tcpFields["listen_overflows"] = *tcp.ListenOverflows
+	}
+	if tcp.ListenDrops != nil {
+		tcpFields["listen_drops"] = *tcp.ListenDrops
+	}
+	if tcp.Timeouts != nil {
+		tcpFields["timeouts"] = *tcp.Timeouts
+	}
+
+	udp := protocols.UDP
+	udpFields := map[string]any{
+		"in_datagrams":          udp.InDatagrams,
+		"out_datagrams":         udp.OutDatagrams,
+		"no_ports":              udp.NoPorts,
+		"in_errors":             udp.InErrors,
+		"receive_buffer_errors": udp.ReceiveBufferErrors,
+		"send_buffer_errors":    udp.SendBufferErrors,
+		"checksum_errors":       udp.ChecksumErrors,
+	}
+
+	sockets := protocols.Sockets
+	socketFields := map[string]any{
+		"used":          sockets.Used,
+		"tcp_inuse":     sockets.TCPInUse,
+		"tcp_orphan":    sockets.TCPOrphan,
+		"tcp_time_wait": sockets.TCPTimeWait,
+		"tcp_alloc":     sockets.TCPAlloc,
+		"tcp_memory":    sockets.TCPMemory,
+		"udp_inuse":     sockets.UDPInUse,
+		"udp_memory":    sockets.UDPMemory,
+		"raw_inuse":     sockets.RawInUse,
+	}
+	if sockets.TCP6InUse != nil {
+		socketFields["tcp6_inuse"] = *sockets.TCP6InUse
+	}
+	if sockets.UDP6InUse != nil {
+		socketFields["udp6_inuse"] = *sockets.UDP6InUse
+	}
+	if sockets.Raw6InUse != nil {
+		socketFields["raw6_inuse"] = *sockets.Raw6InUse
+	}
+
+	return []domain.Sample{
+		{Measurement: "tcp", Tags: map[string]string{}, Fields: tcpFields},
+		{Measurement: "udp", Tags: map[string]string{}, Fields: udpFields},
+		{Measurement: "sockets", Tags: map[string]string{}, Fields: socketFields},
+	}, nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNetworkProtocolPort struct {
+	mockResult domain.NetworkProtocols
+	mockError  error
+}
+
+func (m *mockNetworkProtocolPort) GetProtocols() (domain.NetworkProtocols, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetNetworkProtocolsValues(t *testing.T) {
+
+	overflows := uint64(17)
+	tcp6 := uint64(4)
+	mockPort := &mockNetworkProtocolPort{
+		mockResult: domain.NetworkProtocols{
+			TCP:     domain.TCPStats{ActiveOpens: 10234, CurrentEstablished: 7, RetransSegments: 1523, ListenOverflows: &overflows},
+			UDP:     domain.UDPStats{InDatagrams: 120345, InErrors: 7, ReceiveBufferErrors: 5},
+			Sockets: domain.SocketStats{Used: 291, TCPInUse: 9, TCPMemory: 12288, TCP6InUse: &tcp6},
+		},
+	}
+
+	svc := NewNetworkProtocolService(mockPort)
+
+	result, err := svc.GetProtocols()
+	assert.NoError(t, err)
+	assert.Equal(t, mockPort.mockResult, result)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+	assert.Equal(t, "tcp", samples[0].Measurement)
+	assert.Equal(t, uint64(1523), samples[0].Fields["retrans_segments"])
+	assert.Equal(t, uint64(17), samples[0].Fields["listen_overflows"])
+	// Missing from /proc/net/netstat
+	assert.NotContains(t, samples[0].Fields, "timeouts")
+	assert.Equal(t, "udp", samples[1].Measurement)
+	assert.Equal(t, uint64(5), samples[1].Fields["receive_buffer_errors"])
+	assert.Equal(t, "sockets", samples[2].Measurement)
+	assert.Equal(t, uint64(12288), samples[2].Fields["tcp_memory"])
+	assert.Equal(t, uint64(4), samples[2].Fields["tcp6_inuse"])
+	assert.NotContains(t, samples[2].Fields, "udp6_inuse")
+}
+
+func TestGetNetworkProtocolsSimulateError(t *testing.T) {
+
+	mockPort := &mockNetworkProtocolPort{
+		mockError: errors.New("mock error"),
+	}
+
+	svc := NewNetworkProtocolService(mockPort)
+
+	_, err := svc.GetProtocols()
+	assert.Error(t, err)
+	assert.Equal(t, "mock error", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
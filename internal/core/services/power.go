@@ -0,0 +1,91 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// PowerService provides business logic related to the power supply.
+// Acts as a middleman between the core domain model (Power) and the outside
+type PowerService struct {
+	powerPort ports.PowerPort
+	recorder  ports.RunRecorderPort
+}
+
+// Service constructor
+func NewPowerService(powerPort ports.PowerPort) *PowerService {
+	return &PowerService{powerPort: powerPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *PowerService) WithRecorder(recorder ports.RunRecorderPort) *PowerService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the power supplies and throttling status
+func (s *PowerService) GetPower() (domain.Power, error) {
+	return track(s.recorder, "power", s.powerPort.GetPower)
+}
+
+// Samples expresses every power supply as a sample, and the throttling
+// status as another when known
+func (s *PowerService) Samples() ([]domain.Sample, error) {
+	power, err := s.GetPower()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := []domain.Sample{}
+	if status := power.Throttled; status != nil {
+		samples = append(samples, domain.Sample{
+			Measurement: "throttled",
+			Fields: map[string]any{
+				"under_voltage":            status.UnderVoltage,
+				"freq_capped":              status.FreqCapped,
+				"throttled":                status.Throttled,
+				"soft_temp_limit":          status.SoftTempLimit,
+				"under_voltage_occurred":   status.UnderVoltageOccurred,
+				"freq_capped_occurred":     status.FreqCappedOccurred,
+				"throttled_occurred":       status.ThrottledOccurred,
+				"soft_temp_limit_occurred": status.SoftTempLimitOccurred,
+			},
+		})
+	}
+
+	for _, supply := range power.Supplies {
+		fields := map[string]any{}
+		if supply.Status != "" {
+			fields["status"] = supply.Status
+		}
+		if supply.Online != nil {
+			fields["online"] = *supply.Online
+		}
+		if supply.Capacity != nil {
+			fields["capacity"] = int64(*supply.Capacity)
+		}
+		if supply.Voltage != nil {
+			fields["voltage"] = *supply.Voltage
+		}
+		if supply.Current != nil {
+			fields["current"] = *supply.Current
+		}
+		if supply.Power != nil {
+			fields["power"] = *supply.Power
+		}
+		// A sample needs at least one field
+		if len(fields) == 0 {
+			continue
+		}
+
+		samples = append(samples, domain.Sample{
+			Measurement: "power_supply",
+			Tags: map[string]string{
+				"supply": supply.Name,
+				"type":   supply.Type,
+			},
+			Fields: fields,
+		})
+	}
+	return samples, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPowerPort struct {
+	mockResult domain.Power
+	mockError  error
+}
+
+func (m *mockPowerPort) GetPower() (domain.Power, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetPowerValues(t *testing.T) {
+
+	online := true
+	capacity := 87
+	voltage := 4.012
+	mockPort := &mockPowerPort{
+		mockResult: domain.Power{
+			Supplies: []domain.PowerSupply{
+				{Name: "ups-hat", Type: "Battery", Status: "Discharging", Capacity: &capacity, Voltage: &voltage},
+				{Name: "AC", Type: "Mains", Online: &online},
+				{Name: "usb", Type: "USB"},
+			},
+			Throttled: &domain.ThrottledStatus{
+				Raw: "0x50005", UnderVoltage: true, Throttled: true,
+				UnderVoltageOccurred: true, ThrottledOccurred: true,
+				Flags: []string{"under-voltage", "throttled", "under-voltage-occurred", "throttled-occurred"},
+			},
+		},
+	}
+
+	svc := NewPowerService(mockPort)
+
+	result, err := svc.GetPower()
+	assert.NoError(t, err)
+	assert.Equal(t, mockPort.mockResult, result)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+
+	// A supply without values has no sample
+	assert.Len(t, samples, 3)
+	assert.Equal(t, "throttled", samples[0].Measurement)
+	assert.Equal(t, true, samples[0].Fields["under_voltage"])
+	assert.Equal(t, false, samples[0].Fields["freq_capped"])
+	assert.Equal(t, true, samples[0].Fields["throttled_occurred"])
+	assert.Equal(t, "power_supply", samples[1].Measurement)
+	assert.Equal(t, map[string]string{"supply": "ups-hat", "type": "Battery"}, samples[1].Tags)
+	assert.Equal(t, int64(87), samples[1].Fields["capacity"])
+	assert.Equal(t, 4.012, samples[1].Fields["voltage"])
+	assert.Equal(t, map[string]any{"online": true}, samples[2].Fields)
+}
+
+func TestGetPowerWithoutThrottling(t *testing.T) {
+
+	mockPort := &mockPowerPort{
+		mockResult: domain.Power{Supplies: []domain.PowerSupply{}},
+	}
+
+	svc := NewPowerService(mockPort)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Empty(t, samples)
+}
+
+func TestGetPowerSimulateError(t *testing.T) {
+
+	mockPort := &mockPowerPort{
+		mockError: errors.New("mock error"),
+	}
+
+	svc := NewPowerService(mockPort)
+
+	_, err := svc.GetPower()
+	assert.Error(t, err)
+	assert.Equal(t, "mock error", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
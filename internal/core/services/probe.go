@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+type probeOutcome struct {
+	at      time.Time
+	success bool
+	latency time.Duration
+}
+
+// probeHistory is the window of the latest attempts of a probe
+type probeHistory struct {
+	outcomes    []probeOutcome
+	lastAttempt time.Time
+	lastSuccess time.Time
+	detail      string
+	err         string
+}
+
+/* ******************************************** PROBE ******************************************** */
+
+// ProbeService provides business logic related to the connectivity probes.
+// Acts as a middleman between the core domain model (Probe) and the outside
+type ProbeService struct {
+	probePort ports.ProbePort
+	targets   []domain.ProbeTarget
+	window    int
+	recorder  ports.RunRecorderPort
+	now       func() time.Time
+
+	mutex     sync.Mutex
+	histories map[string]*probeHistory // Keyed by name
+}
+
+// Service constructor. Probes are run by Run or Check, and loss and latency
+// are computed over the latest window attempts of each.
+func NewProbeService(probePort ports.ProbePort, targets []domain.ProbeTarget, window int) *ProbeService {
+	return &ProbeService{
+		probePort: probePort,
+		targets:   targets,
+		window:    window,
+		now:       time.Now,
+		histories: make(map[string]*probeHistory),
+	}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *ProbeService) WithRecorder(recorder ports.RunRecorderPort) *ProbeService {
+	s.recorder = recorder
+	return s
+}
+
+// Check runs the probe of the target once. Probes the host doesn't permit
+// keep their error, but don't count as lost.
+func (s *ProbeService) Check(target domain.ProbeTarget) {
+	attempt, err := s.probePort.Probe(target)
+	at := s.now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	history := s.histories[target.Name]
+	if history == nil {
+		history = &probeHistory{}
+		s.histories[target.Name] = history
+	}
+	// Only changes are logged, not every failed attempt
+	switch {
+	case err != nil && (history.err == "" || history.lastAttempt.IsZero()):
+		log.Printf("Probe %s failed: %v", target.Name, err)
+	case err == nil && history.err != "":
+		log.Printf("Probe %s recovered", target.Name)
+	}
+
+	history.lastAttempt = at
+	history.err = ""
+	if err != nil {
+		history.err = err.Error()
+		if errors.Is(err, domain.ErrProbeNotPermitted) {
+			return
+		}
+	} else {
+		history.lastSuccess = at
+		history.detail = attempt.Detail
+	}
+
+	history.outcomes = append(history.outcomes, probeOutcome{at: at, success: err == nil, latency: attempt.Latency})
+	if len(history.outcomes) > s.window {
+		history.outcomes = history.outcomes[len(history.outcomes)-s.window:]
+	}
+}
+
+// Run checks every probe right away and then every interval of its own,
+// until the context is cancelled
+func (s *ProbeService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, target := range s.targets {
+		wg.Add(1)
+		go func(target domain.ProbeTarget) {
+			defer wg.Done()
+			s.Check(target)
+
+			ticker := time.NewTicker(target.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.Check(target)
+				}
+			}
+		}(target)
+	}
+	wg.Wait()
+}
+
+// Business logic to get the latest state of every probe, in the order of the
+// configuration
+func (s *ProbeService) GetProbes() ([]domain.Probe, error) {
+	return track(s.recorder, "probes", func() ([]domain.Probe, error) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		probes := make([]domain.Probe, 0, len(s.targets))
+		for _, target := range s.targets {
+			probe := domain.Probe{
+				Name:     target.Name,
+				Type:     target.Type,
+				Target:   target.Target,
+				Interval: target.Interval.Seconds(),
+			}
+			if history := s.histories[target.Name]; history != nil {
+				fillProbe(&probe, history)
+			}
+			probes = append(probes, probe)
+		}
+		return probes, nil
+	})
+}
+
+// fillProbe summarizes the attempts of the window
+func fillProbe(probe *domain.Probe, history *probeHistory) {
+	probe.Detail = history.detail
+	probe.Error = history.err
+	lastAttempt := history.lastAttempt
+	probe.LastAttempt = &lastAttempt
+	if !history.lastSuccess.IsZero() {
+		lastSuccess := history.lastSuccess
+		probe.LastSuccess = &lastSuccess
+	}
+
+	var total time.Duration
+	successes := 0
+	for _, outcome := range history.outcomes {
+		if !outcome.success {
+			continue
+		}
+		total += outcome.latency
+		successes++
+	}
+	probe.Attempts = len(history.outcomes)
+	probe.Failures = probe.Attempts - successes
+	if probe.Attempts == 0 {
+		return
+	}
+	probe.Loss = float64(probe.Failures) * 100 / float64(probe.Attempts)
+
+	probe.Up = history.err == ""
+	if successes > 0 {
+		average := total.Seconds() / float64(successes)
+		probe.AverageLatency = &average
+	}
+	for i := len(history.outcomes) - 1; i >= 0; i-- {
+		if history.outcomes[i].success {
+			latency := history.outcomes[i].latency.Seconds()
+			probe.Latency = &latency
+			break
+		}
+	}
+}
+
+// Samples expresses every probe attempted as a sample. Those not run yet, or
+// not permitted, are left out.
+func (s *ProbeService) Samples() ([]domain.Sample, error) {
+	probes, err := s.GetProbes()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(probes))
+	for _, probe := range probes {
+		if probe.Attempts == 0 {
+			continue
+		}
+		fields := map[string]any{
+			"up":   probe.Up,
+			"loss": probe.Loss,
+		}
+		if probe.Latency != nil {
+			fields["latency"] = *probe.Latency
+		}
+
+		samples = append(samples, domain.Sample{
+			Measurement: "probe",
+			Tags:        map[string]string{"name": probe.Name, "type": probe.Type},
+			Fields:      fields,
+		})
+	}
+	return samples, nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockProbePort replies to each probe with its scripted outcomes in turn,
+// repeating the last one
+type mockProbePort struct {
+	mutex    sync.Mutex
+	outcomes map[string][]error
+	latency  time.Duration
+	calls    map[string]int
+}
+
+func (m *mockProbePort) Probe(target domain.ProbeTarget) (domain.ProbeAttempt, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.calls == nil {
+		m.calls = map[string]int{}
+	}
+	outcomes := m.outcomes[target.Name]
+	err := outcomes[min(m.calls[target.Name], len(outcomes)-1)]
+	m.calls[target.Name]++
+	if err != nil {
+		return domain.ProbeAttempt{}, err
+	}
+	return domain.ProbeAttempt{Latency: m.latency, Detail: "connected to " + target.Target}, nil
+}
+
+func TestGetProbesValues(t *testing.T) {
+
+	timeout := errors.New("dial tcp 10.0.0.5:22: i/o timeout")
+	mockPort := &mockProbePort{
+		outcomes: map[string][]error{
+			"ssh":     {nil, timeout, nil, nil, timeout},
+			"gateway": {fmt.Errorf("%w: ping sockets aren't allowed", domain.ErrProbeNotPermitted)},
+		},
+		latency: 20 * time.Millisecond,
+	}
+	targets := []domain.ProbeTarget{
+		{Name: "ssh", Type: domain.ProbeTCP, Target: "10.0.0.5:22", Interval: time.Minute, Timeout: time.Second},
+		{Name: "gateway", Type: domain.ProbeICMP, Target: "192.168.1.1", Interval: time.Minute, Timeout: time.Second},
+		{Name: "backend", Type: domain.ProbeHTTP, Target: "https://backend/health", Interval: time.Minute, Timeout: time.Second},
+	}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := start
+
+	svc := NewProbeService(mockPort, targets, 4)
+	svc.now = func() time.Time { return at }
+
+	// Nothing is reported until the first attempt
+	result, err := svc.GetProbes()
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Nil(t, result[0].LastAttempt)
+	assert.Equal(t, 60.0, result[0].Interval)
+
+	for i := 0; i < 5; i++ {
+		at = start.Add(time.Duration(i) * time.Minute)
+		svc.Check(targets[0])
+		svc.Check(targets[1])
+	}
+
+	result, err = svc.GetProbes()
+	assert.NoError(t, err)
+
+	// The first attempt is out of the window
+	ssh := result[0]
+	assert.False(t, ssh.Up)
+	assert.Equal(t, 4, ssh.Attempts)
+	assert.Equal(t, 2, ssh.Failures)
+	assert.Equal(t, 50.0, ssh.Loss)
+	assert.Equal(t, 0.02, *ssh.Latency)
+	assert.Equal(t, 0.02, *ssh.AverageLatency)
+	assert.Equal(t, start.Add(4*time.Minute), *ssh.LastAttempt)
+	assert.Equal(t, start.Add(3*time.Minute), *ssh.LastSuccess)
+	assert.Equal(t, "connected to 10.0.0.5:22", ssh.Detail)
+	assert.Equal(t, timeout.Error(), ssh.Error)
+
+	// Not permitted, without counting as lost
+	gateway := result[1]
+	assert.Equal(t, 0, gateway.Attempts)
+	assert.Equal(t, 0.0, gateway.Loss)
+	assert.Nil(t, gateway.LastSuccess)
+	assert.Nil(t, gateway.Latency)
+	assert.Contains(t, gateway.Error, "ping sockets aren't allowed")
+
+	assert.Nil(t, result[2].LastAttempt)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Sample{
+		{
+			Measurement: "probe",
+			Tags:        map[string]string{"name": "ssh", "type": "tcp"},
+			Fields:      map[string]any{"up": false, "loss": 50.0, "latency": 0.02},
+		},
+	}, samples)
+}
+
+func TestGetProbesRun(t *testing.T) {
+
+	mockPort := &mockProbePort{outcomes: map[string][]error{"ssh": {nil}, "dns": {nil}}}
+	targets := []domain.ProbeTarget{
+		{Name: "ssh", Type: domain.ProbeTCP, Target: "10.0.0.5:22", Interval: time.Millisecond, Timeout: time.Second},
+		{Name: "dns", Type: domain.ProbeDNS, Target: "example.com", Interval: time.Hour, Timeout: time.Second},
+	}
+
+	svc := NewProbeService(mockPort, targets, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.Run(ctx)
+	}()
+
+	// Each probe runs on its own schedule
+	assert.Eventually(t, func() bool {
+		result, _ := svc.GetProbes()
+		return result[0].Attempts >= 3 && result[1].Attempts == 1
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+
+	result, err := svc.GetProbes()
+	assert.NoError(t, err)
+	assert.True(t, result[0].Up)
+	assert.Equal(t, 1, result[1].Attempts)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// ProcessService provides business logic related to the running processes.
+// Acts as a middleman between the core domain model (Process) and the outside
+type ProcessService struct {
+	processPort ports.ProcessPort
+	recorder    ports.RunRecorderPort
+}
+
+// Service constructor
+func NewProcessService(processPort ports.ProcessPort) *ProcessService {
+	return &ProcessService{processPort: processPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *ProcessService) WithRecorder(recorder ports.RunRecorderPort) *ProcessService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get every running process
+func (s *ProcessService) GetProcesses() ([]domain.Process, error) {
+	return track(s.recorder, "processes", s.processPort.GetProcesses)
+}
+
+// Business logic to get the heaviest processes. Ties are listed by PID.
+func (s *ProcessService) GetTopProcesses(sortBy string, limit int) ([]domain.Process, error) {
+	if !slices.Contains(domain.ProcessSortKeys, sortBy) {
+		return nil, fmt.Errorf("unknown sort key %q", sortBy)
+	}
+
+	processes, err := s.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	less := map[string]func(a, b domain.Process) bool{
+		"cpu":     func(a, b domain.Process) bool { return a.CPUPercent > b.CPUPercent },
+		"memory":  func(a, b domain.Process) bool { return a.RSS > b.RSS },
+		"threads": func(a, b domain.Process) bool { return a.Threads > b.Threads },
+		"pid":     func(a, b domain.Process) bool { return false },
+		"name":    func(a, b domain.Process) bool { return a.Name < b.Name },
+	}[sortBy]
+
+	sort.SliceStable(processes, func(i, j int) bool { return processes[i].PID < processes[j].PID })
+	sort.SliceStable(processes, func(i, j int) bool { return less(processes[i], processes[j]) })
+
+	if limit > 0 && limit < len(processes) {
+		processes = processes[:limit]
+	}
+	return processes, nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProcessPort struct {
+	mockResult []domain.Process
+	mockError  error
+}
+
+func (m *mockProcessPort) GetProcesses() ([]domain.Process, error) {
+	// Return a copy, as the service sorts it
+	return append([]domain.Process(nil), m.mockResult...), m.mockError
+}
+
+func pids(processes []domain.Process) []int {
+	result := []int{}
+	for _, process := range processes {
+		result = append(result, process.PID)
+	}
+	return result
+}
+
+func TestGetTopProcessesValues(t *testing.T) {
+
+	mockPort := &mockProcessPort{
+		mockResult: []domain.Process{
+			{PID: 30, Name: "influxd", CPUPercent: 12.5, RSS: 150 << 20, Threads: 12},
+			{PID: 1, Name: "systemd", CPUPercent: 0, RSS: 11 << 20, Threads: 1},
+			{PID: 512, Name: "python3", CPUPercent: 95, RSS: 52 << 20, Threads: 4},
+			{PID: 7, Name: "sshd", CPUPercent: 0, RSS: 8 << 20, Threads: 1},
+		},
+	}
+
+	svc := NewProcessService(mockPort)
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - CPU, ties by PID": {"sort": "cpu", "limit": 0, "expected": []int{512, 30, 1, 7}},
+		"Case 2 - Memory limited":   {"sort": "memory", "limit": 2, "expected": []int{30, 512}},
+		"Case 3 - Threads":          {"sort": "threads", "limit": 3, "expected": []int{30, 512, 1}},
+		"Case 4 - PID":              {"sort": "pid", "limit": 10, "expected": []int{1, 7, 30, 512}},
+		"Case 5 - Name":             {"sort": "name", "limit": 0, "expected": []int{30, 512, 7, 1}},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		result, err := svc.GetTopProcesses(caseData["sort"].(string), caseData["limit"].(int))
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"].([]int), pids(result))
+	}
+
+	_, err := svc.GetTopProcesses("size", 10)
+	assert.Error(t, err)
+}
+
+func TestGetTopProcessesSimulateError(t *testing.T) {
+
+	mockPort := &mockProcessPort{
+		mockError: errors.New("unable to list /proc"),
+	}
+
+	svc := NewProcessService(mockPort)
+
+	_, err := svc.GetTopProcesses("cpu", 10)
+	assert.Error(t, err)
+	assert.Equal(t, "unable to list /proc", err.Error())
+}
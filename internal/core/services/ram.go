@@ -8,7 +8,8 @@ import (
 // RAMService provides business logic related to RAM operations.
 // Acts as a middleman between the core domain model (RAM) and the outside
 type RAMService struct {
-	ramPort ports.RAMPort
+	ramPort  ports.RAMPort
+	recorder ports.RunRecorderPort
 }
 
 // Service constructor
@@ -16,7 +17,32 @@ func NewRAMService(ramPort ports.RAMPort) *RAMService {
 	return &RAMService{ramPort: ramPort}
 }
 
+// WithRecorder reports every run of the service to the recorder
+func (s *RAMService) WithRecorder(recorder ports.RunRecorderPort) *RAMService {
+	s.recorder = recorder
+	return s
+}
+
 // Business logic to get the RAM stats
 func (s *RAMService) GetRAMStats() (domain.RAM, error) {
-	return s.ramPort.GetRAMStats()
+	return track(s.recorder, "ram", s.ramPort.GetRAMStats)
+}
+
+// Samples expresses the RAM stats as metrics samples
+func (s *RAMService) Samples() ([]domain.Sample, error) {
+	ram, err := s.GetRAMStats()
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.Sample{{
+		Measurement: "ram",
+		Tags:        map[string]string{},
+		Fields: map[string]any{
+			"total":     ram.Total,
+			"available": ram.Available,
+			"free":      ram.Free,
+			"used":      ram.Used,
+		},
+	}}, nil
 }
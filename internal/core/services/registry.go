@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+)
+
+// CollectFunc returns the current value of a collector
+type CollectFunc func() (any, error)
+
+// CollectorRegistry keeps every collector under a name, so that features
+// working on all of them, like streaming, don't need to know each service.
+// It also keeps the stats of their runs, as the recorder of the services.
+type CollectorRegistry struct {
+	mu         sync.RWMutex
+	names      []string
+	collectors map[string]CollectFunc
+	stats      map[string]domain.CollectorStats
+}
+
+// Registry constructor
+func NewCollectorRegistry() *CollectorRegistry {
+	return &CollectorRegistry{
+		collectors: make(map[string]CollectFunc),
+		stats:      make(map[string]domain.CollectorStats),
+	}
+}
+
+// Register adds a collector, replacing any other with the same name
+func (r *CollectorRegistry) Register(name string, fn CollectFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.collectors[name]; !exists {
+		r.names = append(r.names, name)
+	}
+	r.collectors[name] = fn
+}
+
+// Names returns the registered collectors in registration order
+func (r *CollectorRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.names...)
+}
+
+func (r *CollectorRegistry) Collect(name string) (any, error) {
+	r.mu.RLock()
+	fn, exists := r.collectors[name]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown collector %q", name)
+	}
+	return fn()
+}
+
+// Record accounts for a run of a collector which started at start. The last
+// error is kept after later successful runs, to tell when it failed.
+func (r *CollectorRegistry) Record(name string, start time.Time, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.stats[name]
+	stats.Runs++
+	stats.LastRun = start
+	stats.LastDuration = time.Since(start)
+	if err != nil {
+		stats.Failures++
+		stats.LastError = err.Error()
+		stats.LastErrorAt = &start
+	}
+	r.stats[name] = stats
+}
+
+// Stats returns the stats of the runs of a collector
+func (r *CollectorRegistry) Stats(name string) domain.CollectorStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.stats[name]
+}
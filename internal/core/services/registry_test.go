@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectorRegistry(t *testing.T) {
+
+	cpuSvc := NewCPUService(&mockCPUPort{mockResult: domain.CPU{LoadAvg1Min: 0.5}})
+	ramSvc := NewRAMService(&mockRAMPort{mockError: errors.New("unable to read RAM stats")})
+
+	registry := NewCollectorRegistry()
+	registry.Register("cpu", func() (any, error) { return cpuSvc.GetCPULoad() })
+	registry.Register("ram", func() (any, error) { return ramSvc.GetRAMStats() })
+	registry.Register("cpu", func() (any, error) { return cpuSvc.GetCPULoad() })
+
+	assert.Equal(t, []string{"cpu", "ram"}, registry.Names())
+
+	result, err := registry.Collect("cpu")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CPU{LoadAvg1Min: 0.5}, result)
+
+	_, err = registry.Collect("ram")
+	assert.EqualError(t, err, "unable to read RAM stats")
+
+	_, err = registry.Collect("gpu")
+	assert.Error(t, err)
+}
+
+func TestCollectorRegistryRecord(t *testing.T) {
+
+	registry := NewCollectorRegistry()
+	registry.Record("ram", time.Now(), nil)
+	assert.Nil(t, registry.Stats("ram").LastErrorAt)
+
+	failed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	registry.Record("ram", failed, errors.New("unable to read RAM stats"))
+	registry.Record("ram", failed.Add(time.Minute), nil)
+
+	// The error survives the later success
+	stats := registry.Stats("ram")
+	assert.Equal(t, uint64(3), stats.Runs)
+	assert.Equal(t, uint64(1), stats.Failures)
+	assert.Equal(t, failed.Add(time.Minute), stats.LastRun)
+	assert.Equal(t, "unable to read RAM stats", stats.LastError)
+	assert.Equal(t, failed, *stats.LastErrorAt)
+
+	// Until another error replaces it
+	registry.Record("ram", failed.Add(2*time.Minute), errors.New("/proc/meminfo not found"))
+	stats = registry.Stats("ram")
+	assert.Equal(t, "/proc/meminfo not found", stats.LastError)
+	assert.Equal(t, failed.Add(2*time.Minute), *stats.LastErrorAt)
+
+	assert.Equal(t, domain.CollectorStats{}, registry.Stats("cpu"))
+}
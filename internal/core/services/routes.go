@@ -0,0 +1,51 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// RouteService provides business logic related to routing and name resolution.
+// Acts as a middleman between the core domain model (Routing) and the outside
+type RouteService struct {
+	routePort ports.RoutePort
+	recorder  ports.RunRecorderPort
+}
+
+// Service constructor
+func NewRouteService(routePort ports.RoutePort) *RouteService {
+	return &RouteService{routePort: routePort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *RouteService) WithRecorder(recorder ports.RunRecorderPort) *RouteService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the routes, the default gateways and the resolver
+func (s *RouteService) GetRouting() (domain.Routing, error) {
+	return track(s.recorder, "routes", s.routePort.GetRouting)
+}
+
+// Samples expresses the routing as a single sample, whose default_gateway
+// field can be alerted on
+func (s *RouteService) Samples() ([]domain.Sample, error) {
+	routing, err := s.GetRouting()
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.Sample{
+		{
+			Measurement: "routing",
+			Tags:        map[string]string{},
+			Fields: map[string]any{
+				"routes":           len(routing.Routes),
+				"default_gateway":  routing.DefaultGateway != nil,
+				"default_gateway6": routing.DefaultGateway6 != nil,
+				"nameservers":      len(routing.Resolver.Nameservers),
+			},
+		},
+	}, nil
+}
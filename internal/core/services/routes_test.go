@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRoutePort struct {
+	mockResult domain.Routing
+	mockError  error
+}
+
+func (m *mockRoutePort) GetRouting() (domain.Routing, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetRoutingValues(t *testing.T) {
+
+	defaultRoute := domain.Route{Family: "inet", Destination: "0.0.0.0/0", Gateway: "192.168.1.1", Interface: "eth0", Metric: 100, Default: true}
+	mockPort := &mockRoutePort{
+		mockResult: domain.Routing{
+			Routes: []domain.Route{
+				defaultRoute,
+				{Family: "inet", Destination: "192.168.1.0/24", Interface: "eth0", Metric: 100},
+			},
+			DefaultGateway: &defaultRoute,
+			Resolver:       domain.Resolver{Nameservers: []string{"192.168.1.1"}, Search: []string{"home.lan"}, Options: []string{}},
+		},
+	}
+
+	svc := NewRouteService(mockPort)
+
+	result, err := svc.GetRouting()
+	assert.NoError(t, err)
+	assert.Equal(t, mockPort.mockResult, result)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 1)
+	assert.Equal(t, "routing", samples[0].Measurement)
+	assert.Equal(t, map[string]any{
+		"routes":           2,
+		"default_gateway":  true,
+		"default_gateway6": false,
+		"nameservers":      1,
+	}, samples[0].Fields)
+}
+
+func TestGetRoutingSimulateError(t *testing.T) {
+
+	mockPort := &mockRoutePort{
+		mockError: errors.New("mock error"),
+	}
+
+	svc := NewRouteService(mockPort)
+
+	_, err := svc.GetRouting()
+	assert.Error(t, err)
+	assert.Equal(t, "mock error", err.Error())
+}
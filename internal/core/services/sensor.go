@@ -0,0 +1,60 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// SensorService provides business logic related to the hwmon sensors.
+// Acts as a middleman between the core domain model (Sensor) and the outside
+type SensorService struct {
+	sensorPort ports.SensorPort
+	recorder   ports.RunRecorderPort
+}
+
+// Service constructor
+func NewSensorService(sensorPort ports.SensorPort) *SensorService {
+	return &SensorService{sensorPort: sensorPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *SensorService) WithRecorder(recorder ports.RunRecorderPort) *SensorService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the readings of the sensors
+func (s *SensorService) GetSensors() ([]domain.Sensor, error) {
+	return track(s.recorder, "sensors", s.sensorPort.GetSensors)
+}
+
+// Samples expresses every sensor reading as a sample
+func (s *SensorService) Samples() ([]domain.Sample, error) {
+	sensors, err := s.GetSensors()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(sensors))
+	for _, sensor := range sensors {
+		tags := map[string]string{
+			"chip":   sensor.Chip,
+			"device": sensor.Device,
+			"sensor": sensor.Name,
+			"type":   sensor.Type,
+			"unit":   sensor.Unit,
+		}
+		if sensor.Label != "" {
+			tags["label"] = sensor.Label
+		}
+
+		samples = append(samples, domain.Sample{
+			Measurement: "sensor",
+			Tags:        tags,
+			Fields: map[string]any{
+				"value": sensor.Value,
+			},
+		})
+	}
+	return samples, nil
+}
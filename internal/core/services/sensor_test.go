@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSensorPort struct {
+	mockResult []domain.Sensor
+	mockError  error
+}
+
+func (m *mockSensorPort) GetSensors() ([]domain.Sensor, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetSensorsValues(t *testing.T) {
+
+	mockPort := &mockSensorPort{
+		mockResult: []domain.Sensor{
+			{Chip: "cpu_thermal", Device: "hwmon0", Name: "temp1", Type: "temperature", Value: 52.35, Unit: "°C"},
+			{Chip: "coretemp", Device: "hwmon1", Name: "temp2", Label: "Core 0", Type: "temperature", Value: 43, Unit: "°C"},
+		},
+	}
+
+	svc := NewSensorService(mockPort)
+
+	result, err := svc.GetSensors()
+	assert.NoError(t, err)
+	assert.Equal(t, mockPort.mockResult, result)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, "sensor", samples[0].Measurement)
+	assert.Equal(t, map[string]string{
+		"chip": "cpu_thermal", "device": "hwmon0", "sensor": "temp1", "type": "temperature", "unit": "°C",
+	}, samples[0].Tags)
+	assert.Equal(t, map[string]any{"value": 52.35}, samples[0].Fields)
+	assert.Equal(t, "Core 0", samples[1].Tags["label"])
+}
+
+func TestGetSensorsSimulateError(t *testing.T) {
+
+	mockPort := &mockSensorPort{
+		mockError: errors.New("hwmon class not found"),
+	}
+
+	svc := NewSensorService(mockPort)
+
+	_, err := svc.GetSensors()
+	assert.Error(t, err)
+	assert.Equal(t, "hwmon class not found", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// SocketService provides business logic related to the socket table.
+// Acts as a middleman between the core domain model (Socket) and the outside
+type SocketService struct {
+	socketPort ports.SocketPort
+	recorder   ports.RunRecorderPort
+}
+
+// Service constructor
+func NewSocketService(socketPort ports.SocketPort) *SocketService {
+	return &SocketService{socketPort: socketPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *SocketService) WithRecorder(recorder ports.RunRecorderPort) *SocketService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the sockets selected by the filter, in any of its
+// states and with its port on either end
+func (s *SocketService) GetSockets(filter domain.SocketFilter) ([]domain.Socket, error) {
+	for _, state := range filter.States {
+		if !slices.Contains(domain.SocketStates, state) {
+			return nil, fmt.Errorf("unknown socket state %q", state)
+		}
+	}
+
+	sockets, err := track(s.recorder, "sockets", s.socketPort.GetSockets)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := []domain.Socket{}
+	for _, socket := range sockets {
+		if len(filter.States) > 0 && !slices.Contains(filter.States, socket.State) {
+			continue
+		}
+		if filter.Port != 0 && socket.LocalPort != filter.Port && socket.RemotePort != filter.Port {
+			continue
+		}
+		selected = append(selected, socket)
+	}
+	return selected, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSocketPort struct {
+	mockResult []domain.Socket
+	mockError  error
+}
+
+func (m *mockSocketPort) GetSockets() ([]domain.Socket, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetSocketsValues(t *testing.T) {
+
+	sshListen := domain.Socket{Protocol: "tcp", LocalAddress: "0.0.0.0", LocalPort: 22, State: "LISTEN"}
+	sshSession := domain.Socket{Protocol: "tcp", LocalAddress: "192.168.1.15", LocalPort: 22, RemoteAddress: "192.168.1.100", RemotePort: 54321, State: "ESTABLISHED"}
+	https := domain.Socket{Protocol: "tcp", LocalAddress: "192.168.1.15", LocalPort: 40000, RemoteAddress: "8.8.8.8", RemotePort: 443, State: "ESTABLISHED"}
+	dns := domain.Socket{Protocol: "udp", LocalAddress: "127.0.0.1", LocalPort: 53, State: "UNCONN"}
+
+	mockPort := &mockSocketPort{mockResult: []domain.Socket{sshListen, sshSession, https, dns}}
+
+	svc := NewSocketService(mockPort)
+
+	testBattery := map[string]map[string]any{
+		"Case 1 - Everything":  {"filter": domain.SocketFilter{}, "expected": []domain.Socket{sshListen, sshSession, https, dns}},
+		"Case 2 - Listening":   {"filter": domain.SocketFilter{States: []string{"LISTEN", "UNCONN"}}, "expected": []domain.Socket{sshListen, dns}},
+		"Case 3 - Local port":  {"filter": domain.SocketFilter{Port: 22}, "expected": []domain.Socket{sshListen, sshSession}},
+		"Case 4 - Remote port": {"filter": domain.SocketFilter{Port: 443}, "expected": []domain.Socket{https}},
+		"Case 5 - Both":        {"filter": domain.SocketFilter{States: []string{"ESTABLISHED"}, Port: 22}, "expected": []domain.Socket{sshSession}},
+		"Case 6 - None":        {"filter": domain.SocketFilter{States: []string{"TIME_WAIT"}}, "expected": []domain.Socket{}},
+	}
+
+	for caseName, caseData := range testBattery {
+		t.Log(caseName)
+
+		result, err := svc.GetSockets(caseData["filter"].(domain.SocketFilter))
+		assert.NoError(t, err)
+		assert.Equal(t, caseData["expected"], result)
+	}
+
+	_, err := svc.GetSockets(domain.SocketFilter{States: []string{"listen"}})
+	assert.EqualError(t, err, `unknown socket state "listen"`)
+}
+
+func TestGetSocketsSimulateError(t *testing.T) {
+
+	mockPort := &mockSocketPort{
+		mockError: errors.New("mock error"),
+	}
+
+	svc := NewSocketService(mockPort)
+
+	_, err := svc.GetSockets(domain.SocketFilter{})
+	assert.Error(t, err)
+	assert.Equal(t, "mock error", err.Error())
+}
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"log"
+	"sync"
+	"time"
+
 	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
 	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
 )
@@ -9,14 +13,169 @@ import (
 // Acts as a middleman between the core domain model (Storage) and the outside
 type StorageService struct {
 	storagePort ports.StoragePort
+	expectedRW  map[string]bool
+	recorder    ports.RunRecorderPort
+
+	// Health is cached, as smartctl wakes spun-down disks
+	healthPort ports.StorageHealthPort
+	healthTTL  time.Duration
+	now        func() time.Time
+	healthMu   sync.Mutex
+	health     map[string]domain.DeviceHealth // Keyed by device name
+	healthAt   time.Time
+}
+
+// Service constructor. Partitions mounted read-only at any of the expectedRW
+// mount points are flagged.
+func NewStorageService(storagePort ports.StoragePort, expectedRW []string) *StorageService {
+	expected := make(map[string]bool, len(expectedRW))
+	for _, mountPoint := range expectedRW {
+		expected[mountPoint] = true
+	}
+	return &StorageService{storagePort: storagePort, expectedRW: expected, now: time.Now}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *StorageService) WithRecorder(recorder ports.RunRecorderPort) *StorageService {
+	s.recorder = recorder
+	return s
 }
 
-// Service constructor
-func NewStorageService(storagePort ports.StoragePort) *StorageService {
-	return &StorageService{storagePort: storagePort}
+// WithHealth attaches the health of every device reported by the health port
+// to the devices with the same name. Health is read again only once it's
+// older than the ttl.
+func (s *StorageService) WithHealth(healthPort ports.StorageHealthPort, ttl time.Duration) *StorageService {
+	s.healthPort = healthPort
+	s.healthTTL = ttl
+	return s
 }
 
-// Business logic to get the storage devices
+// Business logic to get the storage devices, with their health when known
 func (s *StorageService) GetDevices() ([]domain.Device, error) {
-	return s.storagePort.GetDevices()
+	devices, err := track(s.recorder, "storage", s.getDevices)
+	if err != nil || s.healthPort == nil {
+		return devices, err
+	}
+
+	health := s.cachedHealth()
+	for i := range devices {
+		if deviceHealth, ok := health[devices[i].Name]; ok {
+			devices[i].Health = &deviceHealth
+		}
+	}
+	return devices, nil
+}
+
+// cachedHealth reads the health of the devices when the cache has expired.
+// A failed read keeps the previous health, and isn't retried before the ttl.
+func (s *StorageService) cachedHealth() map[string]domain.DeviceHealth {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	now := s.now()
+	if !s.healthAt.IsZero() && now.Sub(s.healthAt) < s.healthTTL {
+		return s.health
+	}
+	s.healthAt = now
+
+	// Devices are still worth reporting without their health
+	devices, err := s.healthPort.GetDevicesHealth()
+	if err != nil {
+		log.Printf("Storage health not attached: %v", err)
+		return s.health
+	}
+	s.health = make(map[string]domain.DeviceHealth, len(devices))
+	for _, device := range devices {
+		s.health[device.Device] = device
+	}
+	return s.health
+}
+
+func (s *StorageService) getDevices() ([]domain.Device, error) {
+	devices, err := s.storagePort.GetDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, device := range devices {
+		for key, partition := range device.Partitions {
+			partition.UnexpectedReadOnly = false
+			for _, mount := range partition.Mounts {
+				if mount.ReadOnly && s.expectedRW[mount.MountPoint] {
+					partition.UnexpectedReadOnly = true
+				}
+			}
+			device.Partitions[key] = partition
+		}
+	}
+	return devices, nil
+}
+
+// Samples expresses every mounted partition as a metrics sample, and the
+// state of every md array as another
+func (s *StorageService) Samples() ([]domain.Sample, error) {
+	// Health has samples of its own, and smartctl isn't worth running here
+	devices, err := track(s.recorder, "storage", s.getDevices)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []domain.Sample
+	for _, device := range devices {
+		for _, partition := range device.Partitions {
+			if partition.MountPoint == "" {
+				continue
+			}
+			samples = append(samples, domain.Sample{
+				Measurement: "storage",
+				Tags: map[string]string{
+					"device":     device.Name,
+					"partition":  partition.Name,
+					"mountpoint": partition.MountPoint,
+				},
+				Fields: map[string]any{
+					"total": partition.Total,
+					"used":  partition.Used,
+					"free":  partition.Free,
+
+					"read_only":            partition.ReadOnly,
+					"unexpected_read_only": partition.UnexpectedReadOnly,
+				},
+			})
+		}
+	}
+	for _, device := range devices {
+		if device.RAID == nil {
+			continue
+		}
+		fields := map[string]any{
+			"degraded":       device.RAID.Degraded,
+			"devices":        int64(device.RAID.Devices),
+			"active_devices": int64(device.RAID.ActiveDevices),
+			"faulty_devices": int64(countFaulty(device.RAID.Members)),
+		}
+		if device.RAID.SyncProgress != nil {
+			fields["sync_progress"] = *device.RAID.SyncProgress
+		}
+		samples = append(samples, domain.Sample{
+			Measurement: "raid",
+			Tags: map[string]string{
+				"device": device.Name,
+				"level":  device.RAID.Level,
+				"state":  device.RAID.State,
+			},
+			Fields: fields,
+		})
+	}
+	return samples, nil
+}
+
+func countFaulty(members []domain.RAIDMember) int {
+	faulty := 0
+	for _, member := range members {
+		if member.Faulty {
+			faulty++
+		}
+	}
+	return faulty
 }
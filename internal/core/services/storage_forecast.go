@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+/* ******************************************** AUX ******************************************** */
+
+const (
+	// Fewer samples, or a shorter span, don't tell a trend from noise
+	minForecastSamples = 3
+	minForecastSpan    = time.Hour
+	// Bounds the pairs of the regression to about 20k
+	maxRegressionPoints = 200
+	// Beyond that, the partition is considered not to be filling up
+	maxForecastHorizon = 100 * 365 * 24 * time.Hour
+)
+
+type usagePoint struct {
+	at   time.Time
+	used uint64
+}
+
+// usageHistory is the window of samples of the partition at a mount point
+type usageHistory struct {
+	partition string
+	total     uint64
+	free      uint64
+	points    []usagePoint
+}
+
+// thinPoints keeps at most limit points, evenly spread and always including
+// the oldest and the latest ones
+func thinPoints(points []usagePoint, limit int) []usagePoint {
+	if len(points) <= limit {
+		return points
+	}
+	thinned := make([]usagePoint, 0, limit)
+	for i := 0; i < limit; i++ {
+		thinned = append(thinned, points[i*(len(points)-1)/(limit-1)])
+	}
+	return thinned
+}
+
+// theilSenSlope is the median of the slopes between every pair of points, in
+// bytes per day. Unlike least squares, a few outliers, like a log rotation
+// or a temporary file, barely move it.
+func theilSenSlope(points []usagePoint) float64 {
+	points = thinPoints(points, maxRegressionPoints)
+
+	slopes := make([]float64, 0, len(points)*(len(points)-1)/2)
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			days := points[j].at.Sub(points[i].at).Hours() / 24
+			if days <= 0 {
+				continue
+			}
+			slopes = append(slopes, (float64(points[j].used)-float64(points[i].used))/days)
+		}
+	}
+	if len(slopes) == 0 {
+		return 0
+	}
+
+	slices.Sort(slopes)
+	middle := len(slopes) / 2
+	if len(slopes)%2 == 0 {
+		return (slopes[middle-1] + slopes[middle]) / 2
+	}
+	return slopes[middle]
+}
+
+/* ******************************************** FORECAST ******************************************** */
+
+// StorageForecastService provides business logic related to the fill trend of partitions.
+// Acts as a middleman between the core domain model (UsageForecast) and the outside
+type StorageForecastService struct {
+	storagePort ports.StoragePort
+	window      time.Duration
+	recorder    ports.RunRecorderPort
+	now         func() time.Time
+
+	mutex     sync.Mutex
+	histories map[string]*usageHistory // Keyed by mount point
+}
+
+// Service constructor. Forecasts are fitted over the samples of the last
+// window, taken by Run or Record.
+func NewStorageForecastService(storagePort ports.StoragePort, window time.Duration) *StorageForecastService {
+	return &StorageForecastService{
+		storagePort: storagePort,
+		window:      window,
+		now:         time.Now,
+		histories:   make(map[string]*usageHistory),
+	}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *StorageForecastService) WithRecorder(recorder ports.RunRecorderPort) *StorageForecastService {
+	s.recorder = recorder
+	return s
+}
+
+// Record samples the usage of every mounted partition, dropping the samples
+// that fell out of the window
+func (s *StorageForecastService) Record() error {
+	devices, err := s.storagePort.GetDevices()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	for _, device := range devices {
+		for _, partition := range device.Partitions {
+			if partition.MountPoint == "" || partition.Total == 0 {
+				continue
+			}
+
+			history, exists := s.histories[partition.MountPoint]
+			// Another partition mounted there has nothing to do with the old one
+			if !exists || history.partition != partition.Name {
+				history = &usageHistory{partition: partition.Name}
+				s.histories[partition.MountPoint] = history
+			}
+			history.total = partition.Total
+			history.free = partition.Free
+			history.points = append(history.points, usagePoint{at: now, used: partition.Used})
+		}
+	}
+
+	oldest := now.Add(-s.window)
+	for mountPoint, history := range s.histories {
+		kept := slices.IndexFunc(history.points, func(point usagePoint) bool { return !point.at.Before(oldest) })
+		if kept < 0 {
+			delete(s.histories, mountPoint)
+			continue
+		}
+		history.points = history.points[kept:]
+	}
+	return nil
+}
+
+// Run records the usage right away and then every interval, until the
+// context is cancelled
+func (s *StorageForecastService) Run(ctx context.Context, interval time.Duration) {
+	if err := s.Record(); err != nil {
+		log.Printf("Error sampling storage usage: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Record(); err != nil {
+				log.Printf("Error sampling storage usage: %v", err)
+			}
+		}
+	}
+}
+
+// Business logic to get the forecast of every mount point sampled within
+// the window, sorted by mount point
+func (s *StorageForecastService) GetForecasts() ([]domain.UsageForecast, error) {
+	return track(s.recorder, "storage_forecast", func() ([]domain.UsageForecast, error) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		forecasts := make([]domain.UsageForecast, 0, len(s.histories))
+		for mountPoint, history := range s.histories {
+			forecasts = append(forecasts, forecastUsage(mountPoint, history))
+		}
+		sort.Slice(forecasts, func(i, j int) bool {
+			return forecasts[i].MountPoint < forecasts[j].MountPoint
+		})
+		return forecasts, nil
+	})
+}
+
+func forecastUsage(mountPoint string, history *usageHistory) domain.UsageForecast {
+	first := history.points[0]
+	latest := history.points[len(history.points)-1]
+	span := latest.at.Sub(first.at)
+
+	result := domain.UsageForecast{
+		MountPoint:    mountPoint,
+		Partition:     history.partition,
+		Total:         history.total,
+		Used:          latest.used,
+		Free:          history.free,
+		Samples:       len(history.points),
+		WindowSeconds: span.Seconds(),
+	}
+	if len(history.points) < minForecastSamples || span < minForecastSpan {
+		return result
+	}
+
+	rate := theilSenSlope(history.points)
+	result.FillRate = &rate
+	if rate <= 0 {
+		return result
+	}
+
+	// Free is the space left to unprivileged users, what df reports
+	untilFull := float64(history.free) / rate * 24 * float64(time.Hour)
+	if untilFull > float64(maxForecastHorizon) {
+		return result
+	}
+	seconds := untilFull / float64(time.Second)
+	full := latest.at.Add(time.Duration(untilFull))
+	result.SecondsUntilFull = &seconds
+	result.EstimatedFull = &full
+	return result
+}
+
+// Samples expresses the forecast of every mount point as a sample. Those
+// without enough samples yet are left out.
+func (s *StorageForecastService) Samples() ([]domain.Sample, error) {
+	forecasts, err := s.GetForecasts()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(forecasts))
+	for _, forecast := range forecasts {
+		if forecast.FillRate == nil {
+			continue
+		}
+		fields := map[string]any{
+			"fill_rate": *forecast.FillRate,
+			"samples":   forecast.Samples,
+		}
+		if forecast.SecondsUntilFull != nil {
+			fields["seconds_until_full"] = *forecast.SecondsUntilFull
+		}
+
+		samples = append(samples, domain.Sample{
+			Measurement: "storage_forecast",
+			Tags: map[string]string{
+				"mountpoint": forecast.MountPoint,
+				"partition":  forecast.Partition,
+			},
+			Fields: fields,
+		})
+	}
+	return samples, nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gigabyte = 1 << 30
+
+func rootDevice(partition string, used, free uint64) []domain.Device {
+	return []domain.Device{
+		{
+			Name: "mmcblk0",
+			Partitions: map[string]domain.Partition{
+				"/": {Name: partition, MountPoint: "/", Total: used + free, Used: used, Free: free},
+				// Not mounted, never forecasted
+				"": {Name: "mmcblk0p3", Total: 1000},
+			},
+		},
+	}
+}
+
+func TestGetStorageForecastValues(t *testing.T) {
+
+	mockPort := &mockStoragePort{}
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	svc := NewStorageForecastService(mockPort, 7*24*time.Hour)
+	svc.now = func() time.Time { return now }
+
+	// A gigabyte a day, sampled every hour for two days, with a log rotation
+	// freeing 5 GB for a few hours
+	var used uint64 = 10 * gigabyte
+	for hour := 0; hour <= 48; hour++ {
+		now = start.Add(time.Duration(hour) * time.Hour)
+		sampled := used + uint64(hour)*gigabyte/24
+		if hour >= 20 && hour < 24 {
+			sampled -= 5 * gigabyte
+		}
+		mockPort.mockResult = rootDevice("mmcblk0p2", sampled, 50*gigabyte-sampled)
+		assert.NoError(t, svc.Record())
+	}
+
+	result, err := svc.GetForecasts()
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	forecast := result[0]
+	assert.Equal(t, "/", forecast.MountPoint)
+	assert.Equal(t, "mmcblk0p2", forecast.Partition)
+	assert.Equal(t, 49, forecast.Samples)
+	assert.Equal(t, float64(48*3600), forecast.WindowSeconds)
+	assert.Equal(t, uint64(12*gigabyte), forecast.Used)
+	assert.InDelta(t, float64(gigabyte), *forecast.FillRate, float64(gigabyte)/100)
+	// 38 GB left at a gigabyte a day
+	assert.InDelta(t, 38*24*3600, *forecast.SecondsUntilFull, 3600)
+	assert.WithinDuration(t, now.Add(38*24*time.Hour), *forecast.EstimatedFull, time.Hour)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 1)
+	assert.Equal(t, "storage_forecast", samples[0].Measurement)
+	assert.Equal(t, map[string]string{"mountpoint": "/", "partition": "mmcblk0p2"}, samples[0].Tags)
+	assert.Equal(t, 49, samples[0].Fields["samples"])
+	assert.Contains(t, samples[0].Fields, "seconds_until_full")
+}
+
+func TestGetStorageForecastWindow(t *testing.T) {
+
+	mockPort := &mockStoragePort{}
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	svc := NewStorageForecastService(mockPort, 24*time.Hour)
+	svc.now = func() time.Time { return now }
+
+	testBattery := []struct {
+		name      string
+		after     time.Duration
+		partition string
+		used      uint64
+		samples   int
+		filling   bool
+	}{
+		{"Case 1 - First sample", 0, "mmcblk0p2", 10 * gigabyte, 1, false},
+		{"Case 2 - Too short to tell", 30 * time.Minute, "mmcblk0p2", 11 * gigabyte, 2, false},
+		{"Case 3 - Freeing space", 12 * time.Hour, "mmcblk0p2", 9 * gigabyte, 3, false},
+		{"Case 4 - Oldest samples out of the window", 25 * time.Hour, "mmcblk0p2", 8 * gigabyte, 2, false},
+		{"Case 5 - Another partition mounted", 26 * time.Hour, "sda1", 1 * gigabyte, 1, false},
+	}
+
+	for _, tc := range testBattery {
+		t.Log(tc.name)
+		now = start.Add(tc.after)
+		mockPort.mockResult = rootDevice(tc.partition, tc.used, 20*gigabyte)
+		assert.NoError(t, svc.Record())
+
+		result, err := svc.GetForecasts()
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, tc.partition, result[0].Partition)
+		assert.Equal(t, tc.samples, result[0].Samples)
+		assert.Equal(t, tc.filling, result[0].SecondsUntilFull != nil)
+	}
+
+	// Mount points not seen within the window are forgotten
+	mockPort.mockResult = nil
+	now = start.Add(60 * time.Hour)
+	assert.NoError(t, svc.Record())
+	result, err := svc.GetForecasts()
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestGetStorageForecastFreeing(t *testing.T) {
+
+	mockPort := &mockStoragePort{}
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	svc := NewStorageForecastService(mockPort, 24*time.Hour)
+	svc.now = func() time.Time { return now }
+
+	for hour := 0; hour < 4; hour++ {
+		now = start.Add(time.Duration(hour) * time.Hour)
+		mockPort.mockResult = rootDevice("mmcblk0p2", uint64(10-hour)*gigabyte, 20*gigabyte)
+		assert.NoError(t, svc.Record())
+	}
+
+	result, err := svc.GetForecasts()
+	assert.NoError(t, err)
+	assert.InDelta(t, -24*float64(gigabyte), *result[0].FillRate, 1)
+	assert.Nil(t, result[0].SecondsUntilFull)
+	assert.Nil(t, result[0].EstimatedFull)
+}
+
+func TestGetStorageForecastSimulateError(t *testing.T) {
+
+	mockPort := &mockStoragePort{
+		mockError: errors.New("mock error"),
+	}
+
+	svc := NewStorageForecastService(mockPort, time.Hour)
+
+	err := svc.Record()
+	assert.Error(t, err)
+	assert.Equal(t, "mock error", err.Error())
+
+	result, err := svc.GetForecasts()
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// StorageHealthService provides business logic related to the wear of the
+// storage devices.
+// Acts as a middleman between the core domain model (DeviceHealth) and the outside
+type StorageHealthService struct {
+	storageHealthPort ports.StorageHealthPort
+	recorder          ports.RunRecorderPort
+}
+
+// Service constructor
+func NewStorageHealthService(storageHealthPort ports.StorageHealthPort) *StorageHealthService {
+	return &StorageHealthService{storageHealthPort: storageHealthPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *StorageHealthService) WithRecorder(recorder ports.RunRecorderPort) *StorageHealthService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the health of the storage devices
+func (s *StorageHealthService) GetDevicesHealth() ([]domain.DeviceHealth, error) {
+	return track(s.recorder, "storage_health", s.storageHealthPort.GetDevicesHealth)
+}
+
+// Samples expresses the health of every device reporting any measure as a
+// sample
+func (s *StorageHealthService) Samples() ([]domain.Sample, error) {
+	devices, err := s.GetDevicesHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := []domain.Sample{}
+	for _, device := range devices {
+		fields := map[string]any{}
+		if device.WearPercent != nil {
+			fields["wear_percent"] = int64(*device.WearPercent)
+		}
+		if device.PreEOL != "" {
+			fields["pre_eol"] = device.PreEOL
+		}
+		if device.SmartPassed != nil {
+			fields["smart_passed"] = *device.SmartPassed
+		}
+		if device.Temperature != nil {
+			fields["temperature"] = *device.Temperature
+		}
+		if device.ReallocatedSectors != nil {
+			fields["reallocated_sectors"] = *device.ReallocatedSectors
+		}
+		if device.PowerOnHours != nil {
+			fields["power_on_hours"] = *device.PowerOnHours
+		}
+		// SD cards don't report their wear, and a sample needs a field
+		if len(fields) == 0 {
+			continue
+		}
+
+		samples = append(samples, domain.Sample{
+			Measurement: "storage_health",
+			Tags: map[string]string{
+				"device": device.Device,
+				"type":   device.Type,
+				"model":  device.Model,
+			},
+			Fields: fields,
+		})
+	}
+	return samples, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStorageHealthPort struct {
+	mockResult []domain.DeviceHealth
+	mockError  error
+	calls      int
+}
+
+func (m *mockStorageHealthPort) GetDevicesHealth() ([]domain.DeviceHealth, error) {
+	m.calls++
+	return m.mockResult, m.mockError
+}
+
+func TestGetDevicesHealthValues(t *testing.T) {
+
+	wear := 20
+	passed := true
+	reallocated := uint64(2)
+	mockPort := &mockStorageHealthPort{
+		mockResult: []domain.DeviceHealth{
+			{Device: "mmcblk0", Type: "MMC", Source: "sysfs", Model: "DG4016", WearPercent: &wear, PreEOL: "normal"},
+			{Device: "mmcblk1", Type: "SD", Source: "sysfs", Model: "SD32G"},
+			{Device: "sda", Type: "ATA", Source: "smartctl", Model: "Samsung SSD 870 EVO 500GB", SmartPassed: &passed, ReallocatedSectors: &reallocated},
+		},
+	}
+
+	svc := NewStorageHealthService(mockPort)
+
+	result, err := svc.GetDevicesHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, mockPort.mockResult, result)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+
+	// The SD card reports no measure
+	assert.Len(t, samples, 2)
+	assert.Equal(t, "storage_health", samples[0].Measurement)
+	assert.Equal(t, map[string]string{"device": "mmcblk0", "type": "MMC", "model": "DG4016"}, samples[0].Tags)
+	assert.Equal(t, map[string]any{"wear_percent": int64(20), "pre_eol": "normal"}, samples[0].Fields)
+	assert.Equal(t, map[string]any{"smart_passed": true, "reallocated_sectors": uint64(2)}, samples[1].Fields)
+}
+
+func TestGetDevicesHealthSimulateError(t *testing.T) {
+
+	mockPort := &mockStorageHealthPort{
+		mockError: errors.New("block devices not found"),
+	}
+
+	svc := NewStorageHealthService(mockPort)
+
+	_, err := svc.GetDevicesHealth()
+	assert.Error(t, err)
+	assert.Equal(t, "block devices not found", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
 
@@ -59,7 +60,7 @@ func TestGetStorageDevices(t *testing.T) {
 		},
 		mockError: nil,
 	}
-	svc := NewStorageService(mockPort)
+	svc := NewStorageService(mockPort, nil)
 
 	// Call the service method
 	result, err := svc.GetDevices()
@@ -106,10 +107,141 @@ func TestGetStorageInfoSimulateError(t *testing.T) {
 		mockError:  errors.New("unable to read Storage info"),
 	}
 
-	svc := NewStorageService(mockPort)
+	svc := NewStorageService(mockPort, nil)
 
 	_, err := svc.GetDevices()
 
 	assert.Error(t, err)
 	assert.Equal(t, "unable to read Storage info", err.Error())
 }
+
+func TestGetStorageUnexpectedReadOnly(t *testing.T) {
+
+	mockPort := &mockStoragePort{
+		mockResult: []domain.Device{
+			{
+				Name: "mmcblk0",
+				Partitions: map[string]domain.Partition{
+					"mmcblk0p1": {
+						Name: "mmcblk0p1", MountPoint: "/boot/firmware", ReadOnly: true,
+						Mounts: []domain.Mount{{MountPoint: "/boot/firmware", Filesystem: "vfat", Options: []string{"ro"}, ReadOnly: true}},
+					},
+					"mmcblk0p2": {
+						Name: "mmcblk0p2", MountPoint: "/", ReadOnly: true,
+						Mounts: []domain.Mount{{MountPoint: "/", Filesystem: "ext4", Options: []string{"ro", "noatime"}, ReadOnly: true}},
+					},
+				},
+			},
+			{
+				Name: "sda",
+				Partitions: map[string]domain.Partition{
+					"sda1": {
+						Name: "sda1", MountPoint: "/mnt/data",
+						Mounts: []domain.Mount{
+							{MountPoint: "/mnt/data", Filesystem: "ext4", Options: []string{"rw"}},
+							{MountPoint: "/var/log/archive", Filesystem: "ext4", Options: []string{"ro", "bind"}, ReadOnly: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	svc := NewStorageService(mockPort, []string{"/", "/var/log/archive"})
+
+	result, err := svc.GetDevices()
+	assert.NoError(t, err)
+
+	// Only the read-only mounts expected to be read-write are flagged
+	assert.False(t, result[0].Partitions["mmcblk0p1"].UnexpectedReadOnly)
+	assert.True(t, result[0].Partitions["mmcblk0p2"].UnexpectedReadOnly)
+	assert.True(t, result[1].Partitions["sda1"].UnexpectedReadOnly)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+	for _, sample := range samples {
+		if sample.Tags["partition"] == "mmcblk0p2" {
+			assert.Equal(t, true, sample.Fields["read_only"])
+			assert.Equal(t, true, sample.Fields["unexpected_read_only"])
+		}
+	}
+}
+
+func TestGetStorageRAIDSamples(t *testing.T) {
+
+	progress := 12.6
+	mockPort := &mockStoragePort{
+		mockResult: []domain.Device{
+			{Name: "sda", Partitions: map[string]domain.Partition{"sda1": {Name: "sda1"}}, Holders: []string{"md0"}},
+			{
+				Name: "md0", Partitions: map[string]domain.Partition{"md0": {Name: "md0"}},
+				Slaves: []string{"sda1", "sdb1"},
+				RAID: &domain.RAIDStatus{
+					Level: "raid1", State: "active", Devices: 2, ActiveDevices: 1, Degraded: true,
+					Members:    []domain.RAIDMember{{Name: "sdb1", Role: 1, Faulty: true}, {Name: "sda1", Role: 0}},
+					SyncAction: "recovery", SyncProgress: &progress,
+				},
+			},
+		},
+	}
+
+	svc := NewStorageService(mockPort, nil)
+
+	// Neither partition is mounted, so only the array is sampled
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 1)
+	assert.Equal(t, "raid", samples[0].Measurement)
+	assert.Equal(t, map[string]string{"device": "md0", "level": "raid1", "state": "active"}, samples[0].Tags)
+	assert.Equal(t, map[string]any{
+		"degraded": true, "devices": int64(2), "active_devices": int64(1), "faulty_devices": int64(1), "sync_progress": 12.6,
+	}, samples[0].Fields)
+}
+
+func TestGetStorageDevicesHealth(t *testing.T) {
+
+	mockPort := &mockStoragePort{
+		mockResult: []domain.Device{
+			{Name: "mmcblk0", Partitions: map[string]domain.Partition{"mmcblk0p2": {Name: "mmcblk0p2"}}},
+			{Name: "nvme0", Partitions: map[string]domain.Partition{"nvme0n1p1": {Name: "nvme0n1p1"}}},
+			{Name: "dm-0", Partitions: map[string]domain.Partition{"dm-0": {Name: "dm-0"}}},
+		},
+	}
+	wear := 20
+	healthPort := &mockStorageHealthPort{
+		mockResult: []domain.DeviceHealth{
+			{Device: "mmcblk0", Type: "MMC", Source: "sysfs", WearPercent: &wear},
+			{Device: "nvme0", Type: "NVMe", Source: "smartctl"},
+		},
+	}
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	svc := NewStorageService(mockPort, nil).WithHealth(healthPort, 10*time.Minute)
+	svc.now = func() time.Time { return at }
+
+	// Stacked devices have no health of their own
+	result, err := svc.GetDevices()
+	assert.NoError(t, err)
+	assert.Equal(t, &healthPort.mockResult[0], result[0].Health)
+	assert.Equal(t, "NVMe", result[1].Health.Type)
+	assert.Nil(t, result[2].Health)
+
+	// Health is cached until it expires, samples never read it
+	at = at.Add(9 * time.Minute)
+	_, err = svc.GetDevices()
+	assert.NoError(t, err)
+	_, err = svc.Samples()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, healthPort.calls)
+
+	// A failed read keeps the previous health, without retrying meanwhile
+	healthPort.mockError = errors.New("block devices not found")
+	at = at.Add(time.Minute)
+	result, err = svc.GetDevices()
+	assert.NoError(t, err)
+	assert.Equal(t, "NVMe", result[1].Health.Type)
+	_, err = svc.GetDevices()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, healthPort.calls)
+}
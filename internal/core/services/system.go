@@ -0,0 +1,48 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// SystemService provides business logic related to the system identity.
+// Acts as a middleman between the core domain model (System) and the outside
+type SystemService struct {
+	systemPort ports.SystemPort
+	recorder   ports.RunRecorderPort
+}
+
+// Service constructor
+func NewSystemService(systemPort ports.SystemPort) *SystemService {
+	return &SystemService{systemPort: systemPort}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *SystemService) WithRecorder(recorder ports.RunRecorderPort) *SystemService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the system identity
+func (s *SystemService) GetSystemInfo() (domain.System, error) {
+	return track(s.recorder, "system", s.systemPort.GetSystemInfo)
+}
+
+// Samples expresses the uptime as a metrics sample
+func (s *SystemService) Samples() ([]domain.Sample, error) {
+	system, err := s.GetSystemInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.Sample{{
+		Measurement: "system",
+		Tags: map[string]string{
+			"kernel": system.Kernel.Release,
+			"os":     system.OS.PrettyName,
+		},
+		Fields: map[string]any{
+			"uptime": system.UptimeSeconds,
+		},
+	}}, nil
+}
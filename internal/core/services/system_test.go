@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSystemPort struct {
+	mockResult domain.System
+	mockError  error
+}
+
+func (m *mockSystemPort) GetSystemInfo() (domain.System, error) {
+	return m.mockResult, m.mockError
+}
+
+func TestGetSystemInfoValues(t *testing.T) {
+
+	mockPort := &mockSystemPort{
+		mockResult: domain.System{
+			Hostname:      "raspberrypi",
+			Kernel:        domain.Kernel{Name: "Linux", Release: "6.6.31+rpt-rpi-v8"},
+			OS:            domain.OSRelease{ID: "debian", PrettyName: "Debian GNU/Linux 12 (bookworm)"},
+			UptimeSeconds: 3600,
+		},
+	}
+
+	svc := NewSystemService(mockPort)
+
+	result, err := svc.GetSystemInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "raspberrypi", result.Hostname)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 1)
+	assert.Equal(t, "system", samples[0].Measurement)
+	assert.Equal(t, "6.6.31+rpt-rpi-v8", samples[0].Tags["kernel"])
+	assert.Equal(t, 3600.0, samples[0].Fields["uptime"])
+}
+
+func TestGetSystemInfoSimulateError(t *testing.T) {
+
+	mockPort := &mockSystemPort{
+		mockResult: domain.System{},
+		mockError:  errors.New("unable to read uptime"),
+	}
+
+	svc := NewSystemService(mockPort)
+
+	_, err := svc.GetSystemInfo()
+	assert.Error(t, err)
+	assert.Equal(t, "unable to read uptime", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// SystemdService provides business logic related to the systemd units.
+// Acts as a middleman between the core domain model (Services) and the outside
+type SystemdService struct {
+	systemdPort ports.SystemdPort
+	units       []string
+	recorder    ports.RunRecorderPort
+}
+
+// Service constructor. units are the ones always reported, failed or not.
+func NewSystemdService(systemdPort ports.SystemdPort, units []string) *SystemdService {
+	return &SystemdService{systemdPort: systemdPort, units: units}
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *SystemdService) WithRecorder(recorder ports.RunRecorderPort) *SystemdService {
+	s.recorder = recorder
+	return s
+}
+
+// Business logic to get the configured units and the failed ones
+func (s *SystemdService) GetServices() (domain.Services, error) {
+	return track(s.recorder, "services", func() (domain.Services, error) {
+		units, err := s.systemdPort.GetUnits(s.units)
+		if err != nil {
+			return domain.Services{}, err
+		}
+		failed, err := s.systemdPort.GetFailedUnits()
+		if err != nil {
+			return domain.Services{}, err
+		}
+		return domain.Services{Units: units, Failed: failed}, nil
+	})
+}
+
+// Samples expresses the state of every configured unit as a sample, and how
+// many units failed as another
+func (s *SystemdService) Samples() ([]domain.Sample, error) {
+	services, err := s.GetServices()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(services.Units)+1)
+	for _, unit := range services.Units {
+		samples = append(samples, domain.Sample{
+			Measurement: "systemd_unit",
+			Tags: map[string]string{
+				"unit": unit.Name,
+			},
+			Fields: map[string]any{
+				"active":       unit.ActiveState == "active",
+				"active_state": unit.ActiveState,
+				"sub_state":    unit.SubState,
+				"restarts":     int64(unit.Restarts),
+			},
+		})
+	}
+	samples = append(samples, domain.Sample{
+		Measurement: "systemd",
+		Fields: map[string]any{
+			"failed_units": int64(len(services.Failed)),
+		},
+	})
+	return samples, nil
+}
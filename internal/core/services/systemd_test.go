@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSystemdPort struct {
+	mockUnits  []domain.SystemdUnit
+	mockFailed []domain.SystemdUnit
+	mockError  error
+	askedUnits []string
+}
+
+func (m *mockSystemdPort) GetUnits(names []string) ([]domain.SystemdUnit, error) {
+	m.askedUnits = names
+	return m.mockUnits, m.mockError
+}
+
+func (m *mockSystemdPort) GetFailedUnits() ([]domain.SystemdUnit, error) {
+	return m.mockFailed, m.mockError
+}
+
+func TestGetServicesValues(t *testing.T) {
+
+	mockPort := &mockSystemdPort{
+		mockUnits: []domain.SystemdUnit{
+			{Name: "ssh.service", ActiveState: "active", SubState: "running"},
+			{Name: "myapp.service", ActiveState: "activating", SubState: "auto-restart", Restarts: 7},
+		},
+		mockFailed: []domain.SystemdUnit{
+			{Name: "hciuart.service", ActiveState: "failed", SubState: "failed"},
+		},
+	}
+
+	svc := NewSystemdService(mockPort, []string{"ssh", "myapp"})
+
+	result, err := svc.GetServices()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ssh", "myapp"}, mockPort.askedUnits)
+	assert.Len(t, result.Units, 2)
+	assert.Len(t, result.Failed, 1)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+	assert.Equal(t, "systemd_unit", samples[0].Measurement)
+	assert.Equal(t, "ssh.service", samples[0].Tags["unit"])
+	assert.Equal(t, true, samples[0].Fields["active"])
+	assert.Equal(t, false, samples[1].Fields["active"])
+	assert.Equal(t, int64(7), samples[1].Fields["restarts"])
+	assert.Equal(t, "systemd", samples[2].Measurement)
+	assert.Equal(t, int64(1), samples[2].Fields["failed_units"])
+}
+
+func TestGetServicesSimulateError(t *testing.T) {
+
+	mockPort := &mockSystemdPort{
+		mockError: errors.New("systemctl not found"),
+	}
+
+	svc := NewSystemdService(mockPort, []string{"ssh"})
+
+	_, err := svc.GetServices()
+	assert.Error(t, err)
+	assert.Equal(t, "systemctl not found", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}
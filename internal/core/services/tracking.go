@@ -0,0 +1,19 @@
+package services
+
+import (
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// track runs fn, reporting its duration and outcome to the recorder if any
+func track[T any](recorder ports.RunRecorderPort, name string, fn func() (T, error)) (T, error) {
+	if recorder == nil {
+		return fn()
+	}
+
+	start := time.Now()
+	result, err := fn()
+	recorder.Record(name, start, err)
+	return result, err
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+	"github.com/alvmarrod/pi-monitor-api/internal/core/ports"
+)
+
+// watchState is what is remembered of a watched process between checks
+type watchState struct {
+	pid      int
+	restarts int
+}
+
+// WatchService checks whether the configured processes are running, counting
+// their restarts as changes of their PID. Checks run in the background, so
+// that restarts are detected whether the API is polled or not.
+type WatchService struct {
+	processPort ports.ProcessPort
+	pidfilePort ports.PidfilePort
+	rules       []domain.WatchRule
+	cmdlines    map[string]*regexp.Regexp
+	recorder    ports.RunRecorderPort
+	now         func() time.Time
+
+	mu        sync.Mutex
+	states    map[string]*watchState
+	checked   bool
+	latest    []domain.WatchedProcess
+	latestErr error
+}
+
+// Service constructor. Fails when a cmdline rule isn't a valid expression.
+func NewWatchService(processPort ports.ProcessPort, pidfilePort ports.PidfilePort, rules []domain.WatchRule) (*WatchService, error) {
+	cmdlines := map[string]*regexp.Regexp{}
+	for _, rule := range rules {
+		if rule.Pidfile != "" || rule.Process != "" || rule.Cmdline == "" {
+			continue
+		}
+		expression, err := regexp.Compile(rule.Cmdline)
+		if err != nil {
+			return nil, fmt.Errorf("watch %s: %w", rule.Name, err)
+		}
+		cmdlines[rule.Name] = expression
+	}
+
+	return &WatchService{
+		processPort: processPort,
+		pidfilePort: pidfilePort,
+		rules:       rules,
+		cmdlines:    cmdlines,
+		now:         time.Now,
+		states:      make(map[string]*watchState),
+	}, nil
+}
+
+// WithRecorder reports every run of the service to the recorder
+func (s *WatchService) WithRecorder(recorder ports.RunRecorderPort) *WatchService {
+	s.recorder = recorder
+	return s
+}
+
+// Check looks for the watched processes once, keeping the result for the
+// requests until the next check
+func (s *WatchService) Check() {
+	watched, err := s.check()
+	if err != nil {
+		log.Printf("Error checking watched processes: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checked = true
+	s.latest = watched
+	s.latestErr = err
+}
+
+// Run checks the processes right away and then every interval, until the
+// context is cancelled
+func (s *WatchService) Run(ctx context.Context, interval time.Duration) {
+	if len(s.rules) == 0 {
+		return
+	}
+	s.Check()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Check()
+		}
+	}
+}
+
+// Business logic to get the latest check of the watched processes, in the
+// configured order. They are checked right away when Run hasn't yet.
+func (s *WatchService) GetWatchedProcesses() ([]domain.WatchedProcess, error) {
+	return track(s.recorder, "watch", func() ([]domain.WatchedProcess, error) {
+		s.mu.Lock()
+		checked := s.checked
+		s.mu.Unlock()
+		if !checked {
+			s.Check()
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.latestErr != nil {
+			return nil, s.latestErr
+		}
+
+		// Uptimes go on between checks
+		now := s.now()
+		watched := make([]domain.WatchedProcess, len(s.latest))
+		copy(watched, s.latest)
+		for i := range watched {
+			if watched[i].Up && !watched[i].StartTime.IsZero() {
+				watched[i].UptimeSeconds = now.Sub(watched[i].StartTime).Seconds()
+			}
+		}
+		return watched, nil
+	})
+}
+
+func (s *WatchService) check() ([]domain.WatchedProcess, error) {
+	if len(s.rules) == 0 {
+		return []domain.WatchedProcess{}, nil
+	}
+
+	processes, err := s.processPort.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watched := make([]domain.WatchedProcess, 0, len(s.rules))
+	for _, rule := range s.rules {
+		state, exists := s.states[rule.Name]
+		if !exists {
+			state = &watchState{}
+			s.states[rule.Name] = state
+		}
+
+		result := domain.WatchedProcess{Name: rule.Name}
+		matches := s.match(rule, processes)
+		result.Matches = len(matches)
+
+		if len(matches) > 0 {
+			process := oldestProcess(matches)
+			if state.pid != 0 && state.pid != process.PID {
+				state.restarts++
+			}
+			state.pid = process.PID
+
+			result.Up = true
+			result.StartTime = process.StartTime
+			if !process.StartTime.IsZero() {
+				result.UptimeSeconds = now.Sub(process.StartTime).Seconds()
+			}
+			result.CPUPercent = process.CPUPercent
+			result.RSS = process.RSS
+		}
+		result.PID = state.pid
+		result.Restarts = state.restarts
+
+		watched = append(watched, result)
+	}
+	return watched, nil
+}
+
+func (s *WatchService) match(rule domain.WatchRule, processes []domain.Process) []domain.Process {
+	matches := []domain.Process{}
+	switch {
+	case rule.Pidfile != "":
+		// A missing pidfile means the process is down
+		pid, err := s.pidfilePort.ReadPidfile(rule.Pidfile)
+		if err != nil {
+			return matches
+		}
+		for _, process := range processes {
+			if process.PID == pid {
+				matches = append(matches, process)
+			}
+		}
+	case rule.Process != "":
+		for _, process := range processes {
+			if process.Name == rule.Process {
+				matches = append(matches, process)
+			}
+		}
+	case s.cmdlines[rule.Name] != nil:
+		for _, process := range processes {
+			if process.Command != "" && s.cmdlines[rule.Name].MatchString(process.Command) {
+				matches = append(matches, process)
+			}
+		}
+	}
+	return matches
+}
+
+// oldestProcess picks the process started first, usually the parent of the
+// rest, or the lowest PID when they can't be told apart
+func oldestProcess(processes []domain.Process) domain.Process {
+	oldest := processes[0]
+	for _, process := range processes[1:] {
+		if process.StartTime.Before(oldest.StartTime) ||
+			(process.StartTime.Equal(oldest.StartTime) && process.PID < oldest.PID) {
+			oldest = process
+		}
+	}
+	return oldest
+}
+
+// Samples expresses the liveness of every watched process as a sample
+func (s *WatchService) Samples() ([]domain.Sample, error) {
+	watched, err := s.GetWatchedProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.Sample, 0, len(watched))
+	for _, process := range watched {
+		samples = append(samples, domain.Sample{
+			Measurement: "watch",
+			Tags: map[string]string{
+				"name": process.Name,
+			},
+			Fields: map[string]any{
+				"up":          process.Up,
+				"pid":         int64(process.PID),
+				"uptime":      process.UptimeSeconds,
+				"restarts":    int64(process.Restarts),
+				"cpu_percent": process.CPUPercent,
+				"rss":         process.RSS,
+			},
+		})
+	}
+	return samples, nil
+}
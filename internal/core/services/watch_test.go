@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alvmarrod/pi-monitor-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPidfilePort struct {
+	pids map[string]int
+}
+
+func (m *mockPidfilePort) ReadPidfile(path string) (int, error) {
+	pid, exists := m.pids[path]
+	if !exists {
+		return 0, errors.New("no such file")
+	}
+	return pid, nil
+}
+
+func TestGetWatchedProcessesValues(t *testing.T) {
+
+	boot := time.Unix(1718000000, 0).UTC()
+	processPort := &mockProcessPort{
+		mockResult: []domain.Process{
+			{PID: 1, Name: "systemd", Command: "/sbin/init", StartTime: boot},
+			{PID: 600, Name: "sshd", Command: "sshd: pi@pts/0", StartTime: boot.Add(time.Hour)},
+			{PID: 500, Name: "sshd", Command: "/usr/sbin/sshd -D", StartTime: boot.Add(10 * time.Second)},
+			{PID: 512, Name: "python3", Command: "/usr/bin/python3 /home/pi/app.py", StartTime: boot.Add(time.Minute), CPUPercent: 12.5, RSS: 52 << 20},
+		},
+	}
+	pidfilePort := &mockPidfilePort{pids: map[string]int{"/run/app.pid": 512}}
+
+	rules := []domain.WatchRule{
+		{Name: "ssh", Process: "sshd"},
+		{Name: "app", Cmdline: `python3 .*app\.py`},
+		{Name: "app-pidfile", Pidfile: "/run/app.pid"},
+		{Name: "nginx", Pidfile: "/run/nginx.pid"},
+	}
+
+	registry := NewCollectorRegistry()
+	svc, err := NewWatchService(processPort, pidfilePort, rules)
+	assert.NoError(t, err)
+	svc.WithRecorder(registry)
+	svc.now = func() time.Time { return boot.Add(2 * time.Hour) }
+
+	watched, err := svc.GetWatchedProcesses()
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.WatchedProcess{
+		{Name: "ssh", Up: true, PID: 500, Matches: 2, StartTime: boot.Add(10 * time.Second), UptimeSeconds: 7190},
+		{Name: "app", Up: true, PID: 512, Matches: 1, StartTime: boot.Add(time.Minute), UptimeSeconds: 7140, CPUPercent: 12.5, RSS: 52 << 20},
+		{Name: "app-pidfile", Up: true, PID: 512, Matches: 1, StartTime: boot.Add(time.Minute), UptimeSeconds: 7140, CPUPercent: 12.5, RSS: 52 << 20},
+		{Name: "nginx", Up: false},
+	}, watched)
+	assert.Equal(t, uint64(1), registry.Stats("watch").Runs)
+
+	// The app goes down, keeping its last PID, and comes back with another one
+	processPort.mockResult = processPort.mockResult[:3]
+	svc.Check()
+	watched, err = svc.GetWatchedProcesses()
+	assert.NoError(t, err)
+	assert.False(t, watched[1].Up)
+	assert.Equal(t, 512, watched[1].PID)
+	assert.Equal(t, 0, watched[1].Restarts)
+
+	processPort.mockResult = append(processPort.mockResult,
+		domain.Process{PID: 900, Name: "python3", Command: "/usr/bin/python3 /home/pi/app.py", StartTime: boot.Add(2 * time.Hour)})
+	svc.Check()
+	watched, err = svc.GetWatchedProcesses()
+	assert.NoError(t, err)
+	assert.True(t, watched[1].Up)
+	assert.Equal(t, 900, watched[1].PID)
+	assert.Equal(t, 1, watched[1].Restarts)
+	assert.Equal(t, 0, watched[0].Restarts)
+
+	samples, err := svc.Samples()
+	assert.NoError(t, err)
+	assert.Len(t, samples, 4)
+	assert.Equal(t, "watch", samples[1].Measurement)
+	assert.Equal(t, "app", samples[1].Tags["name"])
+	assert.Equal(t, true, samples[1].Fields["up"])
+	assert.Equal(t, int64(1), samples[1].Fields["restarts"])
+	assert.Equal(t, false, samples[3].Fields["up"])
+}
+
+func TestGetWatchedProcessesBetweenRequests(t *testing.T) {
+
+	boot := time.Unix(1718000000, 0).UTC()
+	processPort := &mockProcessPort{
+		mockResult: []domain.Process{{PID: 512, Name: "python3", StartTime: boot}},
+	}
+
+	svc, err := NewWatchService(processPort, &mockPidfilePort{}, []domain.WatchRule{{Name: "app", Process: "python3"}})
+	assert.NoError(t, err)
+	now := boot.Add(time.Hour)
+	svc.now = func() time.Time { return now }
+
+	// Every restart checked is counted, however many happen between requests
+	for pid := 600; pid < 603; pid++ {
+		svc.Check()
+		processPort.mockResult = []domain.Process{{PID: pid, Name: "python3", StartTime: boot}}
+	}
+	svc.Check()
+
+	// Served from the latest check, with the uptime up to date
+	now = now.Add(time.Minute)
+	watched, err := svc.GetWatchedProcesses()
+	assert.NoError(t, err)
+	assert.Equal(t, 602, watched[0].PID)
+	assert.Equal(t, 3, watched[0].Restarts)
+	assert.Equal(t, 3660.0, watched[0].UptimeSeconds)
+}
+
+func TestGetWatchedProcessesRun(t *testing.T) {
+
+	processPort := &mockProcessPort{mockResult: []domain.Process{{PID: 512, Name: "python3"}}}
+
+	svc, err := NewWatchService(processPort, &mockPidfilePort{}, []domain.WatchRule{{Name: "app", Process: "python3"}})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.Run(ctx, time.Hour)
+	}()
+
+	// The first check runs right away
+	assert.Eventually(t, func() bool {
+		svc.mu.Lock()
+		defer svc.mu.Unlock()
+		return svc.checked
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+
+	watched, err := svc.GetWatchedProcesses()
+	assert.NoError(t, err)
+	assert.True(t, watched[0].Up)
+}
+
+func TestGetWatchedProcessesNoRules(t *testing.T) {
+
+	// Nothing is read when there is nothing to watch
+	svc, err := NewWatchService(&mockProcessPort{mockError: errors.New("unable to list /proc")}, &mockPidfilePort{}, nil)
+	assert.NoError(t, err)
+
+	watched, err := svc.GetWatchedProcesses()
+	assert.NoError(t, err)
+	assert.Empty(t, watched)
+}
+
+func TestGetWatchedProcessesSimulateError(t *testing.T) {
+
+	_, err := NewWatchService(&mockProcessPort{}, &mockPidfilePort{}, []domain.WatchRule{{Name: "app", Cmdline: "app("}})
+	assert.Error(t, err)
+
+	svc, err := NewWatchService(&mockProcessPort{mockError: errors.New("unable to list /proc")}, &mockPidfilePort{},
+		[]domain.WatchRule{{Name: "ssh", Process: "sshd"}})
+	assert.NoError(t, err)
+
+	_, err = svc.GetWatchedProcesses()
+	assert.Error(t, err)
+	assert.Equal(t, "unable to list /proc", err.Error())
+
+	_, err = svc.Samples()
+	assert.Error(t, err)
+}